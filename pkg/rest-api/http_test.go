@@ -26,6 +26,34 @@ func TestHTTPHandlerPutMethodForValidation(t *testing.T) {
 	if !strings.Contains(string(b), "validation_failed") {
 		t.Fatalf("PUT method for invalid request did not output validation_failed error text")
 	}
+	if !strings.Contains(string(b), "FirstName must be at least 2 characters long") {
+		t.Fatalf("PUT method for invalid request did not output validation message for the failed field")
+	}
+}
+
+// Test structs for validation done by struct2db controller
+type ValidationAliasTestStruct struct {
+	ID   int64
+	Name string `crud:"minlen:2"`
+}
+
+type ValidationInvalidRegexpTestStruct struct {
+	ID   int64
+	Code string `crud:"regexp:^[A-Z{3}$"`
+}
+
+// TestValidate tests if Validate uses aliases of validation rules and returns an error instead of panicking when
+// a pattern is invalid, the same as struct2db controller
+func TestValidate(t *testing.T) {
+	b, failedFields, err := ctl.Validate(&ValidationAliasTestStruct{Name: "J"}, nil)
+	if err != nil || b || len(failedFields) != 1 {
+		t.Fatalf("Validate failed to use alias of validation rule, got %v", failedFields)
+	}
+
+	_, _, err = ctl.Validate(&ValidationInvalidRegexpTestStruct{Code: "WAW"}, nil)
+	if err == nil {
+		t.Fatalf("Validate failed to return an err for invalid pattern")
+	}
 }
 
 // TestHTTPHandlerPutMethodForCreating tests if HTTP endpoint properly creates new object in the database, when PUT request is made, without object ID
@@ -63,11 +63,3 @@ func (c *Controller) initHelpers(newObjFunc func() interface{}, options HandlerO
 
 	return nil
 }
-
-func (c Controller) mapWithInterfacesToMapBool(m map[string]interface{}) map[string]bool {
-	o := map[string]bool{}
-	for k := range m {
-		o[k] = true
-	}
-	return o
-}
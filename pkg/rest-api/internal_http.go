@@ -43,11 +43,18 @@ func (c Controller) handleHTTPPut(w http.ResponseWriter, r *http.Request, newObj
 		return
 	}
 
-	b, _, err := c.Validate(objClone, nil)
-	if !b || err != nil {
+	b, failedFields, err := c.Validate(objClone, nil)
+	if err != nil {
 		c.writeErrText(w, http.StatusBadRequest, "validation_failed")
 		return
 	}
+	if !b {
+		ctx := stdb.WithLocale(r.Context(), c.getRequestLocale(r))
+		c.writeErrTextWithData(w, http.StatusBadRequest, "validation_failed", map[string]interface{}{
+			"fields": c.struct2db.ValidationErrorMessages(ctx, objClone, failedFields),
+		})
+		return
+	}
 
 	err2 := c.struct2db.Save(objClone, stdb.SaveOptions{})
 	if err2 != nil {
@@ -273,6 +280,23 @@ func (c Controller) writeErrText(w http.ResponseWriter, status int, errText stri
 	}
 }
 
+func (c Controller) writeErrTextWithData(w http.ResponseWriter, status int, errText string, data map[string]interface{}) {
+	r := NewHTTPResponse(0, errText)
+	r.Data = data
+	j, err := json.Marshal(r)
+	w.WriteHeader(status)
+	if err == nil {
+		w.Write(j)
+	}
+}
+
+// getRequestLocale returns the first language from 'Accept-Language' header, eg. 'pl-PL' for 'pl-PL,pl;q=0.9'
+func (c Controller) getRequestLocale(r *http.Request) string {
+	locale, _, _ := strings.Cut(r.Header.Get("Accept-Language"), ",")
+	locale, _, _ = strings.Cut(locale, ";")
+	return strings.TrimSpace(locale)
+}
+
 func (c Controller) writeOK(w http.ResponseWriter, status int, data map[string]interface{}) {
 	r := NewHTTPResponse(1, "")
 	r.Data = data
@@ -14,6 +14,10 @@ type Controller struct {
 
 type ControllerConfig struct {
 	TagName string
+	// ValidationMessages are catalogs of validation messages keyed by locale, eg. 'pl'. When validation of a request
+	// fails, messages for the failed fields are returned in the language from 'Accept-Language' header, and in
+	// English when there is no catalog for it
+	ValidationMessages map[string]struct2db.ValidationMessages
 }
 
 // NewController returns new Controller object
@@ -25,8 +29,14 @@ func NewController(dbConn *sql.DB, tblPrefix string, cfg *ControllerConfig) *Con
 		tagName = cfg.TagName
 	}
 
+	var validationMessages map[string]struct2db.ValidationMessages
+	if cfg != nil {
+		validationMessages = cfg.ValidationMessages
+	}
+
 	c.struct2db = struct2db.NewController(dbConn, tblPrefix, &struct2db.ControllerConfig{
-		TagName: tagName,
+		TagName:            tagName,
+		ValidationMessages: validationMessages,
 	})
 
 	return c
@@ -3,27 +3,12 @@ package restapi
 import (
 	"reflect"
 	"regexp"
-
-	validator "github.com/mikolajgs/struct-validator"
 )
 
-// Validate checks object's fields. It returns result of validation as a bool and list of fields with invalid value
+// Validate checks object's fields. It returns result of validation as a bool and list of fields with invalid value.
+// It is done by struct2db controller, with the same tag, aliases of validation rules and cached regular expressions
 func (c Controller) Validate(obj interface{}, filters map[string]interface{}) (bool, map[string]int, error) {
-	if filters != nil {
-		valid, failedFields := validator.Validate(obj, &validator.ValidationOptions{
-			OverwriteTagName:     "crud",
-			ValidateWhenSuffix:   true,
-			OverwriteFieldValues: filters,
-			RestrictFields:       c.mapWithInterfacesToMapBool(filters),
-		})
-		return valid, failedFields, nil
-	}
-
-	valid, failedFields := validator.Validate(obj, &validator.ValidationOptions{
-		OverwriteTagName:   "crud",
-		ValidateWhenSuffix: true,
-	})
-	return valid, failedFields, nil
+	return c.struct2db.Validate(obj, filters)
 }
 
 // validateFieldRequired checks if field that is required has a value
@@ -0,0 +1,36 @@
+package structdbpostgres
+
+// DefaultBatchSize is the maximum number of items (eg. IDs in IN (...) or rows in VALUES) that bulk operations put
+// into a single query, when BatchSize is not set in ControllerConfig
+const DefaultBatchSize = 10000
+
+// MaxQueryParameters is the PostgreSQL limit of bind parameters in a single query
+const MaxQueryParameters = 65535
+
+// getBatches splits 'n' items into batches, so that none of them has more than 'batchSize' items and does not
+// exceed the limit of query parameters when each item takes 'paramsPerItem' of them. When 'batchSize' is lower than 1
+// then controller's batch size is used. Batches are returned as start and end (exclusive) indexes
+func (c Controller) getBatches(n int, paramsPerItem int, batchSize int) [][2]int {
+	if batchSize < 1 {
+		batchSize = c.batchSize
+	}
+	if paramsPerItem < 1 {
+		paramsPerItem = 1
+	}
+	if batchSize*paramsPerItem > MaxQueryParameters {
+		batchSize = MaxQueryParameters / paramsPerItem
+	}
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	batches := [][2]int{}
+	for start := 0; start < n; start += batchSize {
+		end := start + batchSize
+		if end > n {
+			end = n
+		}
+		batches = append(batches, [2]int{start, end})
+	}
+	return batches
+}
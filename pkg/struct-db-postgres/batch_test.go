@@ -0,0 +1,26 @@
+package structdbpostgres
+
+import "testing"
+
+// TestGetBatches tests if getBatches splits items so that batches do not exceed batch size and limit of query parameters
+func TestGetBatches(t *testing.T) {
+	batches := testController.getBatches(25, 1, 10)
+	if len(batches) != 3 || batches[0] != [2]int{0, 10} || batches[2] != [2]int{20, 25} {
+		t.Fatalf("getBatches failed to split items into batches: %v", batches)
+	}
+
+	batches = testController.getBatches(70000, 1, 0)
+	if len(batches) != 7 || batches[6] != [2]int{60000, 70000} {
+		t.Fatalf("getBatches failed to split items into batches of default size: %v", batches)
+	}
+
+	batches = testController.getBatches(2000, 100, 1000)
+	if len(batches) != 4 || batches[0] != [2]int{0, 655} {
+		t.Fatalf("getBatches failed to split items into batches within the limit of query parameters: %v", batches)
+	}
+
+	batches = testController.getBatches(0, 1, 10)
+	if len(batches) != 0 {
+		t.Fatalf("getBatches returned batches for no items: %v", batches)
+	}
+}
@@ -0,0 +1,70 @@
+package structdbpostgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
+)
+
+// Watermark is the position in the changes of a table, ie. time in the 'updated_at' field and ID of the last object
+// that was read by GetChangedSince. ID breaks ties between objects updated at the same time
+type Watermark struct {
+	UpdatedAt time.Time
+	ID        int64
+}
+
+// GetChangedSince returns up to limit objects that were updated after the watermark, eg. to sync them to another
+// system, ordered by their 'updated_at' field and ID, and the watermark of the last one, which is passed to get
+// the next objects. When there are no more objects, the same watermark is returned. Zero Watermark gets all the
+// objects, and Watermark with time only gets these updated at that time or later. Struct must have a field with
+// 'updated_at' tag and an integer primary key
+func (c Controller) GetChangedSince(newObjFunc func() interface{}, since Watermark, limit int) ([]interface{}, Watermark, *ErrController) {
+	return c.GetChangedSinceContext(context.Background(), newObjFunc, since, limit)
+}
+
+// GetChangedSinceContext does the same as GetChangedSince but it runs queries with the context
+func (c Controller) GetChangedSinceContext(ctx context.Context, newObjFunc func() interface{}, since Watermark, limit int) ([]interface{}, Watermark, *ErrController) {
+	h, err := c.getSQLGenerator(newObjFunc(), nil, "")
+	if err != nil {
+		return nil, since, err
+	}
+
+	updatedAt := h.GetUpdatedAtFieldName()
+	if updatedAt == "" {
+		return nil, since, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("struct does not have a field with 'updated_at' tag"),
+		}
+	}
+
+	// ID in Watermark is an integer, and string primary key cannot be compared with it
+	if c.getFieldKind(newObjFunc(), h.GetIDFieldName()) == reflect.String {
+		return nil, since, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("primary key %s is a string and it cannot be used in Watermark", h.GetIDFieldName()),
+		}
+	}
+
+	xobj, err := c.GetContext(ctx, newObjFunc, GetOptions{
+		Order: []string{updatedAt, "asc", h.GetIDFieldName(), "asc"},
+		Limit: limit,
+		Filters: map[string]interface{}{
+			"_keyset": stsql.Keyset{
+				Fields: []string{updatedAt, h.GetIDFieldName()},
+				Values: []interface{}{since.UpdatedAt, since.ID},
+			},
+		},
+	})
+	if err != nil || len(xobj) == 0 {
+		return xobj, since, err
+	}
+
+	last := xobj[len(xobj)-1]
+	return xobj, Watermark{
+		UpdatedAt: reflect.ValueOf(last).Elem().FieldByName(updatedAt).Interface().(time.Time),
+		ID:        c.GetObjIDValue(last),
+	}, nil
+}
@@ -1,10 +1,13 @@
 package structdbpostgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
+	"sort"
 	"strconv"
+	"time"
 
 	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
 )
@@ -12,173 +15,1330 @@ import (
 const RawConjuctionOR = 1
 const RawConjuctionAND = 2
 
+// Raw is a filter value which is a trusted SQL expression, eg. Raw("now()"), that is put into the query as it is
+// instead of being a bind parameter. It must never contain user input. See struct-sql-postgres for details
+type Raw = stsql.Raw
+
+// Filter is a filter value with a comparison operator other than equality, eg. Gt(18). See struct-sql-postgres
+type Filter = stsql.Filter
+
+// FilterGroup is a group of filters joined with its Conjunction, RawConjuctionOR or RawConjuctionAND (the default),
+// eg. to match objects with one status or another. See GetOptions.FilterGroups
+type FilterGroup = stsql.FilterGroup
+
+// AggregateFunc is an aggregate function used in GetAggregate, one of Sum, Avg, Min or Max
+type AggregateFunc = stsql.AggregateFunc
+
+const (
+	Sum = stsql.Sum
+	Avg = stsql.Avg
+	Min = stsql.Min
+	Max = stsql.Max
+)
+
+// Gt returns a filter value matching objects with field greater than v
+func Gt(v interface{}) Filter {
+	return stsql.Gt(v)
+}
+
+// Gte returns a filter value matching objects with field greater than or equal to v
+func Gte(v interface{}) Filter {
+	return stsql.Gte(v)
+}
+
+// Lt returns a filter value matching objects with field less than v
+func Lt(v interface{}) Filter {
+	return stsql.Lt(v)
+}
+
+// Lte returns a filter value matching objects with field less than or equal to v
+func Lte(v interface{}) Filter {
+	return stsql.Lte(v)
+}
+
+// Ne returns a filter value matching objects with field not equal to v
+func Ne(v interface{}) Filter {
+	return stsql.Ne(v)
+}
+
+// Like returns a filter value matching objects with field matching pattern v, eg. Like("%john%")
+func Like(v string) Filter {
+	return stsql.Like(v)
+}
+
+// IsNull returns a filter value matching objects with field that is NULL in the database, which only a pointer
+// field can be
+func IsNull() Filter {
+	return stsql.IsNull()
+}
+
+// IsNotNull returns a filter value matching objects with field that is not NULL in the database
+func IsNotNull() Filter {
+	return stsql.IsNotNull()
+}
+
+// ILike returns a filter value matching objects with field matching pattern v case-insensitively
+func ILike(v string) Filter {
+	return stsql.ILike(v)
+}
+
 type LoadOptions struct {
 	Unused bool
+	// HydrateJoined sets joined structs in the object, see GetOptions
+	HydrateJoined bool
 }
 
 type SaveOptions struct {
 	NoInsert bool
+	// ForceInsertWithID makes Save run a plain INSERT with ID set in the object, instead of the "upsert" query
+	ForceInsertWithID bool
+	// SyncIDSequence, used with ForceInsertWithID, sets ID sequence to the highest ID in the table after the insert,
+	// so that rows inserted later without ID do not collide with it
+	SyncIDSequence bool
+	// UpdateColumns limits fields that are overwritten when object with ID already exists in the database, other
+	// columns keep their values. All fields are updated when it is empty
+	UpdateColumns []string
+	// ConflictUpdateWhere is a condition for updating object that already exists in the database, eg.
+	// 'EXCLUDED.UpdatedAt > .UpdatedAt', where '.Field' is the current value and 'EXCLUDED.Field' the saved one.
+	// Only trusted input can be used here as it is put into the query as it is
+	ConflictUpdateWhere string
+	// ConflictConstraint is a name of a constraint, eg. a unique one, that is the conflict target of the "upsert"
+	// instead of ID, eg. 'uq_email'. Object without ID is inserted, and when it conflicts with an existing row, that
+	// row is updated and its ID is set in the object. UpdateColumns and ConflictUpdateWhere work the same way.
+	// It cannot be used with NoInsert or ForceInsertWithID
+	ConflictConstraint string
+	// OnConflictDoNothing makes inserting object skipped, instead of failing, when it violates a unique constraint.
+	// Object's ID stays zero then and SaveResult has Skipped set. It is used only when object is inserted and not
+	// "upserted", ie. when it has no ID, or with ForceInsertWithID or ID generator
+	OnConflictDoNothing bool
+	// SkipValidation saves object without validating its fields. It should be used only with data that is known to
+	// be valid, eg. when importing it, as invalid values get into the database
+	SkipValidation bool
+	// CheckReferences maps foreign key fields, eg. 'GroupID', to functions returning an instance of the struct they
+	// refer to, eg. '&Group{}'. Before the object is saved, it is checked if the referenced objects exist, and
+	// ValidateReferences error with FailReference for each dangling field is returned when they do not. Fields with
+	// zero value are not checked. It costs one query per field, so it is off by default
+	CheckReferences map[string]func() interface{}
+	// OnProgress, when set, is called by SaveMultiple after each inserted batch with the number of objects inserted
+	// so far and the number of all objects, eg. to show a progress bar. It is not used by Save
+	OnProgress func(done int, total int)
+}
+
+// OrderBy is a field that objects are ordered by, ascending unless Desc is set. See GetOptions.OrderBy
+type OrderBy struct {
+	Field string
+	Desc  bool
 }
 
 type GetOptions struct {
+	// Order is a list of field names, each followed by its direction, eg. {"Age", "desc", "Name", "asc"}. Field at
+	// the end without direction is ascending. See also OrderBy
 	Order               []string
 	Limit               int
 	Offset              int
 	Filters             map[string]interface{}
 	RowObjTransformFunc func(interface{}) interface{}
+	// StatementTimeout, when set, runs the query in a read transaction with 'SET LOCAL statement_timeout' so that
+	// the database server aborts it when it takes longer
+	StatementTimeout time.Duration
+	// HydrateJoined sets joined structs (fields with 'join' tag, eg. 'Group *Group') in each object. Their fields are
+	// taken from the flattened 'Group_Field' fields and their ID from 'GroupID' field. Other fields stay zeroed
+	HydrateJoined bool
+	// IgnoreEmptyFilters removes filters with empty string value, so that eg. blank search input does not match
+	// only empty columns
+	IgnoreEmptyFilters bool
+	// IgnoreZeroFilters removes filters with zero value of any type, including empty string. Lists are kept, and
+	// an empty or nil one matches nothing
+	IgnoreZeroFilters bool
+	// DisableOrderTiebreaker stops adding ID to the end of Order when Limit or Offset is set. By default, it is
+	// added so that rows with the same values of ordered fields always come in the same order across pages
+	DisableOrderTiebreaker bool
+	// FilterGroups are additional filters, where each group is put in parentheses and its filters are joined with
+	// the group's Conjunction, eg. (status=$2 OR age>$3). Groups are AND-ed with each other and with Filters
+	FilterGroups []FilterGroup
+	// Cursor, created with EncodeCursor, gets objects that come after the one the cursor was created for, in Order.
+	// Unlike Offset, it does not make the database go through the skipped rows. All the fields in Order, including
+	// the ID tiebreaker, must have the same direction, as they are compared with a row value, eg. (age,id)<($1,$2)
+	Cursor string
+	// Fields limits the selected columns to the ones of these fields and the ID field. Other fields in returned
+	// objects stay zeroed. It is useful for wide tables when only some of the fields are needed
+	Fields []string
+	// SkipDefaultOptions ignores options registered for the struct with RegisterDefaultGetOptions
+	SkipDefaultOptions bool
+	// OrderBy is the order with an explicit direction of each field, eg. {{Field: "CreatedAt", Desc: true}, {Field:
+	// "Name"}}. Unlike Order, a field that does not exist is an error. Order and OrderBy cannot be both set
+	OrderBy []OrderBy
+	// ForUpdate locks selected rows, as with 'SELECT ... FOR UPDATE', until the end of transaction, so other
+	// transactions cannot change or lock them. ForShare locks them only against changes, and other transactions can
+	// still read them with ForShare. Both make sense only in a transaction, and they cannot be both set
+	ForUpdate bool
+	ForShare  bool
 }
 
 type DeleteOptions struct {
 	Constructors map[string]func() interface{}
+	// CascadeDeleteBatchSize is the number of IDs passed at once to cascade delete, controller's BatchSize is used when not set
+	CascadeDeleteBatchSize int
 }
 
 type DeleteMultipleOptions struct {
 	Filters            map[string]interface{}
 	CascadeDeleteDepth int
 	Constructors       map[string]func() interface{}
+	// CascadeDeleteBatchSize is the number of IDs passed at once to cascade delete, controller's BatchSize is used when not set
+	CascadeDeleteBatchSize int
+	// AllowFullTableUpdate must be set to run DeleteMultiple without any filters, which removes all the rows
+	AllowFullTableUpdate bool
 }
 
 type UpdateMultipleOptions struct {
 	Filters                 map[string]interface{}
 	CascadeDeleteDepth      int
 	ConvertValuesFromString bool
+	// AllowFullTableUpdate must be set to run UpdateMultiple without any filters, which updates all the rows
+	AllowFullTableUpdate bool
+	// SkipValidation updates rows without validating values, see SaveOptions. Filters are still validated
+	SkipValidation bool
 }
 
 type GetCountOptions struct {
 	Filters map[string]interface{}
+	// IgnoreEmptyFilters and IgnoreZeroFilters work the same as in GetOptions
+	IgnoreEmptyFilters bool
+	IgnoreZeroFilters  bool
+	// Approximate returns estimated number of rows from PostgreSQL statistics, when there are no filters. Estimate
+	// is updated by VACUUM, ANALYZE and CREATE INDEX, so it can differ from the real count. Rows are counted when
+	// there is no estimate for the table yet
+	Approximate bool
+}
+
+// SaveResult contains details on what Save has done with the object
+type SaveResult struct {
+	// Inserted is true when a new row was inserted and false when an existing one was updated
+	Inserted bool
+	// Skipped is true when an existing row was not updated because of ConflictUpdateWhere condition, or when row was
+	// not inserted because of OnConflictDoNothing
+	Skipped bool
+}
+
+// Facet is a distinct value of a field with number of rows that have it
+type Facet struct {
+	Value interface{}
+	Count int64
 }
 
 // Save takes object, validates its field values and saves it in the database.
 // If ID is not present then an INSERT will be performed
 // If ID is set then an "upsert" is performed
 func (c Controller) Save(obj interface{}, options SaveOptions) *ErrController {
+	return c.SaveContext(context.Background(), obj, options)
+}
+
+// SaveContext does the same as Save but it runs queries with the context
+func (c Controller) SaveContext(ctx context.Context, obj interface{}, options SaveOptions) *ErrController {
+	_, err := c.SaveWithResultContext(ctx, obj, options)
+	return err
+}
+
+// SaveWithResult does the same as Save but it additionally returns SaveResult that tells whether the row was
+// inserted or updated. For "upsert", it is determined with the xmax system column in the same query
+func (c Controller) SaveWithResult(obj interface{}, options SaveOptions) (*SaveResult, *ErrController) {
+	return c.SaveWithResultContext(context.Background(), obj, options)
+}
+
+// SaveWithResultContext does the same as SaveWithResult but it runs queries with the context
+func (c Controller) SaveWithResultContext(ctx context.Context, obj interface{}, options SaveOptions) (*SaveResult, *ErrController) {
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	b, invalidFields, err2 := c.Validate(obj, nil)
-	if err2 != nil {
-		return &ErrController{
-			Op:  "Validate",
-			Err: fmt.Errorf("Error when trying to validate: %w", err2),
+	err = c.checkNotView(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if !options.SkipValidation {
+		b, invalidFields, err2 := c.Validate(obj, nil)
+		if err2 != nil {
+			return nil, &ErrController{
+				Op:  "Validate",
+				Err: fmt.Errorf("Error when trying to validate: %w", err2),
+			}
+		}
+
+		if !b {
+			c.logValidationFailure("Save", "Validate", obj, invalidFields)
+			return nil, &ErrController{
+				Op: "Validate",
+				Err: &ErrValidation{
+					Fields: invalidFields,
+				},
+			}
 		}
 	}
 
-	if !b {
-		return &ErrController{
-			Op: "Validate",
-			Err: &ErrValidation{
-				Fields: invalidFields,
-			},
+	if len(options.CheckReferences) > 0 {
+		err = c.checkReferences(ctx, obj, options.CheckReferences)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.setTimestamps(h, obj)
+
+	if options.ConflictConstraint != "" {
+		return c.saveOnConflictConstraint(ctx, h, obj, options)
+	}
+
+	result := &SaveResult{}
+
+	// With ID generator, new object gets an ID before it is inserted
+	insertWithID := options.ForceInsertWithID
+	if c.idGenerator != nil && !c.hasObjID(obj) {
+		err = c.setGeneratedID(obj)
+		if err != nil {
+			return nil, err
+		}
+		insertWithID = true
+	}
+
+	if insertWithID {
+		if !c.hasObjID(obj) {
+			return nil, &ErrController{
+				Op:  "MissingID",
+				Err: fmt.Errorf("object does not have an ID"),
+			}
+		}
+
+		if options.OnConflictDoNothing {
+			result, err = c.insertOnConflictDoNothing(ctx, h, obj, true)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			err3 := c.queryRow(ctx, "Save", h.GetQueryInsertWithID(), c.GetObjFieldInterfaces(obj, true)...).Scan(c.GetObjIDInterface(obj))
+			if err3 != nil {
+				return nil, c.newErrDBQuery(err3)
+			}
+			result.Inserted = true
+		}
+
+		if options.SyncIDSequence && result.Inserted {
+			_, err3 := c.exec(ctx, "Save", h.GetQuerySyncIDSequence())
+			if err3 != nil {
+				return nil, c.newErrDBQuery(err3)
+			}
 		}
+		return result, nil
 	}
 
 	var err3 error
-	if c.GetObjIDValue(obj) != 0 {
+	if c.hasObjID(obj) {
 		// do no try to insert if NoInsert is set
 		// TODO: error handling, we should check if object exists - for now nothing happens, UPDATE gets executed and updates nothing
-		if options.NoInsert {
-			_, err3 = c.dbConn.Exec(h.GetQueryUpdateById(), append(c.GetObjFieldInterfaces(obj, false), c.GetObjIDInterface(obj))...)
+		if options.NoInsert && h.GetCreatedAtFieldName() != "" {
+			err = c.updateByIDWithoutCreatedAt(ctx, h, obj)
+			if err != nil {
+				return nil, err
+			}
+		} else if options.NoInsert {
+			_, err3 = c.exec(ctx, "Save", h.GetQueryUpdateById(), append(c.GetObjFieldInterfaces(obj, false), c.GetObjIDInterface(obj))...)
+		} else if len(options.UpdateColumns) > 0 || options.ConflictUpdateWhere != "" || h.GetCreatedAtFieldName() != "" {
+			// try to insert - if ID already exists then update only the specified columns, when condition is met.
+			// Value of the 'created_at' field is never updated and the existing one is returned instead
+			query := h.GetQueryInsertOnConflictUpdateWhereReturningInserted(options.UpdateColumns, options.ConflictUpdateWhere)
+			if query == "" {
+				return nil, &ErrController{
+					Op:  "InvalidField",
+					Err: fmt.Errorf("invalid update columns %v", options.UpdateColumns),
+				}
+			}
+			err3 = c.queryRow(ctx, "Save", query, c.GetObjFieldInterfaces(obj, true)...).Scan(c.getUpsertScanDests(h, obj, result)...)
+			// when update is skipped because of the condition, no row is returned
+			if err3 == sql.ErrNoRows && options.ConflictUpdateWhere != "" {
+				result.Skipped = true
+				err3 = nil
+			}
 		} else {
 			// try to insert - if ID already exists then try to update it
-			_, err3 = c.dbConn.Exec(h.GetQueryInsertOnConflictUpdate(), append(c.GetObjFieldInterfaces(obj, true), c.GetObjFieldInterfaces(obj, false)...)...)
+			err3 = c.queryRow(ctx, "Save", h.GetQueryInsertOnConflictUpdateReturningInserted(), append(c.GetObjFieldInterfaces(obj, true), c.GetObjFieldInterfaces(obj, false)...)...).Scan(c.GetObjIDInterface(obj), &result.Inserted)
+		}
+	} else if c.getFieldKind(obj, h.GetIDFieldName()) == reflect.String {
+		// string primary key is not generated by the database so it must be set
+		return nil, &ErrController{
+			Op:  "MissingID",
+			Err: fmt.Errorf("object does not have an ID"),
 		}
+	} else if options.OnConflictDoNothing {
+		return c.insertOnConflictDoNothing(ctx, h, obj, false)
 	} else {
-		err3 = c.dbConn.QueryRow(h.GetQueryInsert(), c.GetObjFieldInterfaces(obj, false)...).Scan(c.GetObjIDInterface(obj))
+		err3 = c.queryRow(ctx, "Save", h.GetQueryInsert(), c.GetObjFieldInterfaces(obj, false)...).Scan(c.GetObjIDInterface(obj))
+		result.Inserted = true
 	}
 	if err3 != nil {
+		return nil, c.newErrDBQuery(err3)
+	}
+	return result, nil
+}
+
+// SaveMultiple inserts objects of the same type with multi-row INSERT queries, split into batches when needed, and
+// sets IDs of the inserted rows in the objects. All of them are validated before anything is inserted. Objects are
+// never updated so objects with ID can be inserted only with ForceInsertWithID or ID generator, and only
+// ForceInsertWithID, SyncIDSequence, SkipValidation and OnProgress options are supported. When there is more
+// than one batch, they are inserted in a transaction, so nothing is inserted when one of them fails
+func (c Controller) SaveMultiple(objs []interface{}, options SaveOptions) *ErrController {
+	return c.SaveMultipleContext(context.Background(), objs, options)
+}
+
+// SaveMultipleContext does the same as SaveMultiple but it runs queries with the context
+func (c Controller) SaveMultipleContext(ctx context.Context, objs []interface{}, options SaveOptions) *ErrController {
+	if len(objs) == 0 {
+		return nil
+	}
+
+	if options.NoInsert || len(options.UpdateColumns) > 0 || options.ConflictUpdateWhere != "" || options.ConflictConstraint != "" {
 		return &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err3),
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("SaveMultiple supports only ForceInsertWithID, SyncIDSequence, SkipValidation and OnProgress options"),
+		}
+	}
+
+	t := reflect.TypeOf(objs[0])
+	for i, obj := range objs {
+		if reflect.TypeOf(obj) != t {
+			return &ErrController{
+				Op:  "InvalidType",
+				Err: fmt.Errorf("object %d is %s and not %s", i, reflect.TypeOf(obj), t),
+			}
+		}
+	}
+
+	h, err := c.getSQLGenerator(objs[0], nil, "")
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNotView(objs[0])
+	if err != nil {
+		return err
+	}
+
+	if !options.SkipValidation {
+		for i, obj := range objs {
+			b, invalidFields, err2 := c.Validate(obj, nil)
+			if err2 != nil {
+				return &ErrController{
+					Op:  "Validate",
+					Err: fmt.Errorf("Error when trying to validate object %d: %w", i, err2),
+				}
+			}
+
+			if !b {
+				c.logValidationFailure("SaveMultiple", "Validate", obj, invalidFields)
+				return &ErrController{
+					Op: "Validate",
+					Err: &ErrValidation{
+						Fields: invalidFields,
+					},
+				}
+			}
+		}
+	}
+
+	// With ID generator, new objects get IDs before they are inserted
+	withID := options.ForceInsertWithID
+	if c.idGenerator != nil {
+		for _, obj := range objs {
+			if !c.hasObjID(obj) {
+				err = c.setGeneratedID(obj)
+				if err != nil {
+					return err
+				}
+			}
+		}
+		withID = true
+	}
+
+	for i, obj := range objs {
+		if withID && !c.hasObjID(obj) {
+			return &ErrController{
+				Op:  "MissingID",
+				Err: fmt.Errorf("object %d does not have an ID", i),
+			}
+		}
+		if !withID && c.hasObjID(obj) {
+			return &ErrController{
+				Op:  "InvalidOptions",
+				Err: fmt.Errorf("object %d has an ID and ForceInsertWithID is not set", i),
+			}
+		}
+	}
+
+	for _, obj := range objs {
+		c.setTimestamps(h, obj)
+	}
+
+	batches := c.getBatches(len(objs), len(c.GetObjFieldInterfaces(objs[0], withID)), 0)
+
+	// Batches are inserted in a transaction, so that nothing is inserted when one of them fails. Controller that is
+	// in a transaction already uses that one
+	if c.tx == nil && len(batches) > 1 {
+		tx, err := c.BeginContext(ctx)
+		if err != nil {
+			return err
+		}
+		err = tx.c.insertBatches(ctx, h, objs, batches, withID, options)
+		if err != nil {
+			tx.Rollback()
+			// IDs of rows that were rolled back do not exist
+			if !withID {
+				for _, obj := range objs {
+					reflect.ValueOf(obj).Elem().FieldByName(h.GetIDFieldName()).SetInt(0)
+				}
+			}
+			return err
+		}
+		return tx.Commit()
+	}
+
+	return c.insertBatches(ctx, h, objs, batches, withID, options)
+}
+
+// SyncSequence sets the ID sequence of object's table to the highest ID in the table, so that the next insert gets
+// a higher one, eg. after rows with IDs were inserted or restored from a backup. Primary key must be an integer
+func (c Controller) SyncSequence(obj interface{}) *ErrController {
+	return c.SyncSequenceContext(context.Background(), obj)
+}
+
+// SyncSequenceContext does the same as SyncSequence but it runs query with the context
+func (c Controller) SyncSequenceContext(ctx context.Context, obj interface{}) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return err
+	}
+
+	if c.getFieldKind(obj, h.GetIDFieldName()) == reflect.String {
+		return &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("primary key %s is a string and it has no sequence", h.GetIDFieldName()),
 		}
 	}
+
+	_, err2 := c.exec(ctx, "SyncSequence", h.GetQuerySyncIDSequence())
+	if err2 != nil {
+		return c.newErrDBQuery(err2)
+	}
 	return nil
 }
 
-// Load sets object's fields with values from the database table with a specific id. If record does not exist
-// in the database, all field values in the struct are zeroed
-// TODO: Should it return an ErrNotExist?
-func (c Controller) Load(obj interface{}, id string, options LoadOptions) *ErrController {
-	idInt, err := strconv.Atoi(id)
+// SaveDefaults inserts a row with default values of all columns, eg. a draft to be filled later, and returns
+// a new object with the values of the row. Object is not validated
+func (c Controller) SaveDefaults(newObjFunc func() interface{}) (interface{}, *ErrController) {
+	return c.SaveDefaultsContext(context.Background(), newObjFunc)
+}
+
+// SaveDefaultsContext does the same as SaveDefaults but it runs queries with the context
+func (c Controller) SaveDefaultsContext(ctx context.Context, newObjFunc func() interface{}) (interface{}, *ErrController) {
+	obj := newObjFunc()
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	err2 := c.queryRow(ctx, "SaveDefaults", h.GetQueryInsertDefaultValues()).Scan(c.GetObjFieldInterfaces(obj, true)...)
+	if err2 != nil {
+		return nil, c.newErrDBQuery(err2)
+	}
+	return obj, nil
+}
+
+// ToggleField negates value of a bool field of an object in the database, in a single UPDATE query, and sets the
+// new value in the object. Object must have ID set
+func (c Controller) ToggleField(obj interface{}, fieldName string) *ErrController {
+	return c.ToggleFieldContext(context.Background(), obj, fieldName)
+}
+
+// ToggleFieldContext does the same as ToggleField but it runs queries with the context
+func (c Controller) ToggleFieldContext(ctx context.Context, obj interface{}, fieldName string) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
+		return err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return err
+	}
+
+	if c.getFieldKind(obj, fieldName) != reflect.Bool {
 		return &ErrController{
-			Op:  "IDToInt",
-			Err: fmt.Errorf("Error converting string to int: %w", err),
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s is not a bool", fieldName),
 		}
 	}
 
-	h, err2 := c.getSQLGenerator(obj, nil, "")
+	if !c.hasObjID(obj) {
+		return &ErrController{
+			Op:  "MissingID",
+			Err: fmt.Errorf("object does not have an ID"),
+		}
+	}
+
+	err2 := c.queryRow(ctx, "ToggleField", h.GetQueryToggleById(fieldName), c.GetObjIDInterface(obj)).Scan(reflect.ValueOf(obj).Elem().FieldByName(fieldName).Addr().Interface())
 	if err2 != nil {
-		return err2
+		return c.newErrDBQuery(err2)
 	}
+	return nil
+}
 
-	err3 := c.dbConn.QueryRow(h.GetQuerySelectById(), int64(idInt)).Scan(c.GetObjFieldInterfaces(obj, true)...)
-	switch {
-	case err3 == sql.ErrNoRows:
-		c.ResetFields(obj)
-		return nil
-	case err3 != nil:
+// IncrementField adds delta to a numeric field of an object in the database, in a single UPDATE query, and sets
+// the new value in the object. Negative delta decrements the field. Object must have ID set
+func (c Controller) IncrementField(obj interface{}, fieldName string, delta int64) *ErrController {
+	return c.IncrementFieldContext(context.Background(), obj, fieldName, delta)
+}
+
+// IncrementFieldContext does the same as IncrementField but it runs queries with the context
+func (c Controller) IncrementFieldContext(ctx context.Context, obj interface{}, fieldName string, delta int64) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return err
+	}
+
+	if fieldName == h.GetIDFieldName() || !c.isNumericKind(c.getFieldKind(obj, fieldName)) {
+		return &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s is not numeric", fieldName),
+		}
+	}
+
+	if !c.hasObjID(obj) {
 		return &ErrController{
+			Op:  "MissingID",
+			Err: fmt.Errorf("object does not have an ID"),
+		}
+	}
+
+	err2 := c.queryRow(ctx, "IncrementField", h.GetQueryIncrementById(fieldName), delta, c.GetObjIDInterface(obj)).Scan(reflect.ValueOf(obj).Elem().FieldByName(fieldName).Addr().Interface())
+	if err2 != nil {
+		return c.newErrDBQuery(err2)
+	}
+	return nil
+}
+
+// Reparent moves all objects referencing one parent to another by setting fkField to toID where it equals fromID.
+// It returns number of updated rows
+func (c Controller) Reparent(childNewObjFunc func() interface{}, fkField string, fromID int64, toID int64) (int64, *ErrController) {
+	return c.ReparentContext(context.Background(), childNewObjFunc, fkField, fromID, toID)
+}
+
+// ReparentContext does the same as Reparent but it runs queries with the context
+func (c Controller) ReparentContext(ctx context.Context, childNewObjFunc func() interface{}, fkField string, fromID int64, toID int64) (int64, *ErrController) {
+	obj := childNewObjFunc()
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return 0, err
+	}
+
+	if fkField == h.GetIDFieldName() || !c.isNumericKind(c.getFieldKind(obj, fkField)) {
+		return 0, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s is not a valid foreign key field", fkField),
+		}
+	}
+
+	values := map[string]interface{}{fkField: toID}
+	filters := map[string]interface{}{fkField: fromID}
+
+	res, err2 := c.exec(ctx, "Reparent", h.GetQueryUpdate(values, filters, nil, nil), toID, fromID)
+	if err2 != nil {
+		return 0, c.newErrDBQuery(err2)
+	}
+
+	cnt, err2 := res.RowsAffected()
+	if err2 != nil {
+		return 0, &ErrController{
 			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err3),
+			Err: fmt.Errorf("Error getting number of affected rows: %w", err2),
+		}
+	}
+	return cnt, nil
+}
+
+// Load sets object's fields with values from the database table with a specific id. If record does not exist
+// in the database, all field values in the struct are zeroed
+// TODO: Should it return an ErrNotExist?
+func (c Controller) Load(obj interface{}, id string, options LoadOptions) *ErrController {
+	return c.LoadContext(context.Background(), obj, id, options)
+}
+
+// LoadContext does the same as Load but it runs queries with the context
+func (c Controller) LoadContext(ctx context.Context, obj interface{}, id string, options LoadOptions) *ErrController {
+	h, err2 := c.getSQLGenerator(obj, nil, "")
+	if err2 != nil {
+		return err2
+	}
+
+	// String primary key is passed as it is, except for a malformed UUID, which is rejected with InvalidID
+	var idArg interface{} = id
+	if c.getFieldKind(obj, h.GetIDFieldName()) != reflect.String {
+		idInt, err := strconv.Atoi(id)
+		if err != nil {
+			return &ErrController{
+				Op:  "IDToInt",
+				Err: fmt.Errorf("Error converting string to int: %w", err),
+			}
+		}
+		idArg = int64(idInt)
+	} else if h.GetDBColTypeFromFieldName(h.GetIDFieldName()) == "UUID" && !reUUID.MatchString(id) {
+		return &ErrController{
+			Op:  "InvalidID",
+			Err: fmt.Errorf("id %q is not a valid UUID", id),
+		}
+	}
+
+	err3 := c.queryRow(ctx, "Load", h.GetQuerySelectById(), idArg).Scan(c.GetObjFieldInterfaces(obj, true)...)
+	switch {
+	case err3 == sql.ErrNoRows:
+		c.ResetFields(obj)
+		return nil
+	case err3 != nil:
+		return c.newErrDBQuery(err3)
+	default:
+		if options.HydrateJoined {
+			c.hydrateJoined(obj)
+		}
+		return nil
+	}
+}
+
+// Refresh reads the row of an object again, using object's ID field, and sets its field values to the current ones,
+// eg. after the row was changed by another process. When the row does not exist anymore, it returns an error with
+// Op 'NotExist' that wraps ErrNotExist, and the object is left untouched
+func (c Controller) Refresh(obj interface{}) *ErrController {
+	return c.RefreshContext(context.Background(), obj)
+}
+
+// RefreshContext does the same as Refresh but it runs queries with the context
+func (c Controller) RefreshContext(ctx context.Context, obj interface{}) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	if !c.hasObjID(obj) {
+		return &ErrController{
+			Op:  "Refresh",
+			Err: fmt.Errorf("object does not have an ID so there is nothing to refresh"),
+		}
+	}
+
+	err2 := c.queryRow(ctx, "Refresh", h.GetQuerySelectById(), c.GetObjIDInterface(obj)).Scan(c.GetObjFieldInterfaces(obj, true)...)
+	switch {
+	case err2 == sql.ErrNoRows:
+		return &ErrController{
+			Op:  "NotExist",
+			Err: ErrNotExist,
+		}
+	case err2 != nil:
+		return c.newErrDBQuery(err2)
+	default:
+		return nil
+	}
+}
+
+// Delete removes object from the database table and it does that only when ID field is set (greater than 0).
+// Once deleted from the DB, all field values are zeroed. Object with cascade delete is deleted with its children in
+// a transaction, so on error nothing is deleted
+// TODO: Error handling probably needs re-designing
+func (c Controller) Delete(obj interface{}, options DeleteOptions) *ErrController {
+	return c.DeleteContext(context.Background(), obj, options)
+}
+
+// DeleteContext does the same as Delete but it runs queries with the context
+func (c Controller) DeleteContext(ctx context.Context, obj interface{}, options DeleteOptions) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return err
+	}
+
+	if !c.hasObjID(obj) {
+		return nil
+	}
+
+	// Row and its children are deleted in a transaction, so that nothing is deleted when cascade fails, eg. because
+	// ctx is cancelled. Controller that is in a transaction already uses that one
+	if c.tx == nil && c.GetObjIDValue(obj) != 0 && c.hasCascadeDelete(obj) {
+		tx, err := c.BeginContext(ctx)
+		if err != nil {
+			return err
+		}
+		err = tx.c.deleteByID(ctx, h, obj, options)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+	} else {
+		err = c.deleteByID(ctx, h, obj, options)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.ResetFields(obj)
+	return nil
+}
+
+// CountMatching returns number of rows that DeleteMultiple with the same options would delete, not counting the
+// cascade deletes, eg. to show it before the delete is confirmed. Filters are checked and turned into the WHERE
+// condition the same way, and the same errors are returned, eg. when there are no filters
+func (c Controller) CountMatching(obj interface{}, options DeleteMultipleOptions) (int64, *ErrController) {
+	return c.CountMatchingContext(context.Background(), obj, options)
+}
+
+// CountMatchingContext does the same as CountMatching but it runs queries with the context
+func (c Controller) CountMatchingContext(ctx context.Context, obj interface{}, options DeleteMultipleOptions) (int64, *ErrController) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.validateDeleteMultiple("CountMatching", obj, options)
+	if err != nil {
+		return 0, err
+	}
+
+	var cnt int64
+	err2 := c.queryRow(ctx, "CountMatching", h.GetQuerySelectCount(options.Filters, nil), c.getFilterArgs(obj, options.Filters)...).Scan(&cnt)
+	if err2 != nil {
+		return 0, &ErrController{
+			Op:  "DBQueryRowScan",
+			Err: fmt.Errorf("Error scanning DB query row: %w", err2),
+		}
+	}
+	return cnt, nil
+}
+
+// DeleteMultiple removes objects from the database based on specified filters.
+// When there are no filters, it refuses to delete all the rows unless AllowFullTableUpdate is set
+func (c Controller) DeleteMultiple(obj interface{}, options DeleteMultipleOptions) *ErrController {
+	return c.DeleteMultipleContext(context.Background(), obj, options)
+}
+
+// DeleteMultipleContext does the same as DeleteMultiple but it runs queries with the context
+func (c Controller) DeleteMultipleContext(ctx context.Context, obj interface{}, options DeleteMultipleOptions) *ErrController {
+	_, err := c.deleteMultiple(ctx, "DeleteMultiple", obj, options)
+	return err
+}
+
+// DeleteMultipleCount removes objects the same way as DeleteMultiple does and returns number of deleted rows, without
+// the ones removed by cascade delete
+func (c Controller) DeleteMultipleCount(obj interface{}, options DeleteMultipleOptions) (int64, *ErrController) {
+	return c.DeleteMultipleCountContext(context.Background(), obj, options)
+}
+
+// DeleteMultipleCountContext does the same as DeleteMultipleCount but it runs queries with the context
+func (c Controller) DeleteMultipleCountContext(ctx context.Context, obj interface{}, options DeleteMultipleOptions) (int64, *ErrController) {
+	return c.deleteMultiple(ctx, "DeleteMultipleCount", obj, options)
+}
+
+func (c Controller) deleteMultiple(ctx context.Context, method string, obj interface{}, options DeleteMultipleOptions) (int64, *ErrController) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.validateDeleteMultiple(method, obj, options)
+	if err != nil {
+		return 0, err
+	}
+
+	// Run DELETE query and get IDs of deleted rows
+	rows, err2 := c.query(ctx, method, h.GetQueryDeleteReturningID(options.Filters, nil), c.getFilterArgs(obj, options.Filters)...)
+	if err2 != nil {
+		return 0, c.newErrDBQuery(err2)
+	}
+	defer rows.Close()
+
+	returnedIds := []int64{}
+
+	for rows.Next() {
+		var returnedId int64
+		err3 := rows.Scan(&returnedId)
+		if err3 != nil {
+			return 0, &ErrController{
+				Op:  "DBQueryRowsScan",
+				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
+			}
+		}
+
+		returnedIds = append(returnedIds, returnedId)
+	}
+
+	if options.CascadeDeleteDepth < 3 {
+		// Loop through fields to delete cascade
+		err3 := c.runOnDelete(ctx, obj, c.tagName, returnedIds, options.CascadeDeleteDepth, options.CascadeDeleteBatchSize)
+		if err3 != nil {
+			return 0, err3
+		}
+	}
+
+	return int64(len(returnedIds)), nil
+}
+
+// UpdateMultiple updates specific fields in objects from the database based on specified filters.
+// When there are no filters, it refuses to update all the rows unless AllowFullTableUpdate is set
+func (c Controller) UpdateMultiple(obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) *ErrController {
+	return c.UpdateMultipleContext(context.Background(), obj, values, options)
+}
+
+// UpdateMultipleContext does the same as UpdateMultiple but it runs queries with the context
+func (c Controller) UpdateMultipleContext(ctx context.Context, obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	values, err = c.validateUpdateMultiple("UpdateMultiple", obj, values, options)
+	if err != nil {
+		return err
+	}
+
+	_, err2 := c.exec(ctx, "UpdateMultiple", h.GetQueryUpdate(values, options.Filters, nil, nil), append(c.getFilterArgs(obj, values), c.getFilterArgs(obj, options.Filters)...)...)
+	if err2 != nil {
+		return c.newErrDBQuery(err2)
+	}
+
+	return nil
+}
+
+// UpdateMultipleReturning updates objects the same way as UpdateMultiple does and returns updated objects, as they
+// are after the update, in a single query
+func (c Controller) UpdateMultipleReturning(newObjFunc func() interface{}, values map[string]interface{}, options UpdateMultipleOptions) ([]interface{}, *ErrController) {
+	return c.UpdateMultipleReturningContext(context.Background(), newObjFunc, values, options)
+}
+
+// UpdateMultipleReturningContext does the same as UpdateMultipleReturning but it runs queries with the context
+func (c Controller) UpdateMultipleReturningContext(ctx context.Context, newObjFunc func() interface{}, values map[string]interface{}, options UpdateMultipleOptions) ([]interface{}, *ErrController) {
+	obj := newObjFunc()
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	values, err = c.validateUpdateMultiple("UpdateMultipleReturning", obj, values, options)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err2 := c.query(ctx, "UpdateMultipleReturning", h.GetQueryUpdateReturning(values, options.Filters, nil, nil), append(c.getFilterArgs(obj, values), c.getFilterArgs(obj, options.Filters)...)...)
+	if err2 != nil {
+		return nil, c.newErrDBQuery(err2)
+	}
+	defer rows.Close()
+
+	var v []interface{}
+	for rows.Next() {
+		newObj := newObjFunc()
+		err3 := rows.Scan(c.GetObjFieldInterfaces(newObj, true)...)
+		if err3 != nil {
+			return nil, &ErrController{
+				Op:  "DBQueryRowsScan",
+				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
+			}
+		}
+		v = append(v, newObj)
+	}
+
+	if err4 := rows.Err(); err4 != nil {
+		return nil, c.newErrDBQuery(err4)
+	}
+
+	return v, nil
+}
+
+// UpdateMultipleReturningIDs updates objects the same way as UpdateMultiple does and returns IDs of updated rows, eg.
+// to invalidate cache entries of the objects. Primary key must be an integer
+func (c Controller) UpdateMultipleReturningIDs(obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) ([]int64, *ErrController) {
+	return c.UpdateMultipleReturningIDsContext(context.Background(), obj, values, options)
+}
+
+// UpdateMultipleReturningIDsContext does the same as UpdateMultipleReturningIDs but it runs queries with the context
+func (c Controller) UpdateMultipleReturningIDsContext(ctx context.Context, obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) ([]int64, *ErrController) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if c.getFieldKind(obj, h.GetIDFieldName()) == reflect.String {
+		return nil, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("primary key %s is not an integer", h.GetIDFieldName()),
+		}
+	}
+
+	values, err = c.validateUpdateMultiple("UpdateMultipleReturningIDs", obj, values, options)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err2 := c.query(ctx, "UpdateMultipleReturningIDs", h.GetQueryUpdateReturningID(values, options.Filters, nil, nil), append(c.getFilterArgs(obj, values), c.getFilterArgs(obj, options.Filters)...)...)
+	if err2 != nil {
+		return nil, c.newErrDBQuery(err2)
+	}
+	defer rows.Close()
+
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		err3 := rows.Scan(&id)
+		if err3 != nil {
+			return nil, &ErrController{
+				Op:  "DBQueryRowsScan",
+				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
+			}
+		}
+		ids = append(ids, id)
+	}
+
+	if err4 := rows.Err(); err4 != nil {
+		return nil, c.newErrDBQuery(err4)
+	}
+
+	return ids, nil
+}
+
+// UpdateByIDValues updates many objects at once, each with its own values, eg. recomputed scores. Keys of the map are
+// IDs of objects and all the maps with values must have the same fields. It runs 'UPDATE ... FROM (VALUES ...)'
+// queries, split into batches, and returns number of updated rows
+func (c Controller) UpdateByIDValues(newObjFunc func() interface{}, values map[int64]map[string]interface{}) (int64, *ErrController) {
+	return c.UpdateByIDValuesContext(context.Background(), newObjFunc, values)
+}
+
+// UpdateByIDValuesContext does the same as UpdateByIDValues but it runs queries with the context
+func (c Controller) UpdateByIDValuesContext(ctx context.Context, newObjFunc func() interface{}, values map[int64]map[string]interface{}) (int64, *ErrController) {
+	obj := newObjFunc()
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.checkNotView(obj)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, 0, len(values))
+	for id := range values {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	fields := make([]string, 0, len(values[ids[0]]))
+	for f := range values[ids[0]] {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	if len(fields) == 0 {
+		return 0, &ErrController{
+			Op:  "MissingValues",
+			Err: fmt.Errorf("missing values for update"),
+		}
+	}
+
+	objType := reflect.Indirect(reflect.ValueOf(obj)).Type()
+	for _, id := range ids {
+		err = c.validateValuesForID(obj, objType, id, values[id], fields)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	redacted := c.getRedactedFields(obj)
+	var cnt int64
+	for _, batch := range c.getBatches(len(ids), len(fields)+1, 0) {
+		query := h.GetQueryUpdateFromValues(fields, batch[1]-batch[0])
+		if query == "" {
+			return 0, &ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("invalid fields %v", fields),
+			}
+		}
+
+		args := make([]interface{}, 0, (batch[1]-batch[0])*(len(fields)+1))
+		for _, id := range ids[batch[0]:batch[1]] {
+			args = append(args, id)
+			for _, f := range fields {
+				if redacted[f] {
+					args = append(args, redactedArg{v: values[id][f]})
+				} else {
+					args = append(args, values[id][f])
+				}
+			}
+		}
+
+		res, err2 := c.exec(ctx, "UpdateByIDValues", query, args...)
+		if err2 != nil {
+			return cnt, c.newErrDBQuery(err2)
+		}
+
+		n, err2 := res.RowsAffected()
+		if err2 != nil {
+			return cnt, &ErrController{
+				Op:  "DBQuery",
+				Err: fmt.Errorf("Error getting number of affected rows: %w", err2),
+			}
+		}
+		cnt += n
+	}
+
+	return cnt, nil
+}
+
+// Get runs a select query on the database with specified filters, order, limit and offset and returns a
+// list of objects
+func (c Controller) Get(newObjFunc func() interface{}, options GetOptions) ([]interface{}, *ErrController) {
+	return c.GetContext(context.Background(), newObjFunc, options)
+}
+
+// GetContext does the same as Get but it runs queries with the context
+func (c Controller) GetContext(ctx context.Context, newObjFunc func() interface{}, options GetOptions) ([]interface{}, *ErrController) {
+	obj := newObjFunc()
+	options = c.getOptionsWithDefaults(obj, options)
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
+
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	filtersToValidate := []map[string]interface{}{options.Filters}
+	for _, group := range options.FilterGroups {
+		filtersToValidate = append(filtersToValidate, group.Filters)
+	}
+	for _, filters := range filtersToValidate {
+		if len(filters) == 0 {
+			continue
+		}
+		b, invalidFields, err1 := c.Validate(obj, filters)
+		if err1 != nil {
+			return nil, &ErrController{
+				Op:  "ValidateFilters",
+				Err: fmt.Errorf("Error when trying to validate filters: %w", err1),
+			}
+		}
+
+		if !b {
+			c.logValidationFailure("Get", "ValidateFilters", obj, invalidFields)
+			return nil, &ErrController{
+				Op: "ValidateFilters",
+				Err: &ErrValidation{
+					Fields: invalidFields,
+				},
+			}
+		}
+	}
+	options.Filters = c.getFiltersWithGroups(options.Filters, options.FilterGroups)
+
+	var fieldsToSelect map[string]bool
+	if len(options.Fields) > 0 {
+		fieldsToSelect = map[string]bool{h.GetIDFieldName(): true}
+		for _, fieldName := range options.Fields {
+			if h.GetDBColFromFieldName(fieldName) == "" {
+				return nil, &ErrController{
+					Op:  "InvalidField",
+					Err: fmt.Errorf("field %s does not exist", fieldName),
+				}
+			}
+			fieldsToSelect[fieldName] = true
+		}
+	}
+
+	options.Order, err = c.getOrder(h, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.ForUpdate && options.ForShare {
+		return nil, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("ForUpdate and ForShare cannot be both set"),
+		}
+	}
+
+	var v []interface{}
+	var rows *sql.Rows
+	var err2 error
+	if options.Cursor != "" {
+		options.Order = c.getCursorOrder(h, options)
+		options.Filters, err = c.getKeysetFilters(h, obj, options.Filters, options.Order, options.Cursor)
+		if err != nil {
+			return nil, err
+		}
+	} else if (options.Limit > 0 || options.Offset > 0) && !options.DisableOrderTiebreaker {
+		options.Order = c.addOrderTiebreaker(options.Order, h.GetIDFieldName())
+	}
+
+	query := h.GetQuerySelect(options.Order, options.Limit, options.Offset, options.Filters, nil, nil)
+	if fieldsToSelect != nil {
+		query = h.GetQuerySelectFields(options.Fields, options.Order, options.Limit, options.Offset, options.Filters, nil, nil)
+	}
+	query += h.GetQueryLock(options.ForUpdate, options.ForShare)
+	if options.StatementTimeout > 0 {
+		tx, err := c.beginWithStatementTimeout(ctx, options.StatementTimeout)
+		if err != nil {
+			return nil, err
+		}
+		// Transaction is only used for reading so it is always rolled back
+		defer tx.Rollback()
+		query = c.rewriteQuery("Get", query)
+		args := c.getFilterArgs(obj, options.Filters)
+		rows, err2 = tx.QueryContext(ctx, query, args...)
+		err2 = newQueryError(err2, query, args)
+	} else {
+		rows, err2 = c.query(ctx, "Get", query, c.getFilterArgs(obj, options.Filters)...)
+	}
+	if err2 != nil {
+		return nil, c.newErrDBQuery(err2)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if c.maxRows > 0 && len(v) >= c.maxRows {
+			return nil, &ErrController{
+				Op:  "TooManyRows",
+				Err: fmt.Errorf("query returned more than %d rows", c.maxRows),
+			}
+		}
+
+		newObj := newObjFunc()
+		fieldInterfaces := c.GetObjFieldInterfaces(newObj, true)
+		if fieldsToSelect != nil {
+			fieldInterfaces = c.getObjFieldInterfacesForFields(newObj, fieldsToSelect)
+		}
+		err3 := rows.Scan(fieldInterfaces...)
+		if err3 != nil {
+			return nil, &ErrController{
+				Op:  "DBQueryRowsScan",
+				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
+			}
+		}
+
+		if options.HydrateJoined {
+			c.hydrateJoined(newObj)
+		}
+
+		// If options.RowObjTransformFunc is defined then call it on the row
+		if options.RowObjTransformFunc != nil {
+			v = append(v, options.RowObjTransformFunc(newObj))
+			continue
+		}
+
+		// Normal append
+		v = append(v, newObj)
+	}
+
+	// Query may fail after it started returning rows, eg. when statement timeout is reached
+	if err4 := rows.Err(); err4 != nil {
+		return nil, c.newErrDBQuery(err4)
+	}
+
+	return v, nil
+}
+
+// GetFirst runs the same query as Get but with Limit set to 1 and returns the only object. When nothing matches
+// the filters, it returns an error with Op 'NotExist' that wraps ErrNotExist
+func (c Controller) GetFirst(newObjFunc func() interface{}, options GetOptions) (interface{}, *ErrController) {
+	options.Limit = 1
+	objs, err := c.Get(newObjFunc, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(objs) == 0 {
+		return nil, &ErrController{
+			Op:  "NotExist",
+			Err: ErrNotExist,
 		}
-	default:
-		return nil
 	}
+	return objs[0], nil
 }
 
-// Delete removes object from the database table and it does that only when ID field is set (greater than 0).
-// Once deleted from the DB, all field values are zeroed
-// TODO: Error handling probably needs re-designing
-func (c Controller) Delete(obj interface{}, options DeleteOptions) *ErrController {
+// GetByExample runs the same query as Get with equality filters on all the fields of obj that are not zero, eg.
+// an object with only Email set finds objects with that email. Filters from options are added, and they take
+// precedence. Zero values, eg. 0 or false, cannot be searched for this way, options.Filters must be used instead.
+// Returned objects are of the same type as obj
+func (c Controller) GetByExample(obj interface{}, options GetOptions) ([]interface{}, *ErrController) {
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	id := c.GetObjIDValue(obj)
-
-	if id == 0 {
-		return nil
-	}
-	_, err2 := c.dbConn.Exec(h.GetQueryDeleteById(), c.GetObjIDInterface(obj))
-	if err2 != nil {
-		return &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err2),
-		}
-	}
-	c.ResetFields(obj)
+	options.Filters = c.getExampleFilters(h, obj, options.Filters)
 
-	// Loop through fields to delete cascade
-	err3 := c.runOnDelete(obj, c.tagName, []int64{id}, 0)
-	if err3 != nil {
-		return err3
-	}
+	t := reflect.TypeOf(obj).Elem()
+	return c.Get(func() interface{} { return reflect.New(t).Interface() }, options)
+}
 
-	return nil
+// GetCount runs a 'SELECT COUNT(*)' query on the database with specified filters, order, limit and offset and returns count of rows
+func (c Controller) GetCount(newObjFunc func() interface{}, options GetCountOptions) (int64, *ErrController) {
+	return c.GetCountContext(context.Background(), newObjFunc, options)
 }
 
-// DeleteMultiple removes objects from the database based on specified filters
-func (c Controller) DeleteMultiple(obj interface{}, options DeleteMultipleOptions) *ErrController {
+// GetCountContext does the same as GetCount but it runs queries with the context
+func (c Controller) GetCountContext(ctx context.Context, newObjFunc func() interface{}, options GetCountOptions) (int64, *ErrController) {
+	obj := newObjFunc()
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	// TODO: Enable validation once struct-validator support reflect.Value
 	if len(options.Filters) > 0 {
 		b, invalidFields, err1 := c.Validate(obj, options.Filters)
 		if err1 != nil {
-			return &ErrController{
+			return 0, &ErrController{
 				Op:  "ValidateFilters",
 				Err: fmt.Errorf("Error when trying to validate filters: %w", err1),
 			}
 		}
 
 		if !b {
-			return &ErrController{
+			c.logValidationFailure("GetCount", "ValidateFilters", obj, invalidFields)
+			return 0, &ErrController{
 				Op: "ValidateFilters",
 				Err: &ErrValidation{
 					Fields: invalidFields,
@@ -187,212 +1347,374 @@ func (c Controller) DeleteMultiple(obj interface{}, options DeleteMultipleOption
 		}
 	}
 
-	// Run DELETE query and get IDs of deleted rows
-	rows, err2 := c.dbConn.Query(h.GetQueryDeleteReturningID(options.Filters, nil), c.GetFiltersInterfaces(options.Filters)...)
-	if err2 != nil {
-		return &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err2),
-		}
-	}
-	defer rows.Close()
-
-	returnedIds := []int64{}
-
-	for rows.Next() {
-		var returnedId int64
-		err3 := rows.Scan(&returnedId)
+	if options.Approximate && len(options.Filters) == 0 && h.GetQuerySelectApproximateCount() != "" {
+		var cnt int64
+		err3 := c.queryRow(ctx, "GetCount", h.GetQuerySelectApproximateCount()).Scan(&cnt)
 		if err3 != nil {
-			return &ErrController{
-				Op:  "DBQueryRowsScan",
+			return 0, &ErrController{
+				Op:  "DBQueryRowScan",
 				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 			}
 		}
-
-		returnedIds = append(returnedIds, returnedId)
+		// Estimate is -1 (or 0 in PostgreSQL older than 14) when table has never been analyzed. Counting rows is
+		// cheap when table is actually empty
+		if cnt > 0 {
+			return cnt, nil
+		}
 	}
 
-	if options.CascadeDeleteDepth < 3 {
-		// Loop through fields to delete cascade
-		err3 := c.runOnDelete(obj, c.tagName, returnedIds, options.CascadeDeleteDepth)
-		if err3 != nil {
-			return err3
+	row := c.queryRow(ctx, "GetCount", h.GetQuerySelectCount(options.Filters, nil), c.getFilterArgs(obj, options.Filters)...)
+	var cnt int64
+	err3 := row.Scan(&cnt)
+	if err3 != nil {
+		return 0, &ErrController{
+			Op:  "DBQueryRowScan",
+			Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 		}
 	}
 
-	return nil
+	return cnt, nil
 }
 
-// UpdateMultiple updates specific fields in objects from the database based on specified filters
-func (c Controller) UpdateMultiple(obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) *ErrController {
+// Exists runs a 'SELECT EXISTS' query on the database with specified filters and returns true when any row matches
+// them. It is cheaper than GetCount because the database stops at the first matching row. Approximate in options
+// is ignored
+func (c Controller) Exists(newObjFunc func() interface{}, options GetCountOptions) (bool, *ErrController) {
+	return c.ExistsContext(context.Background(), newObjFunc, options)
+}
+
+// ExistsContext does the same as Exists but it runs query with the context
+func (c Controller) ExistsContext(ctx context.Context, newObjFunc func() interface{}, options GetCountOptions) (bool, *ErrController) {
+	obj := newObjFunc()
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	if len(values) < 1 {
-		return &ErrController{
-			Op:  "MissingValues",
-			Err: fmt.Errorf("missing values for update"),
+	err = c.validateFilters("Exists", obj, options.Filters)
+	if err != nil {
+		return false, err
+	}
+
+	var exists bool
+	err3 := c.queryRow(ctx, "Exists", h.GetQuerySelectExists(options.Filters, nil), c.getFilterArgs(obj, options.Filters)...).Scan(&exists)
+	if err3 != nil {
+		return false, &ErrController{
+			Op:  "DBQueryRowScan",
+			Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 		}
 	}
 
-	if options.ConvertValuesFromString {
-		values = c.StringToFieldValues(obj, values)
+	return exists, nil
+}
+
+// GetBooleanBreakdown runs a single 'SELECT COUNT(*)' query on the database with specified filters and returns count of
+// rows where bool field is true and count of rows where it is false
+func (c Controller) GetBooleanBreakdown(newObjFunc func() interface{}, fieldName string, options GetCountOptions) (int64, int64, *ErrController) {
+	return c.GetBooleanBreakdownContext(context.Background(), newObjFunc, fieldName, options)
+}
+
+// GetBooleanBreakdownContext does the same as GetBooleanBreakdown but it runs queries with the context
+func (c Controller) GetBooleanBreakdownContext(ctx context.Context, newObjFunc func() interface{}, fieldName string, options GetCountOptions) (int64, int64, *ErrController) {
+	obj := newObjFunc()
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return 0, 0, err
 	}
 
-	b, invalidFields, err1 := c.Validate(obj, values)
-	if err1 != nil {
-		return &ErrController{
-			Op:  "ValidateValues",
-			Err: fmt.Errorf("Error when trying to validate values: %w", err1),
+	if c.getFieldKind(obj, fieldName) != reflect.Bool {
+		return 0, 0, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s is not a bool", fieldName),
 		}
 	}
 
-	if !b {
-		return &ErrController{
-			Op: "ValidateValues",
-			Err: &ErrValidation{
-				Fields: invalidFields,
-			},
+	err = c.validateFilters("GetBooleanBreakdown", obj, options.Filters)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	row := c.queryRow(ctx, "GetBooleanBreakdown", h.GetQuerySelectCountTrueFalse(fieldName, options.Filters, nil), c.getFilterArgs(obj, options.Filters)...)
+	var cntTrue, cntFalse int64
+	err3 := row.Scan(&cntTrue, &cntFalse)
+	if err3 != nil {
+		return 0, 0, &ErrController{
+			Op:  "DBQueryRowScan",
+			Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 		}
 	}
 
-	if len(options.Filters) > 0 {
-		b, invalidFields, err1 := c.Validate(obj, options.Filters)
-		if err1 != nil {
-			return &ErrController{
-				Op:  "ValidateFilters",
-				Err: fmt.Errorf("Error when trying to validate filters: %w", err1),
-			}
+	return cntTrue, cntFalse, nil
+}
+
+// GetAggregate runs a single SELECT query with an aggregate function, eg. Sum, on a numeric field with specified
+// filters and returns its value, which is 0 when no rows match. Value is float64 for integer and float fields alike.
+// Sum of an integer field is exact as long as it is not greater than 2^53, and Avg is not rounded
+func (c Controller) GetAggregate(newObjFunc func() interface{}, fieldName string, fn AggregateFunc, options GetCountOptions) (float64, *ErrController) {
+	return c.GetAggregateContext(context.Background(), newObjFunc, fieldName, fn, options)
+}
+
+// GetAggregateContext does the same as GetAggregate but it runs queries with the context
+func (c Controller) GetAggregateContext(ctx context.Context, newObjFunc func() interface{}, fieldName string, fn AggregateFunc, options GetCountOptions) (float64, *ErrController) {
+	obj := newObjFunc()
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	if !fn.IsValid() {
+		return 0, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("aggregate function %s is not supported", fn),
 		}
+	}
 
-		if !b {
-			return &ErrController{
-				Op: "ValidateFilters",
-				Err: &ErrValidation{
-					Fields: invalidFields,
-				},
-			}
+	if h.GetDBColFromFieldName(fieldName) == "" || !c.isNumericKind(c.getFieldKind(obj, fieldName)) {
+		return 0, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s is not a numeric field", fieldName),
 		}
 	}
 
-	_, err2 := c.dbConn.Exec(h.GetQueryUpdate(values, options.Filters, nil, nil), append(c.GetFiltersInterfaces(values), c.GetFiltersInterfaces(options.Filters)...)...)
-	if err2 != nil {
-		return &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err2),
+	err = c.validateFilters("GetAggregate", obj, options.Filters)
+	if err != nil {
+		return 0, err
+	}
+
+	var v sql.NullFloat64
+	err3 := c.queryRow(ctx, "GetAggregate", h.GetQuerySelectAggregate(fn, fieldName, options.Filters, nil), c.getFilterArgs(obj, options.Filters)...).Scan(&v)
+	if err3 != nil {
+		return 0, &ErrController{
+			Op:  "DBQueryRowScan",
+			Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 		}
 	}
 
-	return nil
+	return v.Float64, nil
 }
 
-// Get runs a select query on the database with specified filters, order, limit and offset and returns a
-// list of objects
-func (c Controller) Get(newObjFunc func() interface{}, options GetOptions) ([]interface{}, *ErrController) {
-	obj := newObjFunc()
+// GetColumnValues runs a SELECT query on the database with specified filters, order, limit and offset and returns
+// only values of one field, eg. a list of IDs
+func (c Controller) GetColumnValues(newObjFunc func() interface{}, fieldName string, options GetOptions) ([]interface{}, *ErrController) {
+	return c.GetColumnValuesContext(context.Background(), newObjFunc, fieldName, options)
+}
 
+// GetColumnValuesContext does the same as GetColumnValues but it runs queries with the context
+func (c Controller) GetColumnValuesContext(ctx context.Context, newObjFunc func() interface{}, fieldName string, options GetOptions) ([]interface{}, *ErrController) {
+	obj := newObjFunc()
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
 		return nil, err
 	}
 
-	if len(options.Filters) > 0 {
-		b, invalidFields, err1 := c.Validate(obj, options.Filters)
-		if err1 != nil {
-			return nil, &ErrController{
-				Op:  "ValidateFilters",
-				Err: fmt.Errorf("Error when trying to validate filters: %w", err1),
-			}
-		}
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
 
-		if !b {
-			return nil, &ErrController{
-				Op: "ValidateFilters",
-				Err: &ErrValidation{
-					Fields: invalidFields,
-				},
-			}
-		}
+	options.Order, err = c.getOrder(h, options)
+	if err != nil {
+		return nil, err
+	}
+	if (options.Limit > 0 || options.Offset > 0) && !options.DisableOrderTiebreaker {
+		options.Order = c.addOrderTiebreaker(options.Order, h.GetIDFieldName())
 	}
 
-	var v []interface{}
-	rows, err2 := c.dbConn.Query(h.GetQuerySelect(options.Order, options.Limit, options.Offset, options.Filters, nil, nil), c.GetFiltersInterfaces(options.Filters)...)
-	if err2 != nil {
+	query := h.GetQuerySelectColumn(fieldName, options.Order, options.Limit, options.Offset, options.Filters, nil, nil)
+	fieldType, ok := reflect.Indirect(reflect.ValueOf(obj)).Type().FieldByName(fieldName)
+	if !ok || query == "" {
 		return nil, &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err2),
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s does not exist", fieldName),
 		}
 	}
+
+	err = c.validateFilters("GetColumnValues", obj, options.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err2 := c.query(ctx, "GetColumnValues", query, c.getFilterArgs(obj, options.Filters)...)
+	if err2 != nil {
+		return nil, c.newErrDBQuery(err2)
+	}
 	defer rows.Close()
 
+	var values []interface{}
 	for rows.Next() {
-		newObj := newObjFunc()
-		err3 := rows.Scan(c.GetObjFieldInterfaces(newObj, true)...)
+		v := reflect.New(fieldType.Type)
+		err3 := rows.Scan(v.Interface())
 		if err3 != nil {
 			return nil, &ErrController{
 				Op:  "DBQueryRowsScan",
 				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 			}
 		}
+		values = append(values, v.Elem().Interface())
+	}
 
-		// If options.RowObjTransformFunc is defined then call it on the row
-		if options.RowObjTransformFunc != nil {
-			v = append(v, options.RowObjTransformFunc(newObj))
-			continue
+	if err4 := rows.Err(); err4 != nil {
+		return nil, c.newErrDBQuery(err4)
+	}
+
+	return values, nil
+}
+
+// ExistingValues checks which of the values already exist in a column of specified field and returns a map with
+// true for every value that was found. Values are checked with 'SELECT ... WHERE ... IN (...)' queries, split into
+// batches
+func (c Controller) ExistingValues(newObjFunc func() interface{}, fieldName string, values []interface{}) (map[interface{}]bool, *ErrController) {
+	return c.ExistingValuesContext(context.Background(), newObjFunc, fieldName, values)
+}
+
+// ExistingValuesContext does the same as ExistingValues but it runs queries with the context
+func (c Controller) ExistingValuesContext(ctx context.Context, newObjFunc func() interface{}, fieldName string, values []interface{}) (map[interface{}]bool, *ErrController) {
+	obj := newObjFunc()
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	fieldType, ok := reflect.Indirect(reflect.ValueOf(obj)).Type().FieldByName(fieldName)
+	if !ok || h.GetDBColFromFieldName(fieldName) == "" {
+		return nil, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s does not exist", fieldName),
 		}
+	}
 
-		// Normal append
-		v = append(v, newObj)
+	found := map[interface{}]bool{}
+	for _, batch := range c.getBatches(len(values), 1, 0) {
+		existing, err := c.GetColumnValuesContext(ctx, newObjFunc, fieldName, GetOptions{
+			Filters: map[string]interface{}{
+				fieldName: values[batch[0]:batch[1]],
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range existing {
+			found[v] = true
+		}
 	}
 
-	return v, nil
+	// values returned from the database have the type of the field so passed values are converted to it
+	exist := make(map[interface{}]bool, len(values))
+	for _, v := range values {
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() || !rv.Type().ConvertibleTo(fieldType.Type) {
+			exist[v] = false
+			continue
+		}
+		exist[v] = found[rv.Convert(fieldType.Type).Interface()]
+	}
+
+	return exist, nil
 }
 
-// GetCount runs a 'SELECT COUNT(*)' query on the database with specified filters, order, limit and offset and returns count of rows
-func (c Controller) GetCount(newObjFunc func() interface{}, options GetCountOptions) (int64, *ErrController) {
+// GetFacet runs a 'SELECT COUNT(*)' query grouped by a field on the database with specified filters and returns
+// distinct values of the field with their counts, ordered from the most frequent one. For a nullable field values
+// are dereferenced, eg. int64 for 'Age *int64', and rows with NULL are counted under nil
+func (c Controller) GetFacet(newObjFunc func() interface{}, fieldName string, options GetCountOptions) ([]Facet, *ErrController) {
+	return c.GetFacetContext(context.Background(), newObjFunc, fieldName, options)
+}
+
+// GetFacetContext does the same as GetFacet but it runs queries with the context
+func (c Controller) GetFacetContext(ctx context.Context, newObjFunc func() interface{}, fieldName string, options GetCountOptions) ([]Facet, *ErrController) {
+	return c.getFacets(ctx, "GetFacet", newObjFunc(), fieldName, options)
+}
+
+// GetCountGrouped runs the same query as GetFacet and returns counts of rows keyed by distinct values of the field,
+// eg. number of persons per GroupID. Keys have the type of the field, eg. int64 for 'GroupID int64', and the element
+// type for a nullable field, with nil key for NULL
+func (c Controller) GetCountGrouped(newObjFunc func() interface{}, groupField string, options GetCountOptions) (map[interface{}]int64, *ErrController) {
+	return c.GetCountGroupedContext(context.Background(), newObjFunc, groupField, options)
+}
+
+// GetCountGroupedContext does the same as GetCountGrouped but it runs queries with the context
+func (c Controller) GetCountGroupedContext(ctx context.Context, newObjFunc func() interface{}, groupField string, options GetCountOptions) (map[interface{}]int64, *ErrController) {
 	obj := newObjFunc()
+	fieldType, ok := reflect.Indirect(reflect.ValueOf(obj)).Type().FieldByName(groupField)
+	if ok && !fieldType.Type.Comparable() {
+		return nil, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s cannot be grouped by", groupField),
+		}
+	}
+
+	facets, err := c.getFacets(ctx, "GetCountGrouped", obj, groupField, options)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[interface{}]int64, len(facets))
+	for _, facet := range facets {
+		counts[facet.Value] = facet.Count
+	}
+	return counts, nil
+}
+
+// getFacets gets distinct values of the field with their counts, for GetFacet and GetCountGrouped, where op is the
+// name of the method
+func (c Controller) getFacets(ctx context.Context, op string, obj interface{}, fieldName string, options GetCountOptions) ([]Facet, *ErrController) {
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	if len(options.Filters) > 0 {
-		b, invalidFields, err1 := c.Validate(obj, options.Filters)
-		if err1 != nil {
-			return 0, &ErrController{
-				Op:  "ValidateFilters",
-				Err: fmt.Errorf("Error when trying to validate filters: %w", err1),
-			}
+	query := h.GetQuerySelectFacet(fieldName, options.Filters, nil)
+	fieldType, ok := reflect.Indirect(reflect.ValueOf(obj)).Type().FieldByName(fieldName)
+	if !ok || query == "" {
+		return nil, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("field %s does not exist", fieldName),
 		}
+	}
 
-		if !b {
-			return 0, &ErrController{
-				Op: "ValidateFilters",
-				Err: &ErrValidation{
-					Fields: invalidFields,
-				},
+	err = c.validateFilters(op, obj, options.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err2 := c.query(ctx, op, query, c.getFilterArgs(obj, options.Filters)...)
+	if err2 != nil {
+		return nil, c.newErrDBQuery(err2)
+	}
+	defer rows.Close()
+
+	var facets []Facet
+	for rows.Next() {
+		v := reflect.New(fieldType.Type)
+		var cnt int64
+		err3 := rows.Scan(v.Interface(), &cnt)
+		if err3 != nil {
+			return nil, &ErrController{
+				Op:  "DBQueryRowsScan",
+				Err: fmt.Errorf("Error scanning DB query row: %w", err3),
 			}
 		}
+		// Values of nullable fields are dereferenced so that they can be compared with plain values, and NULL
+		// becomes nil
+		var value interface{}
+		if fieldType.Type.Kind() != reflect.Ptr {
+			value = v.Elem().Interface()
+		} else if !v.Elem().IsNil() {
+			value = v.Elem().Elem().Interface()
+		}
+		facets = append(facets, Facet{Value: value, Count: cnt})
 	}
 
-	row := c.dbConn.QueryRow(h.GetQuerySelectCount(options.Filters, nil), c.GetFiltersInterfaces(options.Filters)...)
-	var cnt int64
-	err3 := row.Scan(&cnt)
-	if err3 != nil {
-		return 0, &ErrController{
-			Op:  "DBQueryRowScan",
-			Err: fmt.Errorf("Error scanning DB query row: %w", err3),
-		}
+	if err4 := rows.Err(); err4 != nil {
+		return nil, c.newErrDBQuery(err4)
 	}
 
-	return cnt, nil
+	return facets, nil
 }
 
 // AddSQLGenerator adds StructSQL object to sqlGenerators
 func (c *Controller) AddSQLGenerator(obj interface{}, parentObj interface{}, overwrite bool, forceName string, parentOnlyRoot bool) *ErrController {
-	n := c.getSQLGeneratorName(obj, false)
+	n := c.getSQLGeneratorKey(obj)
 
 	// If sql generator already exists and it should not be overwritten then finish
 	if !overwrite {
@@ -417,6 +1739,7 @@ func (c *Controller) AddSQLGenerator(obj interface{}, parentObj interface{}, ove
 
 	h := stsql.NewStructSQL(obj, stsql.StructSQLOptions{
 		DatabaseTablePrefix: c.dbTblPrefix,
+		DatabaseTableSuffix: c.dbTblSuffix,
 		ForceName:           forceName,
 		Base:                sourceHelper,
 		TagName:             c.tagName,
@@ -459,7 +1782,12 @@ func (c *Controller) StringToFieldValues(obj interface{}, values map[string]inte
 			continue
 		}
 
-		switch field.Type.Kind() {
+		kind := field.Type.Kind()
+		if stsql.IsFieldNullable(field.Type) {
+			kind = field.Type.Elem().Kind()
+		}
+
+		switch kind {
 		case reflect.Int64, reflect.Uint64:
 			i, err := strconv.ParseInt(v.(string), 10, 64)
 			if err == nil {
@@ -1,6 +1,7 @@
 package structdbpostgres
 
 import (
+	"context"
 	"testing"
 )
 
@@ -161,6 +162,47 @@ func TestDeleteCascade(t *testing.T) {
 	}
 }
 
+// TestDeleteCascadeWithCancelledContext tests if nothing is deleted when ctx is cancelled in the middle of cascade
+func TestDeleteCascadeWithCancelledContext(t *testing.T) {
+	p := createTestDelParentWithChildren()
+
+	// Cancel ctx right before the first update of children, when the parent and some children are already deleted
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{
+		QueryRewriter: func(op string, query string) string {
+			if op == "UpdateMultiple" {
+				cancel()
+			}
+			return query
+		},
+	})
+
+	err := c.DeleteContext(ctx, p, DeleteOptions{})
+	if err == nil {
+		t.Fatalf("DeleteContext failed to return error when ctx was cancelled during cascade")
+	}
+	if p.(*DelParent).ID != 1 {
+		t.Fatalf("DeleteContext zeroed fields of object that was not deleted")
+	}
+
+	var cnt int
+	err2 := dbConn.QueryRow("SELECT COUNT(*) FROM struct2db_del_parents").Scan(&cnt)
+	if err2 != nil {
+		t.Fatalf("Failed to select count: %s", err2.Error())
+	}
+	if cnt != 1 {
+		t.Fatalf("DeleteContext failed to roll back delete of the parent")
+	}
+	err2 = dbConn.QueryRow("SELECT COUNT(*) FROM struct2db_del_child_deletes WHERE del_child_delete_id IN (1, 2, 111, 121, 211, 221, 112, 122, 212, 222, 1001, 1003) AND del_parent_id != 0").Scan(&cnt)
+	if err2 != nil {
+		t.Fatalf("Failed to select count: %s", err2.Error())
+	}
+	if cnt != 12 {
+		t.Fatalf("DeleteContext failed to roll back cascade delete of children")
+	}
+}
+
 func createTestDelParentWithChildren() interface{} {
 	recreateTestDelTables()
 
@@ -219,3 +261,49 @@ func recreateTestDelTables() {
 	testController.CreateTable(&DelChildDelete{})
 	testController.CreateTable(&DelChildUpdate{})
 }
+
+// Test structs for cascade delete of many objects
+type BatchParent struct {
+	ID         int64
+	Name       string
+	BatchItems []*BatchItem `2db:"on_del:del"`
+}
+
+type BatchItem struct {
+	ID            int64
+	BatchParentID int64
+}
+
+// TestDeleteCascadeWithManyIDs tests if cascade delete works when there are more deleted parents than the limit of
+// query parameters
+func TestDeleteCascadeWithManyIDs(t *testing.T) {
+	testController.DropTables(&BatchParent{}, &BatchItem{})
+	testController.CreateTables(&BatchParent{}, &BatchItem{})
+
+	_, err := dbConn.Exec("INSERT INTO struct2db_batch_parents(name) SELECT 'parent' FROM generate_series(1, 70000)")
+	if err != nil {
+		t.Fatalf("Failed to insert test parents: %s", err.Error())
+	}
+	_, err = dbConn.Exec("INSERT INTO struct2db_batch_items(batch_parent_id) SELECT g FROM generate_series(1, 70000) g")
+	if err != nil {
+		t.Fatalf("Failed to insert test children: %s", err.Error())
+	}
+
+	errCtl := testController.DeleteMultiple(&BatchParent{}, DeleteMultipleOptions{
+		Filters: map[string]interface{}{
+			"Name": "parent",
+		},
+	})
+	if errCtl != nil {
+		t.Fatalf("DeleteMultiple failed to delete objects with their children: %s", errCtl.Op)
+	}
+
+	var cnt int
+	err = dbConn.QueryRow("SELECT COUNT(*) FROM struct2db_batch_items").Scan(&cnt)
+	if err != nil {
+		t.Fatalf("Failed to select count: %s", err.Error())
+	}
+	if cnt != 0 {
+		t.Fatalf("DeleteMultiple failed to delete children, there are %d rows left", cnt)
+	}
+}
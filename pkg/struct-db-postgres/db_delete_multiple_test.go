@@ -131,3 +131,119 @@ func TestDeleteMultipleWithRawQueryOnly(t *testing.T) {
 		t.Fatalf("DeleteMultiple removed invalid number of rows, there are %d rows left, instead of %d", cnt, 46)
 	}
 }
+
+// TestDeleteMultipleWithoutFilters tests if DeleteMultiple refuses to remove all the rows when no filters are
+// passed, unless AllowFullTableUpdate is set
+func TestDeleteMultipleWithoutFilters(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		testController.Save(ts, SaveOptions{})
+	}
+
+	err := testController.DeleteMultiple(&TestStruct{}, DeleteMultipleOptions{})
+	if err == nil || err.Op != "UnsafeFullTable" {
+		t.Fatalf("DeleteMultiple failed to refuse removing all rows without filters")
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 10 {
+		t.Fatalf("DeleteMultiple removed rows without filters, there are %d rows left, instead of %d", cnt, 10)
+	}
+
+	err = testController.DeleteMultiple(&TestStruct{}, DeleteMultipleOptions{
+		AllowFullTableUpdate: true,
+	})
+	if err != nil {
+		t.Fatalf("DeleteMultiple failed to remove all rows when AllowFullTableUpdate is set: %s", err.Op)
+	}
+
+	cnt, _ = testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("DeleteMultiple removed invalid number of rows, there are %d rows left, instead of %d", cnt, 0)
+	}
+}
+
+// TestCountMatching tests if CountMatching returns the same number of rows that DeleteMultiple deletes with the
+// same filters
+func TestCountMatching(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 101; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = i
+		if i%4 == 0 {
+			ts.PrimaryEmail = "another@example.com"
+		}
+		testController.Save(ts, SaveOptions{})
+	}
+
+	options := DeleteMultipleOptions{
+		Filters: map[string]interface{}{
+			"Age":          Gt(20),
+			"PrimaryEmail": ILike("PRIMARY%"),
+			"_raw":         []interface{}{".Age < ?", 80},
+		},
+	}
+
+	preview, err := testController.CountMatching(&TestStruct{}, options)
+	if err != nil {
+		t.Fatalf("CountMatching failed to count objects: %s", err.Op)
+	}
+	if preview != 45 {
+		t.Fatalf("CountMatching returned invalid number of rows, want %d, got %d", 45, preview)
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	cntBefore, _ := testController.GetCount(newObjFunc, GetCountOptions{})
+	err = testController.DeleteMultiple(&TestStruct{}, options)
+	if err != nil {
+		t.Fatalf("DeleteMultiple failed to delete objects: %s", err.Op)
+	}
+	cntAfter, _ := testController.GetCount(newObjFunc, GetCountOptions{})
+	if cntBefore-cntAfter != preview {
+		t.Fatalf("CountMatching returned %d rows, while DeleteMultiple deleted %d", preview, cntBefore-cntAfter)
+	}
+
+	_, err = testController.CountMatching(&TestStruct{}, DeleteMultipleOptions{})
+	if err == nil || err.Op != "UnsafeFullTable" {
+		t.Fatalf("CountMatching failed to return the same error as DeleteMultiple without filters")
+	}
+}
+
+// TestDeleteMultipleCount tests if DeleteMultipleCount returns number of deleted rows
+func TestDeleteMultipleCount(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	cnt, err := testController.DeleteMultipleCount(&TestStruct{}, DeleteMultipleOptions{
+		Filters: map[string]interface{}{"Age": Gt(15)},
+	})
+	if err != nil {
+		t.Fatalf("DeleteMultipleCount failed to delete objects: %s", err.Op)
+	}
+	if cnt != 5 {
+		t.Fatalf("DeleteMultipleCount returned invalid number of deleted rows, want %d, got %d", 5, cnt)
+	}
+
+	cnt, err = testController.DeleteMultipleCount(&TestStruct{}, DeleteMultipleOptions{
+		Filters: map[string]interface{}{"Age": Gt(15)},
+	})
+	if err != nil || cnt != 0 {
+		t.Fatalf("DeleteMultipleCount failed to return 0 when nothing is deleted")
+	}
+
+	_, err = testController.DeleteMultipleCount(&TestStruct{}, DeleteMultipleOptions{})
+	if err == nil || err.Op != "UnsafeFullTable" {
+		t.Fatalf("DeleteMultipleCount failed to refuse deleting all rows without filters")
+	}
+}
@@ -0,0 +1,39 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// TestExistingValues tests if ExistingValues reports which of the values exist in the database
+func TestExistingValues(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 6; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	// small batch size makes values to be checked in several queries
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{BatchSize: 2})
+	exist, err := c.ExistingValues(newObjFunc, "Age", []interface{}{9, 11, 13, 15, 17})
+	if err != nil {
+		t.Fatalf("ExistingValues failed to check values: %s", err.Op)
+	}
+	if len(exist) != 5 || exist[9] || !exist[11] || !exist[13] || !exist[15] || exist[17] {
+		t.Fatalf("ExistingValues returned invalid result: %v", exist)
+	}
+
+	exist, err = testController.ExistingValues(newObjFunc, "Age", []interface{}{})
+	if err != nil || len(exist) != 0 {
+		t.Fatalf("ExistingValues failed to handle empty list of values")
+	}
+
+	_, err = testController.ExistingValues(newObjFunc, "Missing", []interface{}{1})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("ExistingValues failed to return error for a field that does not exist")
+	}
+}
@@ -0,0 +1,65 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// Test struct for GetAggregate
+type AggregateTestStruct struct {
+	ID     int64
+	Status string
+	Price  int
+	Rating float64
+}
+
+// TestGetAggregate tests if GetAggregate returns values of aggregate functions on rows matching filters
+func TestGetAggregate(t *testing.T) {
+	testController.DropTable(&AggregateTestStruct{})
+	testController.CreateTable(&AggregateTestStruct{})
+
+	for i := 1; i < 5; i++ {
+		testController.Save(&AggregateTestStruct{Status: "paid", Price: i * 10, Rating: float64(i) / 2}, SaveOptions{})
+	}
+	testController.Save(&AggregateTestStruct{Status: "new", Price: 1000}, SaveOptions{})
+
+	newObjFunc := func() interface{} { return &AggregateTestStruct{} }
+	options := GetCountOptions{Filters: map[string]interface{}{"Status": "paid"}}
+
+	tests := []struct {
+		fn    AggregateFunc
+		field string
+		want  float64
+	}{
+		{Sum, "Price", 100},
+		{Avg, "Price", 25},
+		{Min, "Price", 10},
+		{Max, "Price", 40},
+		{Sum, "Rating", 5},
+	}
+	for _, tt := range tests {
+		got, err := testController.GetAggregate(newObjFunc, tt.field, tt.fn, options)
+		if err != nil {
+			t.Fatalf("GetAggregate failed to return %s of %s: %s", tt.fn, tt.field, err.Op)
+		}
+		if got != tt.want {
+			t.Fatalf("GetAggregate returned invalid %s of %s, want %v, got %v", tt.fn, tt.field, tt.want, got)
+		}
+	}
+
+	got, err := testController.GetAggregate(newObjFunc, "Price", Sum, GetCountOptions{
+		Filters: map[string]interface{}{"Status": "cancelled"},
+	})
+	if err != nil || got != 0 {
+		t.Fatalf("GetAggregate failed to return 0 when no rows match")
+	}
+
+	_, err = testController.GetAggregate(newObjFunc, "Status", Sum, options)
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetAggregate failed to return error for a field that is not numeric")
+	}
+
+	_, err = testController.GetAggregate(newObjFunc, "Price", AggregateFunc("COUNT"), options)
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("GetAggregate failed to return error for an unsupported function")
+	}
+}
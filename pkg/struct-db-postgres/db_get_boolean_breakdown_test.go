@@ -0,0 +1,64 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// Test struct for GetBooleanBreakdown
+type BreakdownTestStruct struct {
+	ID     int64
+	Active bool
+	Age    int
+}
+
+// TestGetBooleanBreakdown tests if GetBooleanBreakdown properly gets count of rows with true and false values, filtered
+func TestGetBooleanBreakdown(t *testing.T) {
+	testController.DropTable(&BreakdownTestStruct{})
+	testController.CreateTable(&BreakdownTestStruct{})
+
+	for i := 1; i < 51; i++ {
+		ts := &BreakdownTestStruct{
+			Active: i%5 == 0,
+			Age:    30,
+		}
+		testController.Save(ts, SaveOptions{})
+	}
+
+	// Insert data that should be ignored by filters
+	for i := 1; i < 11; i++ {
+		ts := &BreakdownTestStruct{
+			Active: true,
+			Age:    40,
+		}
+		testController.Save(ts, SaveOptions{})
+	}
+
+	cntTrue, cntFalse, err := testController.GetBooleanBreakdown(func() interface{} {
+		return &BreakdownTestStruct{}
+	}, "Active", GetCountOptions{
+		Filters: map[string]interface{}{"Age": 30},
+	})
+	if err != nil {
+		t.Fatalf("GetBooleanBreakdown failed to return counts: %s", err.Op)
+	}
+	if cntTrue != 10 || cntFalse != 40 {
+		t.Fatalf("GetBooleanBreakdown failed to return counts, want %v and %v, got %v and %v", 10, 40, cntTrue, cntFalse)
+	}
+
+	cntTrue, cntFalse, err = testController.GetBooleanBreakdown(func() interface{} {
+		return &BreakdownTestStruct{}
+	}, "Active", GetCountOptions{
+		Filters:           map[string]interface{}{"Age": 0},
+		IgnoreZeroFilters: true,
+	})
+	if err != nil || cntTrue != 20 || cntFalse != 40 {
+		t.Fatalf("GetBooleanBreakdown failed to ignore zero filters, want %v and %v, got %v and %v", 20, 40, cntTrue, cntFalse)
+	}
+
+	_, _, err = testController.GetBooleanBreakdown(func() interface{} {
+		return &BreakdownTestStruct{}
+	}, "Age", GetCountOptions{})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetBooleanBreakdown failed to return error for a field that is not a bool")
+	}
+}
@@ -0,0 +1,71 @@
+package structdbpostgres
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGetChangedSince tests if GetChangedSince returns all the updated objects page by page, also when many of them
+// were updated at the same time, and nothing after the last watermark
+func TestGetChangedSince(t *testing.T) {
+	testController.DropTable(&TimestampTestStruct{})
+	testController.CreateTable(&TimestampTestStruct{})
+
+	for i := 0; i < 5; i++ {
+		testController.Save(&TimestampTestStruct{Name: "Name"}, SaveOptions{})
+	}
+
+	// three objects updated at the same time are split between pages with limit of 2
+	sameTime := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	testController.UpdateMultiple(&TimestampTestStruct{}, map[string]interface{}{"UpdatedAt": sameTime}, UpdateMultipleOptions{
+		Filters: map[string]interface{}{"ID": []int64{2, 3, 4}},
+	})
+
+	newObjFunc := func() interface{} { return &TimestampTestStruct{} }
+
+	ids := []int64{}
+	wm := Watermark{}
+	for page := 0; page < 5; page++ {
+		xobj, newWm, err := testController.GetChangedSince(newObjFunc, wm, 2)
+		if err != nil {
+			t.Fatalf("GetChangedSince failed to return objects: %s", err.Op)
+		}
+		if len(xobj) == 0 {
+			if newWm != wm {
+				t.Fatalf("GetChangedSince changed watermark when there are no objects")
+			}
+			break
+		}
+		for _, obj := range xobj {
+			ids = append(ids, obj.(*TimestampTestStruct).ID)
+		}
+		wm = newWm
+	}
+
+	want := []int64{1, 5, 2, 3, 4}
+	if len(ids) != len(want) {
+		t.Fatalf("GetChangedSince returned invalid objects, want %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("GetChangedSince returned invalid objects, want %v, got %v", want, ids)
+		}
+	}
+	if !wm.UpdatedAt.Equal(sameTime) || wm.ID != 4 {
+		t.Fatalf("GetChangedSince returned invalid watermark %v", wm)
+	}
+
+	_, _, err := testController.GetChangedSince(func() interface{} { return &TestStruct{} }, Watermark{}, 2)
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetChangedSince failed to return error for struct without 'updated_at' field")
+	}
+
+	type StringKeyTimestampTestStruct struct {
+		Uuid      string    `2db:"pk"`
+		UpdatedAt time.Time `2db:"updated_at"`
+	}
+	_, _, err = testController.GetChangedSince(func() interface{} { return &StringKeyTimestampTestStruct{} }, Watermark{}, 2)
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetChangedSince failed to return error for struct with string primary key")
+	}
+}
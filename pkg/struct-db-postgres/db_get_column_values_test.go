@@ -0,0 +1,49 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// TestGetColumnValues tests if GetColumnValues returns values of a single field from the database
+func TestGetColumnValues(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	values, err := testController.GetColumnValues(newObjFunc, "Age", GetOptions{
+		Order: []string{"Age", "desc"},
+		Limit: 5,
+		Filters: map[string]interface{}{
+			"Price": 444,
+		},
+	})
+	if err != nil {
+		t.Fatalf("GetColumnValues failed to return values: %s", err.Op)
+	}
+	if len(values) != 5 {
+		t.Fatalf("GetColumnValues returned invalid number of values, want %v, got %v", 5, len(values))
+	}
+	if values[0].(int) != 30 || values[4].(int) != 26 {
+		t.Fatalf("GetColumnValues returned invalid values: %v", values)
+	}
+
+	values, err = testController.GetColumnValues(newObjFunc, "ID", GetOptions{})
+	if err != nil || len(values) != 20 {
+		t.Fatalf("GetColumnValues failed to return all IDs")
+	}
+	if _, ok := values[0].(int64); !ok {
+		t.Fatalf("GetColumnValues failed to return values of field type")
+	}
+
+	_, err = testController.GetColumnValues(newObjFunc, "Missing", GetOptions{})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetColumnValues failed to return error for a field that does not exist")
+	}
+}
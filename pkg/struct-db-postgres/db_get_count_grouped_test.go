@@ -0,0 +1,73 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// Test struct for GetCountGrouped
+type CountGroupedTestStruct struct {
+	ID      int64
+	GroupID int64
+	Active  bool
+}
+
+// TestGetCountGrouped tests if GetCountGrouped returns counts of rows keyed by values of the field, filtered
+func TestGetCountGrouped(t *testing.T) {
+	testController.DropTable(&CountGroupedTestStruct{})
+	testController.CreateTable(&CountGroupedTestStruct{})
+
+	for i := 0; i < 9; i++ {
+		testController.Save(&CountGroupedTestStruct{GroupID: int64(i%3 + 1), Active: i < 6}, SaveOptions{})
+	}
+	testController.Save(&CountGroupedTestStruct{GroupID: 1, Active: true}, SaveOptions{})
+
+	newObjFunc := func() interface{} { return &CountGroupedTestStruct{} }
+
+	counts, err := testController.GetCountGrouped(newObjFunc, "GroupID", GetCountOptions{})
+	if err != nil {
+		t.Fatalf("GetCountGrouped failed to return counts: %s", err.Op)
+	}
+	if len(counts) != 3 || counts[int64(1)] != 4 || counts[int64(2)] != 3 || counts[int64(3)] != 3 {
+		t.Fatalf("GetCountGrouped returned invalid counts: %v", counts)
+	}
+
+	counts, err = testController.GetCountGrouped(newObjFunc, "Active", GetCountOptions{
+		Filters: map[string]interface{}{"GroupID": 1},
+	})
+	if err != nil || len(counts) != 2 || counts[true] != 3 || counts[false] != 1 {
+		t.Fatalf("GetCountGrouped failed to return counts with filters: %v", counts)
+	}
+
+	_, err = testController.GetCountGrouped(newObjFunc, "Missing", GetCountOptions{})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetCountGrouped failed to return error for a field that does not exist")
+	}
+}
+
+// TestGetCountGroupedWithNullableField tests if GetCountGrouped returns plain values as keys for a nullable field,
+// and nil key for NULL
+func TestGetCountGroupedWithNullableField(t *testing.T) {
+	testController.DropTable(&NullableTestStruct{})
+	testController.CreateTable(&NullableTestStruct{})
+
+	age := int64(30)
+	for i := 0; i < 3; i++ {
+		testController.Save(&NullableTestStruct{Name: "Aged", Age: &age}, SaveOptions{})
+	}
+	testController.Save(&NullableTestStruct{Name: "Unknown"}, SaveOptions{})
+
+	newObjFunc := func() interface{} { return &NullableTestStruct{} }
+
+	counts, err := testController.GetCountGrouped(newObjFunc, "Age", GetCountOptions{})
+	if err != nil {
+		t.Fatalf("GetCountGrouped failed to return counts for a nullable field: %s", err.Op)
+	}
+	if len(counts) != 2 || counts[int64(30)] != 3 || counts[nil] != 1 {
+		t.Fatalf("GetCountGrouped returned invalid counts for a nullable field: %v", counts)
+	}
+
+	facets, err := testController.GetFacet(newObjFunc, "Age", GetCountOptions{})
+	if err != nil || len(facets) != 2 || facets[0].Value != int64(30) || facets[0].Count != 3 || facets[1].Value != nil {
+		t.Fatalf("GetFacet returned invalid facets for a nullable field: %v", facets)
+	}
+}
@@ -84,3 +84,85 @@ func TestGetCountWithRawQuery(t *testing.T) {
 		t.Fatalf("Get failed to return list of objects, want %v, got %v", 153, cnt)
 	}
 }
+
+// TestGetCountApproximate tests if GetCount returns estimated number of rows when Approximate is set
+func TestGetCountApproximate(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 101; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i%50
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	// there are no statistics before the table is analyzed, so rows are counted
+	cnt, err := testController.GetCount(newObjFunc, GetCountOptions{Approximate: true})
+	if err != nil || cnt != 100 {
+		t.Fatalf("GetCount failed to count rows when there is no estimate, want %v, got %v", 100, cnt)
+	}
+
+	dbConn.Exec("ANALYZE struct2db_test_structs")
+
+	cnt, err = testController.GetCount(newObjFunc, GetCountOptions{Approximate: true})
+	if err != nil {
+		t.Fatalf("GetCount failed to return approximate count: %s", err.Op)
+	}
+	if cnt != 100 {
+		t.Fatalf("GetCount failed to return approximate count after analyze, want %v, got %v", 100, cnt)
+	}
+
+	// with filters, rows are always counted
+	cnt, err = testController.GetCount(newObjFunc, GetCountOptions{
+		Approximate: true,
+		Filters:     map[string]interface{}{"Age": 11},
+	})
+	if err != nil || cnt != 2 {
+		t.Fatalf("GetCount failed to count rows with filters, want %v, got %v", 2, cnt)
+	}
+}
+
+// TestExists tests if Exists returns true only when there is a row matching filters
+func TestExists(t *testing.T) {
+	recreateTestStructTable()
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	exists, err := testController.Exists(newObjFunc, GetCountOptions{})
+	if err != nil || exists {
+		t.Fatalf("Exists failed to return false for empty table")
+	}
+
+	for i := 1; i < 4; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	exists, err = testController.Exists(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Age": 32, "PrimaryEmail": "primary@example.com"},
+	})
+	if err != nil {
+		t.Fatalf("Exists failed: %s", err.Op)
+	}
+	if !exists {
+		t.Fatalf("Exists failed to return true for matching row")
+	}
+
+	exists, _ = testController.Exists(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Age": 40},
+	})
+	if exists {
+		t.Fatalf("Exists returned true when no row matches filters")
+	}
+
+	_, err = testController.Exists(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Age": 500},
+	})
+	if err == nil || err.Op != "ValidateFilters" {
+		t.Fatalf("Exists failed to return error for invalid filter")
+	}
+}
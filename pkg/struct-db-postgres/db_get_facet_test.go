@@ -0,0 +1,60 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// Test struct for GetFacet
+type FacetTestStruct struct {
+	ID       int64
+	Category string
+	Price    int
+}
+
+// TestGetFacet tests if GetFacet returns distinct values of a field with their counts, ordered by count
+func TestGetFacet(t *testing.T) {
+	testController.DropTable(&FacetTestStruct{})
+	testController.CreateTable(&FacetTestStruct{})
+
+	categories := map[string]int{"Books": 5, "Games": 2, "Music": 7}
+	for category, cnt := range categories {
+		for i := 0; i < cnt; i++ {
+			testController.Save(&FacetTestStruct{Category: category, Price: 10 + i}, SaveOptions{})
+		}
+	}
+
+	newObjFunc := func() interface{} { return &FacetTestStruct{} }
+
+	facets, err := testController.GetFacet(newObjFunc, "Category", GetCountOptions{})
+	if err != nil {
+		t.Fatalf("GetFacet failed to return facets: %s", err.Op)
+	}
+	if len(facets) != 3 {
+		t.Fatalf("GetFacet returned invalid number of facets: %d", len(facets))
+	}
+	if facets[0].Value.(string) != "Music" || facets[0].Count != 7 || facets[1].Value.(string) != "Books" || facets[1].Count != 5 || facets[2].Value.(string) != "Games" || facets[2].Count != 2 {
+		t.Fatalf("GetFacet returned invalid facets: %v", facets)
+	}
+
+	facets, err = testController.GetFacet(newObjFunc, "Category", GetCountOptions{
+		Filters: map[string]interface{}{
+			"Price": 11,
+		},
+	})
+	if err != nil || len(facets) != 3 || facets[0].Count != 1 {
+		t.Fatalf("GetFacet failed to return facets with filters: %v", facets)
+	}
+
+	facets, err = testController.GetFacet(newObjFunc, "Category", GetCountOptions{
+		Filters:            map[string]interface{}{"Category": ""},
+		IgnoreEmptyFilters: true,
+	})
+	if err != nil || len(facets) != 3 {
+		t.Fatalf("GetFacet failed to ignore empty filters: %v", facets)
+	}
+
+	_, err = testController.GetFacet(newObjFunc, "Missing", GetCountOptions{})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("GetFacet failed to return error for a field that does not exist")
+	}
+}
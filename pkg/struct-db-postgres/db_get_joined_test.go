@@ -121,6 +121,42 @@ func TestJoinedGet(t *testing.T) {
 	}
 }
 
+func TestJoinedGetWithHydration(t *testing.T) {
+	createTestJoinedStructs()
+
+	ps, err := testController.Get(func() interface{} {
+		return &Product_WithDetails{}
+	}, GetOptions{
+		Filters: map[string]interface{}{
+			"Name": "Product Name",
+		},
+		HydrateJoined: true,
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return list of joined structs with hydration: %s", err.Op)
+	}
+	if len(ps) != 1 {
+		t.Fatalf("Get failed to return list of joined structs with hydration, want %v, got %v", 1, len(ps))
+	}
+
+	p := ps[0].(*Product_WithDetails)
+	if p.ProductKind == nil || p.ProductKind.ID != 33 || p.ProductKind.Name != "Kind 1" {
+		t.Fatalf("Get failed to hydrate joined struct: %v", p.ProductKind)
+	}
+	if p.ProductGrp == nil || p.ProductGrp.ID != 113 || p.ProductGrp.Code != "GRP1" || p.ProductGrp.Name != "" {
+		t.Fatalf("Get failed to hydrate joined struct: %v", p.ProductGrp)
+	}
+
+	p2 := &Product_WithDetails{}
+	err = testController.Load(p2, fmt.Sprintf("%d", 6), LoadOptions{HydrateJoined: true})
+	if err != nil {
+		t.Fatalf("Load failed to get data for struct with hydration: %s", err.Op)
+	}
+	if p2.ProductKind == nil || p2.ProductKind.Name != "Kind 1" || p2.ProductGrp == nil || p2.ProductGrp.Code != "GRP1" {
+		t.Fatalf("Load failed to hydrate joined structs")
+	}
+}
+
 func createTestJoinedStructs() {
 	recreateTestJoinedStructTables()
 
@@ -156,3 +192,42 @@ func recreateTestJoinedStructTables() {
 	testController.CreateTable(&ProductGroup{})
 	testController.CreateTable(&ProductKind{})
 }
+
+// Test structs for joined struct with a primary key other than ID
+type JoinedWarehouse struct {
+	Code string `2db:"pk"`
+	City string
+}
+
+type JoinedStock struct {
+	ID                int64
+	Quantity          int
+	JoinedWarehouseID string
+}
+
+type JoinedStock_WithWarehouse struct {
+	ID                   int64
+	Quantity             int
+	JoinedWarehouseID    string
+	JoinedWarehouse      *JoinedWarehouse `2db:"join"`
+	JoinedWarehouse_City string
+}
+
+// TestJoinedGetWithPrimaryKey tests if joined struct with 'pk' tag is joined and hydrated on its primary key
+func TestJoinedGetWithPrimaryKey(t *testing.T) {
+	testController.DropTables(&JoinedWarehouse{}, &JoinedStock{})
+	testController.CreateTables(&JoinedWarehouse{}, &JoinedStock{})
+	testController.Save(&JoinedWarehouse{Code: "WAW1", City: "Warsaw"}, SaveOptions{})
+	testController.Save(&JoinedStock{Quantity: 5, JoinedWarehouseID: "WAW1"}, SaveOptions{})
+
+	xobj, err := testController.Get(func() interface{} {
+		return &JoinedStock_WithWarehouse{}
+	}, GetOptions{HydrateJoined: true})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("Get failed to return joined struct with primary key")
+	}
+	s := xobj[0].(*JoinedStock_WithWarehouse)
+	if s.JoinedWarehouse_City != "Warsaw" || s.JoinedWarehouse == nil || s.JoinedWarehouse.Code != "WAW1" {
+		t.Fatalf("Get failed to hydrate joined struct with primary key: %v", s.JoinedWarehouse)
+	}
+}
@@ -0,0 +1,49 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// TestGetPage tests if GetPage returns objects of a page and the total number of objects matching filters
+func TestGetPage(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 26; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	options := GetOptions{
+		Order:   []string{"Age", "asc"},
+		Filters: map[string]interface{}{"Age": Gt(3)},
+	}
+
+	items, total, err := testController.GetPage(newObjFunc, 3, 10, options)
+	if err != nil {
+		t.Fatalf("GetPage failed to return a page: %s", err.Op)
+	}
+	if total != 22 {
+		t.Fatalf("GetPage returned invalid total, want %d, got %d", 22, total)
+	}
+	if len(items) != 2 || items[0].(*TestStruct).Age != 24 || items[1].(*TestStruct).Age != 25 {
+		t.Fatalf("GetPage returned invalid objects of the last page")
+	}
+
+	items, total, err = testController.GetPage(newObjFunc, 4, 10, options)
+	if err != nil || len(items) != 0 || total != 22 {
+		t.Fatalf("GetPage failed to return an empty page after the last one")
+	}
+
+	_, _, err = testController.GetPage(newObjFunc, 0, 10, options)
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("GetPage failed to return error for page 0")
+	}
+
+	_, _, err = testController.GetPage(newObjFunc, 1, 0, options)
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("GetPage failed to return error for perPage 0")
+	}
+}
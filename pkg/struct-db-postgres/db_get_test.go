@@ -1,11 +1,14 @@
 package structdbpostgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"html"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestGet tests if Get properly gets many objects from the database, filtered and ordered, with results limited to specific number
@@ -139,3 +142,794 @@ func TestGetWithRowObjTransformFunc(t *testing.T) {
 		t.Fatalf("Get with transform func returned invalid objects")
 	}
 }
+
+// TestGetWithRawValueFilter tests if Get properly gets objects from the database when filter value is a raw SQL expression
+func TestGetWithRawValueFilter(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 51; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	testStructs, err := testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Filters: map[string]interface{}{
+			"Age":          Raw("15+15"),
+			"PrimaryEmail": "primary@example.com",
+		},
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return list of objects: %s", err.Op)
+	}
+	if len(testStructs) != 1 {
+		t.Fatalf("Get failed to return list of objects, want %v, got %v", 1, len(testStructs))
+	}
+	if testStructs[0].(*TestStruct).Age != 30 {
+		t.Fatalf("Get failed to return correct list of objects, want %v, got %v", 30, testStructs[0].(*TestStruct).Age)
+	}
+}
+
+// TestGetWithStatementTimeout tests if Get query is aborted by the database when it takes longer than StatementTimeout
+func TestGetWithStatementTimeout(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 6; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		testController.Save(ts, SaveOptions{})
+	}
+
+	testStructs, err := testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		StatementTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return list of objects: %s", err.Op)
+	}
+	if len(testStructs) != 5 {
+		t.Fatalf("Get failed to return list of objects, want %v, got %v", 5, len(testStructs))
+	}
+
+	_, err = testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Filters: map[string]interface{}{
+			"_raw": []interface{}{
+				".ID > ? AND (SELECT true FROM pg_sleep(2))",
+				0,
+			},
+		},
+		StatementTimeout: 100 * time.Millisecond,
+	})
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("Get failed to abort query exceeding statement timeout")
+	}
+}
+
+// TestGetWithIgnoreEmptyFilters tests if Get skips filters with empty values when requested
+func TestGetWithIgnoreEmptyFilters(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	testStructs, err := testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Filters: map[string]interface{}{
+			"FirstName": "John",
+			"LastName":  "",
+			"Price":     0,
+		},
+		IgnoreEmptyFilters: true,
+		IgnoreZeroFilters:  true,
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return list of objects when ignoring empty filters: %s", err.Op)
+	}
+	if len(testStructs) != 10 {
+		t.Fatalf("Get failed to return list of objects when ignoring empty filters, want %v, got %v", 10, len(testStructs))
+	}
+
+	testStructs, err = testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Filters: map[string]interface{}{
+			"LastName": "",
+			"Price":    0,
+		},
+		IgnoreEmptyFilters: true,
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return list of objects when ignoring empty string filters: %s", err.Op)
+	}
+	if len(testStructs) != 0 {
+		t.Fatalf("Get failed to keep zero value filters when ignoring only empty strings, want %v, got %v", 0, len(testStructs))
+	}
+
+	for _, ids := range []interface{}{[]int64(nil), []int64{}} {
+		testStructs, err = testController.Get(func() interface{} {
+			return &TestStruct{}
+		}, GetOptions{
+			Filters:           map[string]interface{}{"ID": ids},
+			IgnoreZeroFilters: true,
+		})
+		if err != nil || len(testStructs) != 0 {
+			t.Fatalf("Get failed to keep empty list filter when ignoring zero value filters, got %v %#v", len(testStructs), ids)
+		}
+	}
+
+	cnt, err := testController.GetCount(func() interface{} {
+		return &TestStruct{}
+	}, GetCountOptions{
+		Filters: map[string]interface{}{
+			"LastName": "",
+		},
+		IgnoreEmptyFilters: true,
+	})
+	if err != nil || cnt != 10 {
+		t.Fatalf("GetCount failed to ignore empty filters, want %v, got %v", 10, cnt)
+	}
+}
+
+// TestGetWithMaxRows tests if Get returns an error when query returns more rows than controller's MaxRows
+func TestGetWithMaxRows(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		testController.Save(ts, SaveOptions{})
+	}
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{MaxRows: 5})
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	_, err := c.Get(newObjFunc, GetOptions{})
+	if err == nil || err.Op != "TooManyRows" {
+		t.Fatalf("Get failed to return error when query returned more rows than MaxRows")
+	}
+
+	testStructs, err := c.Get(newObjFunc, GetOptions{Limit: 5})
+	if err != nil || len(testStructs) != 5 {
+		t.Fatalf("Get failed to return rows within MaxRows")
+	}
+}
+
+// TestGetWithQueryRewriter tests if controller passes queries to QueryRewriter before executing them
+func TestGetWithQueryRewriter(t *testing.T) {
+	recreateTestStructTable()
+
+	var ops []string
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{
+		QueryRewriter: func(op string, query string) string {
+			ops = append(ops, op)
+			return "/* app:test */ " + query
+		},
+	})
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	err := c.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed with query rewriter: %s", err.Op)
+	}
+
+	testStructs, err := c.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Filters: map[string]interface{}{
+			"FirstName": ts.FirstName,
+		},
+	})
+	if err != nil || len(testStructs) != 1 {
+		t.Fatalf("Get failed with query rewriter")
+	}
+
+	if strings.Join(ops, ",") != "Save,Get" {
+		t.Fatalf("QueryRewriter was called with invalid operations: %v", ops)
+	}
+}
+
+// TestGetWithCallerTag tests if controller adds a comment with caller's file and line to queries
+func TestGetWithCallerTag(t *testing.T) {
+	recreateTestStructTable()
+
+	var queries []string
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{
+		TagQueriesWithCaller: true,
+		QueryRewriter: func(op string, query string) string {
+			queries = append(queries, query)
+			return query
+		},
+	})
+
+	_, err := c.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{})
+	if err != nil {
+		t.Fatalf("Get failed with caller tag: %s", err.Op)
+	}
+
+	if len(queries) != 1 || !strings.HasPrefix(queries[0], "/* struct-db-postgres/db_get_test.go:") {
+		t.Fatalf("Get failed to add caller comment to query: %v", queries)
+	}
+}
+
+// TestGetWithFields tests if Get selects only the fields from options and ID, and leaves other fields zeroed
+func TestGetWithFields(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	testController.Save(ts, SaveOptions{})
+
+	testStructs, err := testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Fields: []string{"Price", "FirstName"},
+	})
+	if err != nil || len(testStructs) != 1 {
+		t.Fatalf("Get failed to return objects with selected fields")
+	}
+	ts2 := testStructs[0].(*TestStruct)
+	if ts2.ID != ts.ID || ts2.FirstName != ts.FirstName || ts2.Price != ts.Price {
+		t.Fatalf("Get failed to set selected fields")
+	}
+	if ts2.Age != 0 || ts2.LastName != "" {
+		t.Fatalf("Get failed to leave other fields zeroed")
+	}
+
+	_, err = testController.Get(func() interface{} {
+		return &TestStruct{}
+	}, GetOptions{
+		Fields: []string{"FirstName", "Missing"},
+	})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Get failed to return error for a field that does not exist")
+	}
+}
+
+// TestGetWithOrderBy tests if Get orders objects by fields with explicit direction, and returns error for a field
+// that does not exist
+func TestGetWithOrderBy(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 7; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i%2
+		ts.FirstName = fmt.Sprintf("Name%d", i)
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	testStructs, err := testController.Get(newObjFunc, GetOptions{
+		OrderBy: []OrderBy{{Field: "Age", Desc: true}, {Field: "FirstName"}},
+	})
+	if err != nil || len(testStructs) != 6 {
+		t.Fatalf("Get failed to return objects ordered with OrderBy")
+	}
+	names := ""
+	for _, ts := range testStructs {
+		names += ts.(*TestStruct).FirstName + ","
+	}
+	if names != "Name1,Name3,Name5,Name2,Name4,Name6," {
+		t.Fatalf("Get returned objects in invalid order: %s", names)
+	}
+
+	testStructs, err = testController.Get(newObjFunc, GetOptions{Order: []string{"FirstName"}, Limit: 2})
+	if err != nil || len(testStructs) != 2 || testStructs[0].(*TestStruct).FirstName != "Name1" || testStructs[1].(*TestStruct).FirstName != "Name2" {
+		t.Fatalf("Get failed to order ascending by field without direction")
+	}
+
+	_, err = testController.Get(newObjFunc, GetOptions{OrderBy: []OrderBy{{Field: "Missing"}}})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Get failed to return error for OrderBy field that does not exist")
+	}
+}
+
+// TestGetWithOrderTiebreaker tests if paginated Get returns rows with the same ordered values in a stable order
+func TestGetWithOrderTiebreaker(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30
+		testController.Save(ts, SaveOptions{})
+	}
+
+	// all rows have the same age so order of pages depends on the tiebreaker only
+	var ids []int64
+	for offset := 0; offset < 20; offset += 5 {
+		testStructs, err := testController.Get(func() interface{} {
+			return &TestStruct{}
+		}, GetOptions{
+			Order:  []string{"Age", "asc"},
+			Limit:  5,
+			Offset: offset,
+		})
+		if err != nil || len(testStructs) != 5 {
+			t.Fatalf("Get failed to return page of objects")
+		}
+		for _, obj := range testStructs {
+			ids = append(ids, obj.(*TestStruct).ID)
+		}
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("Get failed to order rows by ID as a tiebreaker: %v", ids)
+		}
+	}
+}
+
+// TestGetWithCursor tests if Get returns pages after the cursor when many objects have the same value of the first
+// ordered field
+func TestGetWithCursor(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 1 + i%3
+		ts.Key = fmt.Sprintf("%s%d", ts.Key, i)
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	for _, order := range [][]string{{"Age", "asc"}, {"Age", "desc", "ID", "desc"}} {
+		options := GetOptions{Order: order, Limit: 3}
+		var got []*TestStruct
+		for page := 0; page < 5; page++ {
+			xobj, err := testController.Get(newObjFunc, options)
+			if err != nil {
+				t.Fatalf("Get failed to return page with cursor: %s", err.Op)
+			}
+			if len(xobj) == 0 {
+				break
+			}
+			for _, obj := range xobj {
+				got = append(got, obj.(*TestStruct))
+			}
+			options.Cursor, err = testController.EncodeCursor(xobj[len(xobj)-1], options)
+			if err != nil {
+				t.Fatalf("EncodeCursor failed to return cursor: %s", err.Op)
+			}
+		}
+
+		if len(got) != 10 {
+			t.Fatalf("Get with cursor returned invalid number of objects for order %v, want %d, got %d", order, 10, len(got))
+		}
+		for i := 1; i < len(got); i++ {
+			prev, cur := got[i-1], got[i]
+			if order[1] == "asc" && (cur.Age < prev.Age || (cur.Age == prev.Age && cur.ID <= prev.ID)) {
+				t.Fatalf("Get with cursor returned objects in invalid order for %v", order)
+			}
+			if order[1] == "desc" && (cur.Age > prev.Age || (cur.Age == prev.Age && cur.ID >= prev.ID)) {
+				t.Fatalf("Get with cursor returned objects in invalid order for %v", order)
+			}
+		}
+	}
+
+	// Cursor is encoded with OrderBy turned into Order, the same as in Get
+	options := GetOptions{OrderBy: []OrderBy{{Field: "Age"}, {Field: "Price"}}, Limit: 4}
+	cnt := 0
+	for page := 0; page < 5; page++ {
+		xobj, err := testController.Get(newObjFunc, options)
+		if err != nil {
+			t.Fatalf("Get failed to return page with cursor and OrderBy: %s", err.Op)
+		}
+		if len(xobj) == 0 {
+			break
+		}
+		cnt += len(xobj)
+		options.Cursor, err = testController.EncodeCursor(xobj[len(xobj)-1], options)
+		if err != nil {
+			t.Fatalf("EncodeCursor failed to return cursor with OrderBy: %s", err.Op)
+		}
+	}
+	if cnt != 10 {
+		t.Fatalf("Get with cursor and OrderBy returned invalid number of objects, want %d, got %d", 10, cnt)
+	}
+
+	_, err := testController.EncodeCursor(&TestStruct{}, GetOptions{Order: []string{"Age", "desc"}})
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("EncodeCursor failed to return error for fields ordered in different directions")
+	}
+
+	_, err = testController.Get(newObjFunc, GetOptions{Order: []string{"Age", "asc"}, Cursor: "invalid"})
+	if err == nil || err.Op != "InvalidCursor" {
+		t.Fatalf("Get failed to return error for invalid cursor")
+	}
+}
+
+// TestGetContext tests if GetContext returns context error when context is cancelled before or during the query
+func TestGetContext(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	xobj, err := testController.GetContext(context.Background(), newObjFunc, GetOptions{})
+	if err != nil || len(xobj) != 20 {
+		t.Fatalf("GetContext failed to return objects")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = testController.GetContext(ctx, newObjFunc, GetOptions{})
+	if err == nil || err.Op != "DBQuery" || !errors.Is(err.Err, context.Canceled) {
+		t.Fatalf("GetContext failed to return context error when context is cancelled")
+	}
+
+	// cancel context after first row is fetched
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	_, err = testController.GetContext(ctx, newObjFunc, GetOptions{
+		RowObjTransformFunc: func(obj interface{}) interface{} {
+			cancel()
+			time.Sleep(50 * time.Millisecond)
+			return obj
+		},
+	})
+	if err == nil || err.Op != "DBQuery" || !errors.Is(err.Err, context.Canceled) {
+		t.Fatalf("GetContext failed to return context error when context is cancelled while reading rows")
+	}
+}
+
+// TestMethodsWithContext tests if Context variants of other methods return context error when context is cancelled
+func TestMethodsWithContext(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	testController.Save(ts, SaveOptions{})
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs := map[string]*ErrController{}
+	errs["IncrementFieldContext"] = testController.IncrementFieldContext(ctx, ts, "Age", 1)
+	_, errs["UpdateMultipleReturningContext"] = testController.UpdateMultipleReturningContext(ctx, newObjFunc, map[string]interface{}{"Price": 5}, UpdateMultipleOptions{
+		Filters: map[string]interface{}{"ID": ts.ID},
+	})
+	_, errs["GetColumnValuesContext"] = testController.GetColumnValuesContext(ctx, newObjFunc, "Age", GetOptions{})
+	_, errs["ExistingValuesContext"] = testController.ExistingValuesContext(ctx, newObjFunc, "Age", []interface{}{ts.Age})
+	_, errs["GetCountGroupedContext"] = testController.GetCountGroupedContext(ctx, newObjFunc, "Age", GetCountOptions{})
+
+	ts2 := getTestStructWithData()
+	ts2.ID = 0
+	errs["SaveMultipleContext"] = testController.SaveMultipleContext(ctx, []interface{}{ts2}, SaveOptions{})
+
+	for method, err := range errs {
+		if err == nil || err.Op != "DBQuery" || !errors.Is(err.Err, context.Canceled) {
+			t.Fatalf("%s failed to return context error when context is cancelled", method)
+		}
+	}
+}
+
+// TestGetFirst tests if GetFirst returns the first object with filters, order and offset, and ErrNotExist when
+// nothing matches
+func TestGetFirst(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 6; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	obj, err := testController.GetFirst(newObjFunc, GetOptions{
+		Order:   []string{"Age", "desc"},
+		Offset:  1,
+		Filters: map[string]interface{}{"Age": Lte(34)},
+	})
+	if err != nil {
+		t.Fatalf("GetFirst failed to return object: %s", err.Op)
+	}
+	if obj.(*TestStruct).Age != 33 {
+		t.Fatalf("GetFirst returned wrong object, want Age %d, got %d", 33, obj.(*TestStruct).Age)
+	}
+
+	obj, err = testController.GetFirst(newObjFunc, GetOptions{
+		Filters: map[string]interface{}{"Age": 99},
+	})
+	if obj != nil || err == nil || err.Op != "NotExist" || !errors.Is(err, ErrNotExist) {
+		t.Fatalf("GetFirst failed to return ErrNotExist when nothing matches")
+	}
+}
+
+// TestGetByExample tests if GetByExample filters objects by non-zero fields of the example
+func TestGetByExample(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30 + i%2
+		if i <= 3 {
+			ts.FirstName = "Example"
+		}
+		testController.Save(ts, SaveOptions{})
+	}
+
+	xobj, err := testController.GetByExample(&TestStruct{FirstName: "Example"}, GetOptions{})
+	if err != nil {
+		t.Fatalf("GetByExample failed to return objects: %s", err.Op)
+	}
+	if len(xobj) != 3 {
+		t.Fatalf("GetByExample returned wrong number of objects, want %d, got %d", 3, len(xobj))
+	}
+
+	xobj, err = testController.GetByExample(&TestStruct{FirstName: "Example", Age: 31}, GetOptions{})
+	if err != nil || len(xobj) != 2 {
+		t.Fatalf("GetByExample failed to filter by all non-zero fields")
+	}
+	if xobj[0].(*TestStruct).FirstName != "Example" || xobj[0].(*TestStruct).Age != 31 {
+		t.Fatalf("GetByExample returned object that does not match the example")
+	}
+
+	xobj, err = testController.GetByExample(&TestStruct{FirstName: "Example"}, GetOptions{
+		Filters: map[string]interface{}{"Age": 30},
+	})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("GetByExample failed to add filters from options")
+	}
+
+	_, err = testController.GetByExample(&TestStruct{FirstName: "X"}, GetOptions{})
+	if err == nil || err.Op != "ValidateFilters" {
+		t.Fatalf("GetByExample failed to validate values of the example")
+	}
+}
+
+// TestGetWithFilterOperators tests if filters with comparison operators work in Get, UpdateMultiple and
+// DeleteMultiple
+func TestGetWithFilterOperators(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	xobj, err := testController.Get(newObjFunc, GetOptions{
+		Filters: map[string]interface{}{"Age": Gt(35)},
+	})
+	if err != nil || len(xobj) != 15 {
+		t.Fatalf("Get failed to return objects filtered with Gt")
+	}
+
+	cnt, err := testController.GetCount(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Age": Lte(40), "ID": Ne(1)},
+	})
+	if err != nil || cnt != 9 {
+		t.Fatalf("GetCount failed to count objects filtered with Lte and Ne")
+	}
+
+	err = testController.UpdateMultiple(&TestStruct{}, map[string]interface{}{"Price": 1}, UpdateMultipleOptions{
+		Filters: map[string]interface{}{"Age": Gte(45)},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMultiple failed to update objects filtered with Gte: %s", err.Op)
+	}
+	cnt, _ = testController.GetCount(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Price": 1},
+	})
+	if cnt != 6 {
+		t.Fatalf("UpdateMultiple failed to update objects filtered with Gte")
+	}
+
+	err = testController.DeleteMultiple(&TestStruct{}, DeleteMultipleOptions{
+		Filters: map[string]interface{}{"Age": Lt(36)},
+	})
+	if err != nil {
+		t.Fatalf("DeleteMultiple failed to delete objects filtered with Lt: %s", err.Op)
+	}
+	cnt, _ = testController.GetCount(newObjFunc, GetCountOptions{})
+	if cnt != 15 {
+		t.Fatalf("DeleteMultiple failed to delete objects filtered with Lt")
+	}
+}
+
+// TestGetWithFailedQuery tests if error of a failed query contains the query, and its arguments only when allowed
+func TestGetWithFailedQuery(t *testing.T) {
+	recreateTestStructTable()
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	filters := map[string]interface{}{
+		"_raw": []interface{}{".Age = ?", "not a number"},
+	}
+
+	_, err := testController.Get(newObjFunc, GetOptions{Filters: filters})
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("Get failed to return error for invalid query")
+	}
+	if !strings.Contains(err.Query, "WHERE (age = $1)") || err.Args != nil {
+		t.Fatalf("Get returned error with invalid query or arguments: %s %v", err.Query, err.Args)
+	}
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{IncludeQueryArgs: true})
+	_, err = c.Get(newObjFunc, GetOptions{Filters: filters})
+	if err == nil || len(err.Args) != 1 || err.Args[0] != "not a number" {
+		t.Fatalf("Get failed to return error with query arguments")
+	}
+}
+
+// TestGetWithListFilters tests if filters with a list of values work in Get, GetCount, UpdateMultiple and
+// DeleteMultiple
+func TestGetWithListFilters(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	xobj, err := testController.Get(newObjFunc, GetOptions{
+		Order:   []string{"ID", "asc"},
+		Filters: map[string]interface{}{"ID": []int64{2, 4, 6}},
+	})
+	if err != nil || len(xobj) != 3 || xobj[0].(*TestStruct).ID != 2 || xobj[2].(*TestStruct).ID != 6 {
+		t.Fatalf("Get failed to return objects filtered with list of values")
+	}
+
+	cnt, err := testController.GetCount(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"ID": []int64{}},
+	})
+	if err != nil || cnt != 0 {
+		t.Fatalf("GetCount failed to match no objects with empty list of values")
+	}
+
+	err = testController.UpdateMultiple(&TestStruct{}, map[string]interface{}{"Price": 1}, UpdateMultipleOptions{
+		Filters: map[string]interface{}{"ID": []int64{1, 2, 3}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMultiple failed to update objects filtered with list of values: %s", err.Op)
+	}
+	cnt, _ = testController.GetCount(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Price": 1},
+	})
+	if cnt != 3 {
+		t.Fatalf("UpdateMultiple failed to update objects filtered with list of values")
+	}
+
+	err = testController.DeleteMultiple(&TestStruct{}, DeleteMultipleOptions{
+		Filters: map[string]interface{}{"ID": []int64{1, 2, 3, 4}},
+	})
+	if err != nil {
+		t.Fatalf("DeleteMultiple failed to delete objects filtered with list of values: %s", err.Op)
+	}
+	cnt, _ = testController.GetCount(newObjFunc, GetCountOptions{})
+	if cnt != 6 {
+		t.Fatalf("DeleteMultiple failed to delete objects filtered with list of values")
+	}
+}
+
+// TestGetTyped tests if GetTyped returns objects as a typed slice
+func TestGetTyped(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 6; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = i
+		ts.Key = fmt.Sprintf("%s%d", ts.Key, i)
+		testController.Save(ts, SaveOptions{})
+	}
+
+	xts, err := GetTyped[TestStruct](testController, GetOptions{
+		Order:   []string{"Age", "desc"},
+		Filters: map[string]interface{}{"Age": Gt(2)},
+	})
+	if err != nil {
+		t.Fatalf("GetTyped failed to return objects: %s", err.Op)
+	}
+	if len(xts) != 3 || xts[0].Age != 5 || xts[2].Age != 3 {
+		t.Fatalf("GetTyped failed to return filtered and ordered objects")
+	}
+
+	_, err = GetTyped[TestStruct](testController, GetOptions{
+		RowObjTransformFunc: func(obj interface{}) interface{} { return obj.(*TestStruct).Age },
+	})
+	if err == nil || err.Op != "InvalidType" {
+		t.Fatalf("GetTyped failed to return error when objects are transformed to another type")
+	}
+}
+
+// TestGetWithFilterGroups tests if Get returns objects matching filters and any of the filters in a group
+func TestGetWithFilterGroups(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = i
+		ts.Key = fmt.Sprintf("%s%d", ts.Key, i)
+		if i%2 == 0 {
+			ts.Price = 100
+		}
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	xobj, err := testController.Get(newObjFunc, GetOptions{
+		Order:   []string{"Age", "asc"},
+		Filters: map[string]interface{}{"Price": 444},
+		FilterGroups: []FilterGroup{
+			{Conjunction: RawConjuctionOR, Filters: map[string]interface{}{"Age": Gt(7), "FirstName": "Nobody"}},
+			{Conjunction: RawConjuctionOR, Filters: map[string]interface{}{"Age": []int{1, 9}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return objects filtered with filter groups: %s", err.Op)
+	}
+	if len(xobj) != 1 || xobj[0].(*TestStruct).Age != 9 {
+		t.Fatalf("Get failed to return objects filtered with filter groups")
+	}
+
+	_, err = testController.Get(newObjFunc, GetOptions{
+		FilterGroups: []FilterGroup{
+			{Conjunction: RawConjuctionOR, Filters: map[string]interface{}{"Age": 200}},
+		},
+	})
+	if err == nil || err.Op != "ValidateFilters" {
+		t.Fatalf("Get failed to validate filters in filter groups")
+	}
+}
+
+// TestGetWithLikeFilters tests if Like and ILike filters match objects with a pattern
+func TestGetWithLikeFilters(t *testing.T) {
+	recreateTestStructTable()
+
+	for _, n := range []string{"John", "Johnny", "Jane", "johanna"} {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.FirstName = n
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	cnt, err := testController.GetCount(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"FirstName": Like("Joh%")},
+	})
+	if err != nil || cnt != 2 {
+		t.Fatalf("GetCount failed to count objects filtered with Like")
+	}
+
+	cnt, err = testController.GetCount(newObjFunc, GetCountOptions{
+		Filters: map[string]interface{}{"FirstName": ILike("joh%")},
+	})
+	if err != nil || cnt != 3 {
+		t.Fatalf("GetCount failed to count objects filtered with ILike")
+	}
+}
@@ -0,0 +1,64 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Test struct for IncrementField
+type IncrementTestStruct struct {
+	ID    int64
+	Views int64
+	Name  string
+}
+
+// TestIncrementField tests if IncrementField adds delta to numeric field in the database and in the object
+func TestIncrementField(t *testing.T) {
+	testController.DropTable(&IncrementTestStruct{})
+	testController.CreateTable(&IncrementTestStruct{})
+
+	ts := &IncrementTestStruct{Views: 10, Name: "Page"}
+	testController.Save(ts, SaveOptions{})
+
+	err := testController.IncrementField(ts, "Views", 5)
+	if err != nil {
+		t.Fatalf("IncrementField failed to increment field: %s", err.Op)
+	}
+	if ts.Views != 15 {
+		t.Fatalf("IncrementField failed to set new value in the object")
+	}
+
+	err = testController.IncrementField(ts, "Views", -3)
+	if err != nil || ts.Views != 12 {
+		t.Fatalf("IncrementField failed to decrement field")
+	}
+
+	// Increment the field concurrently from many goroutines, each with a stale copy of an object
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tsCopy := &IncrementTestStruct{ID: ts.ID}
+			testController.IncrementField(tsCopy, "Views", 2)
+		}()
+	}
+	wg.Wait()
+
+	ts2 := &IncrementTestStruct{}
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if ts2.Views != 112 {
+		t.Fatalf("IncrementField failed to increment field atomically, got %d", ts2.Views)
+	}
+
+	err = testController.IncrementField(ts, "Name", 1)
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("IncrementField failed to return error for a field that is not numeric")
+	}
+
+	err = testController.IncrementField(&IncrementTestStruct{}, "Views", 1)
+	if err == nil || err.Op != "MissingID" {
+		t.Fatalf("IncrementField failed to return error for an object without ID")
+	}
+}
@@ -0,0 +1,66 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Test structs for JSON fields
+type JSONTestAddress struct {
+	City     string
+	PostCode string
+}
+
+type JSONTestStruct struct {
+	ID         int64
+	Name       string
+	Attributes map[string]interface{} `2db:"json"`
+	Address    JSONTestAddress        `2db:"json"`
+}
+
+// TestSaveAndLoadWithJSONFields tests if map and struct fields with 'json' tag are stored in JSONB columns
+func TestSaveAndLoadWithJSONFields(t *testing.T) {
+	testController.DropTable(&JSONTestStruct{})
+	err := testController.CreateTable(&JSONTestStruct{})
+	if err != nil {
+		t.Fatalf("CreateTable failed to create table with JSON fields: %s", err.Op)
+	}
+
+	js := &JSONTestStruct{
+		Name:       "First",
+		Attributes: map[string]interface{}{"color": "red", "size": float64(3)},
+		Address:    JSONTestAddress{City: "Warsaw", PostCode: "00-001"},
+	}
+	err = testController.Save(js, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with JSON fields: %s", err.Op)
+	}
+
+	js2 := &JSONTestStruct{Attributes: map[string]interface{}{"old": true}}
+	err = testController.Load(js2, fmt.Sprintf("%d", js.ID), LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load failed to get struct with JSON fields: %s", err.Op)
+	}
+	if len(js2.Attributes) != 2 || js2.Attributes["color"] != "red" || js2.Attributes["size"] != float64(3) {
+		t.Fatalf("Load failed to unmarshal map field, got %v", js2.Attributes)
+	}
+	if js2.Address.City != "Warsaw" || js2.Address.PostCode != "00-001" {
+		t.Fatalf("Load failed to unmarshal struct field, got %v", js2.Address)
+	}
+
+	js2.Attributes = nil
+	js2.Address.City = "Cracow"
+	err = testController.Save(js2, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to update struct with JSON fields: %s", err.Op)
+	}
+
+	xobj, err := testController.Get(func() interface{} { return &JSONTestStruct{} }, GetOptions{})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("Get failed to return structs with JSON fields")
+	}
+	js3 := xobj[0].(*JSONTestStruct)
+	if js3.Attributes != nil || js3.Address.City != "Cracow" {
+		t.Fatalf("Get failed to return updated JSON fields, got %v and %v", js3.Attributes, js3.Address)
+	}
+}
@@ -1,6 +1,8 @@
 package structdbpostgres
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -24,3 +26,82 @@ func TestLoad(t *testing.T) {
 		t.Fatalf("Load failed to set struct with data: %s", err.Op)
 	}
 }
+
+// Test struct for byte slice fields
+type BytesTestStruct struct {
+	ID      int64
+	Name    string
+	Content []byte
+}
+
+// TestLoadByteSlice tests if byte slice field is saved and loaded back including null bytes
+func TestLoadByteSlice(t *testing.T) {
+	testController.DropTable(&BytesTestStruct{})
+	testController.CreateTable(&BytesTestStruct{})
+
+	content := []byte{0x00, 0x01, 0xff, 0x00, 'a', 'b', 0x00}
+	ts := &BytesTestStruct{Name: "Binary", Content: content}
+	err := testController.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with byte slice: %s", err.Op)
+	}
+
+	ts2 := &BytesTestStruct{}
+	err = testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load failed to load struct with byte slice: %s", err.Op)
+	}
+	if !bytes.Equal(ts2.Content, content) {
+		t.Fatalf("Load failed to load byte slice, want %v, got %v", content, ts2.Content)
+	}
+
+	ts3 := &BytesTestStruct{Name: "Empty", Content: []byte{}}
+	err = testController.Save(ts3, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with empty byte slice: %s", err.Op)
+	}
+
+	xobj, err := testController.Get(func() interface{} { return &BytesTestStruct{} }, GetOptions{Order: []string{"ID", "asc"}})
+	if err != nil || len(xobj) != 2 {
+		t.Fatalf("Get failed to get structs with byte slices")
+	}
+	if !bytes.Equal(xobj[0].(*BytesTestStruct).Content, content) || len(xobj[1].(*BytesTestStruct).Content) != 0 {
+		t.Fatalf("Get failed to get byte slices")
+	}
+}
+
+// TestRefresh tests if Refresh sets object's fields to current values of its row
+func TestRefresh(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	testController.Save(ts, SaveOptions{})
+
+	ts2 := &TestStruct{}
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	ts2.Age = 60
+	testController.Save(ts2, SaveOptions{})
+
+	err := testController.Refresh(ts)
+	if err != nil {
+		t.Fatalf("Refresh failed to read object: %s", err.Op)
+	}
+	if ts.Age != 60 {
+		t.Fatalf("Refresh failed to set current field values, want %d, got %d", 60, ts.Age)
+	}
+
+	err = testController.Refresh(&TestStruct{})
+	if err == nil || err.Op != "Refresh" {
+		t.Fatalf("Refresh failed to return error for object without ID")
+	}
+
+	testController.Delete(ts2, DeleteOptions{})
+	err = testController.Refresh(ts)
+	if err == nil || err.Op != "NotExist" || !errors.Is(err, ErrNotExist) {
+		t.Fatalf("Refresh failed to return ErrNotExist for deleted row")
+	}
+	if ts.Age != 60 {
+		t.Fatalf("Refresh changed object when row does not exist")
+	}
+}
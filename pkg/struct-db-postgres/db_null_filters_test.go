@@ -0,0 +1,66 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// Test structs for IsNull and IsNotNull filters, where the view has NULL in Nickname when it is empty in the table
+type NullFilterTestItem struct {
+	ID       int64
+	Nickname string
+	Age      int
+}
+
+type NullFilterTestView struct {
+	ID       int64
+	Nickname string
+	Age      int
+}
+
+// TestNullFilters tests if IsNull and IsNotNull filters match NULL columns in Get, GetCount and DeleteMultiple
+func TestNullFilters(t *testing.T) {
+	testController.DropViews(&NullFilterTestView{})
+	testController.DropTable(&NullFilterTestItem{})
+	testController.CreateTable(&NullFilterTestItem{})
+
+	for i := 1; i < 11; i++ {
+		item := &NullFilterTestItem{Age: i}
+		if i%2 == 0 {
+			item.Nickname = "Nick"
+		}
+		testController.Save(item, SaveOptions{})
+	}
+
+	testController.RegisterView(&NullFilterTestView{}, "SELECT null_filter_test_item_id AS null_filter_test_view_id, NULLIF(nickname, '') AS nickname, age FROM struct2db_null_filter_test_items")
+	err := testController.CreateViews(&NullFilterTestView{})
+	if err != nil {
+		t.Fatalf("CreateViews failed to create view: %s", err.Op)
+	}
+
+	newViewFunc := func() interface{} { return &NullFilterTestView{} }
+
+	cnt, err := testController.GetCount(newViewFunc, GetCountOptions{
+		Filters: map[string]interface{}{"Nickname": IsNull(), "Age": Gt(4)},
+	})
+	if err != nil || cnt != 3 {
+		t.Fatalf("GetCount failed to count objects with IsNull filter, want %d, got %d", 3, cnt)
+	}
+
+	items, err := testController.Get(newViewFunc, GetOptions{
+		Filters: map[string]interface{}{"Nickname": IsNotNull(), "Age": Lt(7)},
+	})
+	if err != nil || len(items) != 3 {
+		t.Fatalf("Get failed to return objects with IsNotNull filter")
+	}
+
+	err = testController.DeleteMultiple(&NullFilterTestItem{}, DeleteMultipleOptions{
+		Filters: map[string]interface{}{"Nickname": IsNotNull(), "Age": Gt(8)},
+	})
+	if err != nil {
+		t.Fatalf("DeleteMultiple failed to delete objects with IsNotNull filter: %s", err.Op)
+	}
+	cnt, _ = testController.GetCount(func() interface{} { return &NullFilterTestItem{} }, GetCountOptions{})
+	if cnt != 9 {
+		t.Fatalf("DeleteMultiple with IsNotNull filter deleted invalid number of rows, %d rows left", cnt)
+	}
+}
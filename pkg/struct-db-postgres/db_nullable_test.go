@@ -0,0 +1,72 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Test struct with nullable fields
+type NullableTestStruct struct {
+	ID       int64
+	Name     string
+	Nickname *string
+	Age      *int64
+	BornAt   *time.Time
+}
+
+// TestSaveAndLoadWithNullableFields tests if pointer fields are stored as NULL when they are nil, and read back
+func TestSaveAndLoadWithNullableFields(t *testing.T) {
+	testController.DropTable(&NullableTestStruct{})
+	err := testController.CreateTable(&NullableTestStruct{})
+	if err != nil {
+		t.Fatalf("CreateTable failed to create table with nullable fields: %s", err.Op)
+	}
+
+	ns := &NullableTestStruct{Name: "First"}
+	err = testController.Save(ns, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with nil fields: %s", err.Op)
+	}
+
+	var cnt int64
+	dbConn.QueryRow(fmt.Sprintf("SELECT COUNT(*) FROM struct2db_nullable_test_structs WHERE nullable_test_struct_id = %d AND nickname IS NULL AND age IS NULL AND born_at IS NULL", ns.ID)).Scan(&cnt)
+	if cnt != 1 {
+		t.Fatalf("Save failed to store nil fields as NULL")
+	}
+
+	ns2 := &NullableTestStruct{}
+	err = testController.Load(ns2, fmt.Sprintf("%d", ns.ID), LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load failed to get struct with NULL columns: %s", err.Op)
+	}
+	if ns2.Name != "First" || ns2.Nickname != nil || ns2.Age != nil || ns2.BornAt != nil {
+		t.Fatalf("Load failed to set nil fields for NULL columns")
+	}
+
+	nickname := "Johnny"
+	age := int64(37)
+	bornAt := time.Date(1987, 6, 5, 0, 0, 0, 0, time.UTC)
+	ns2.Nickname, ns2.Age, ns2.BornAt = &nickname, &age, &bornAt
+	err = testController.Save(ns2, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to update struct with nullable fields: %s", err.Op)
+	}
+
+	xobj, err := testController.Get(func() interface{} { return &NullableTestStruct{} }, GetOptions{})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("Get failed to return structs with nullable fields")
+	}
+	ns3 := xobj[0].(*NullableTestStruct)
+	if ns3.Nickname == nil || *ns3.Nickname != "Johnny" || ns3.Age == nil || *ns3.Age != 37 || ns3.BornAt == nil || !ns3.BornAt.Equal(bornAt) {
+		t.Fatalf("Get failed to return values of nullable fields")
+	}
+
+	ns3.Nickname = nil
+	testController.Save(ns3, SaveOptions{})
+	ns4 := &NullableTestStruct{}
+	testController.Load(ns4, fmt.Sprintf("%d", ns.ID), LoadOptions{})
+	if ns4.Nickname != nil || ns4.Age == nil {
+		t.Fatalf("Save failed to set column back to NULL")
+	}
+}
@@ -0,0 +1,151 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Test structs for primary key fields other than ID
+type UuidTestStruct struct {
+	Uuid string `2db:"pk"`
+	Name string
+}
+
+type SessionTestStruct struct {
+	Uuid string `2db:"pk db_type:uuid"`
+	Name string
+}
+
+type PersonTestStruct struct {
+	PersonID int64 `2db:"pk"`
+	Name     string
+}
+
+// TestSaveLoadDeleteWithStringPrimaryKey tests if Save, Load and Delete use string field with 'pk' tag
+func TestSaveLoadDeleteWithStringPrimaryKey(t *testing.T) {
+	testController.DropTable(&UuidTestStruct{})
+	err := testController.CreateTable(&UuidTestStruct{})
+	if err != nil {
+		t.Fatalf("CreateTable failed to create table with string primary key: %s", err.Op)
+	}
+
+	err = testController.Save(&UuidTestStruct{Name: "Without key"}, SaveOptions{})
+	if err == nil || err.Op != "MissingID" {
+		t.Fatalf("Save failed to return error for object without string primary key")
+	}
+
+	us := &UuidTestStruct{Uuid: "9b2d7d3e-4a4f-4c62-9a34-0c2f6a1f1e01", Name: "First"}
+	res, err := testController.SaveWithResult(us, SaveOptions{})
+	if err != nil || !res.Inserted {
+		t.Fatalf("Save failed to insert object with string primary key")
+	}
+
+	us.Name = "Second"
+	res, err = testController.SaveWithResult(us, SaveOptions{})
+	if err != nil || res.Inserted {
+		t.Fatalf("Save failed to update object with string primary key")
+	}
+
+	us2 := &UuidTestStruct{}
+	err = testController.Load(us2, us.Uuid, LoadOptions{})
+	if err != nil || us2.Uuid != us.Uuid || us2.Name != "Second" {
+		t.Fatalf("Load failed to get object with string primary key")
+	}
+
+	err = testController.Delete(us2, DeleteOptions{})
+	if err != nil {
+		t.Fatalf("Delete failed to remove object with string primary key: %s", err.Op)
+	}
+	cnt, _ := testController.GetCount(func() interface{} { return &UuidTestStruct{} }, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("Delete failed to remove object with string primary key")
+	}
+}
+
+// TestSaveLoadDeleteWithIntPrimaryKey tests if Save, Load and Delete use integer field with 'pk' tag
+func TestSaveLoadDeleteWithIntPrimaryKey(t *testing.T) {
+	testController.DropTable(&PersonTestStruct{})
+	testController.CreateTable(&PersonTestStruct{})
+
+	ps := &PersonTestStruct{Name: "John"}
+	err := testController.Save(ps, SaveOptions{})
+	if err != nil || ps.PersonID == 0 {
+		t.Fatalf("Save failed to insert object and set integer primary key")
+	}
+	if testController.GetObjIDValue(ps) != ps.PersonID {
+		t.Fatalf("GetObjIDValue failed to return value of primary key field")
+	}
+
+	ps2 := &PersonTestStruct{}
+	testController.Load(ps2, fmt.Sprintf("%d", ps.PersonID), LoadOptions{})
+	if ps2.PersonID != ps.PersonID || ps2.Name != "John" {
+		t.Fatalf("Load failed to get object with integer primary key")
+	}
+
+	err = testController.Load(ps2, "abc", LoadOptions{})
+	if err == nil || err.Op != "IDToInt" {
+		t.Fatalf("Load failed to return error for non-numeric integer primary key")
+	}
+
+	testController.Delete(ps2, DeleteOptions{})
+	cnt, _ := testController.GetCount(func() interface{} { return &PersonTestStruct{} }, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("Delete failed to remove object with integer primary key")
+	}
+}
+
+// TestLoadWithUUIDPrimaryKey tests if Load gets object with primary key in UUID column, and returns error for a
+// malformed UUID before running the query
+func TestLoadWithUUIDPrimaryKey(t *testing.T) {
+	testController.DropTable(&SessionTestStruct{})
+	err := testController.CreateTable(&SessionTestStruct{})
+	if err != nil {
+		t.Fatalf("CreateTable failed to create table with UUID primary key: %s", err.Op)
+	}
+
+	ss := &SessionTestStruct{Uuid: "9b2d7d3e-4a4f-4c62-9a34-0c2f6a1f1e01", Name: "First"}
+	err = testController.Save(ss, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert object with UUID primary key: %s", err.Op)
+	}
+
+	ss2 := &SessionTestStruct{}
+	err = testController.Load(ss2, ss.Uuid, LoadOptions{})
+	if err != nil || ss2.Uuid != ss.Uuid || ss2.Name != "First" {
+		t.Fatalf("Load failed to get object with UUID primary key")
+	}
+
+	err = testController.Load(ss2, "9b2d7d3e-4a4f", LoadOptions{})
+	if err == nil || err.Op != "InvalidID" {
+		t.Fatalf("Load failed to return error for malformed UUID")
+	}
+	if ss2.Name != "First" {
+		t.Fatalf("Load changed object when UUID was malformed")
+	}
+}
+
+// TestGetWithIDFilterOnPrimaryKey tests if filter named ID is for the primary key field with a different name
+func TestGetWithIDFilterOnPrimaryKey(t *testing.T) {
+	testController.DropTable(&PersonTestStruct{})
+	testController.CreateTable(&PersonTestStruct{})
+
+	for _, n := range []string{"John", "Jane", "Joe"} {
+		testController.Save(&PersonTestStruct{Name: n}, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &PersonTestStruct{} }
+	xobj, err := testController.Get(newObjFunc, GetOptions{
+		Filters: map[string]interface{}{"ID": 2},
+	})
+	if err != nil || len(xobj) != 1 || xobj[0].(*PersonTestStruct).Name != "Jane" {
+		t.Fatalf("Get failed to return object with ID filter on primary key field")
+	}
+
+	xobj, err = testController.Get(newObjFunc, GetOptions{
+		Filters:      map[string]interface{}{"ID": []int64{1, 3}},
+		FilterGroups: []FilterGroup{{Filters: map[string]interface{}{"ID": 3}}},
+	})
+	if err != nil || len(xobj) != 1 || xobj[0].(*PersonTestStruct).Name != "Joe" {
+		t.Fatalf("Get failed to return object with ID filters in groups on primary key field")
+	}
+}
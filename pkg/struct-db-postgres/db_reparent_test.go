@@ -0,0 +1,63 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// Test structs for Reparent
+type ReparentGroup struct {
+	ID   int64
+	Name string
+}
+
+type ReparentMember struct {
+	ID              int64
+	ReparentGroupID int64
+	Name            string
+}
+
+type ReparentPerson struct {
+	PersonID        int64 `2db:"pk"`
+	ReparentGroupID int64
+}
+
+// TestReparent tests if Reparent moves children from one parent to another
+func TestReparent(t *testing.T) {
+	testController.DropTables(&ReparentGroup{}, &ReparentMember{})
+	testController.CreateTables(&ReparentGroup{}, &ReparentMember{})
+
+	g1 := &ReparentGroup{Name: "First"}
+	g2 := &ReparentGroup{Name: "Second"}
+	testController.Save(g1, SaveOptions{})
+	testController.Save(g2, SaveOptions{})
+
+	for i := 0; i < 3; i++ {
+		testController.Save(&ReparentMember{ReparentGroupID: g1.ID, Name: "Member"}, SaveOptions{})
+	}
+	testController.Save(&ReparentMember{ReparentGroupID: g2.ID, Name: "Member"}, SaveOptions{})
+
+	newObjFunc := func() interface{} { return &ReparentMember{} }
+
+	cnt, err := testController.Reparent(newObjFunc, "ReparentGroupID", g1.ID, g2.ID)
+	if err != nil {
+		t.Fatalf("Reparent failed to move children: %s", err.Op)
+	}
+	if cnt != 3 {
+		t.Fatalf("Reparent returned invalid number of updated rows: %d", cnt)
+	}
+
+	cnt2, _ := testController.GetCount(newObjFunc, GetCountOptions{Filters: map[string]interface{}{"ReparentGroupID": g2.ID}})
+	if cnt2 != 4 {
+		t.Fatalf("Reparent failed to move children in the database")
+	}
+
+	_, err = testController.Reparent(newObjFunc, "Name", g1.ID, g2.ID)
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Reparent failed to return error for an invalid foreign key field")
+	}
+
+	_, err = testController.Reparent(func() interface{} { return &ReparentPerson{} }, "PersonID", g1.ID, g2.ID)
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Reparent failed to return error for the primary key field")
+	}
+}
@@ -0,0 +1,116 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestSaveMultiple tests if SaveMultiple inserts objects in batches, reports progress and sets their IDs
+func TestSaveMultiple(t *testing.T) {
+	recreateTestStructTable()
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{BatchSize: 7})
+
+	objs := []interface{}{}
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = i
+		ts.Key = fmt.Sprintf("%s%d", ts.Key, i)
+		objs = append(objs, ts)
+	}
+
+	progress := []string{}
+	err := c.SaveMultiple(objs, SaveOptions{
+		OnProgress: func(done int, total int) {
+			progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+		},
+	})
+	if err != nil {
+		t.Fatalf("SaveMultiple failed to insert objects: %s", err.Op)
+	}
+	if fmt.Sprint(progress) != "[7/20 14/20 20/20]" {
+		t.Fatalf("SaveMultiple failed to report progress after each batch, got %v", progress)
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 20 {
+		t.Fatalf("SaveMultiple inserted invalid number of rows, want %d, got %d", 20, cnt)
+	}
+
+	for _, obj := range objs {
+		ts := obj.(*TestStruct)
+		if ts.ID == 0 {
+			t.Fatalf("SaveMultiple failed to set ID of inserted object")
+		}
+		ts2 := &TestStruct{}
+		testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+		if ts2.Age != ts.Age || ts2.Key != ts.Key {
+			t.Fatalf("SaveMultiple set ID of another row in object with Age %d", ts.Age)
+		}
+	}
+}
+
+// TestSaveMultipleWithInvalidObjects tests if SaveMultiple does not insert anything when objects are invalid or of
+// different types
+func TestSaveMultipleWithInvalidObjects(t *testing.T) {
+	recreateTestStructTable()
+
+	ts1 := getTestStructWithData()
+	ts1.ID = 0
+	ts2 := getTestStructWithData()
+	ts2.ID = 0
+	ts2.FirstName = "x"
+
+	err := testController.SaveMultiple([]interface{}{ts1, ts2}, SaveOptions{})
+	if err == nil || err.Op != "Validate" {
+		t.Fatalf("SaveMultiple failed to return validation error")
+	}
+
+	err = testController.SaveMultiple([]interface{}{ts1, &ProductKind{}}, SaveOptions{})
+	if err == nil || err.Op != "InvalidType" {
+		t.Fatalf("SaveMultiple failed to return error for objects of different types")
+	}
+
+	ts2.FirstName = "John"
+	ts2.ID = 5
+	err = testController.SaveMultiple([]interface{}{ts1, ts2}, SaveOptions{})
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("SaveMultiple failed to return error for object with ID without ForceInsertWithID")
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("SaveMultiple inserted rows when it failed, got %d", cnt)
+	}
+}
+
+// TestSaveMultipleWithFailedBatch tests if SaveMultiple does not insert anything when one of the batches fails
+func TestSaveMultipleWithFailedBatch(t *testing.T) {
+	recreateTestStructTable()
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{BatchSize: 7})
+
+	objs := []interface{}{}
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Key = fmt.Sprintf("%s%d", ts.Key, i)
+		objs = append(objs, ts)
+	}
+	// the last batch violates unique constraint on Key
+	objs[17].(*TestStruct).Key = objs[0].(*TestStruct).Key
+
+	err := c.SaveMultiple(objs, SaveOptions{})
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("SaveMultiple failed to return error when a batch fails")
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("SaveMultiple kept rows of batches before the failed one, got %d", cnt)
+	}
+	if objs[0].(*TestStruct).ID != 0 {
+		t.Fatalf("SaveMultiple kept ID of object that was rolled back")
+	}
+}
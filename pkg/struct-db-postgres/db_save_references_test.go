@@ -0,0 +1,64 @@
+package structdbpostgres
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test structs for CheckReferences
+type ReferenceGroup struct {
+	ID   int64
+	Name string
+}
+
+type ReferenceMember struct {
+	ID               int64
+	ReferenceGroupID int64
+	Name             string
+}
+
+// TestSaveWithCheckReferences tests if Save returns validation error when foreign key field refers to an object that
+// does not exist
+func TestSaveWithCheckReferences(t *testing.T) {
+	testController.DropTables(&ReferenceGroup{}, &ReferenceMember{})
+	testController.CreateTables(&ReferenceGroup{}, &ReferenceMember{})
+
+	g := &ReferenceGroup{Name: "Group"}
+	testController.Save(g, SaveOptions{})
+
+	options := SaveOptions{
+		CheckReferences: map[string]func() interface{}{
+			"ReferenceGroupID": func() interface{} { return &ReferenceGroup{} },
+		},
+	}
+
+	m := &ReferenceMember{ReferenceGroupID: g.ID, Name: "Valid"}
+	err := testController.Save(m, options)
+	if err != nil || m.ID == 0 {
+		t.Fatalf("Save failed to insert object with a valid reference")
+	}
+
+	m2 := &ReferenceMember{Name: "Without group"}
+	err = testController.Save(m2, options)
+	if err != nil || m2.ID == 0 {
+		t.Fatalf("Save failed to insert object with a zero reference")
+	}
+
+	m3 := &ReferenceMember{ReferenceGroupID: g.ID + 100, Name: "Dangling"}
+	err = testController.Save(m3, options)
+	if err == nil || err.Op != "ValidateReferences" {
+		t.Fatalf("Save failed to return error for a dangling reference")
+	}
+	var errValidation *ErrValidation
+	if !errors.As(err.Err, &errValidation) || errValidation.Fields["ReferenceGroupID"] != FailReference {
+		t.Fatalf("Save failed to return FailReference for a dangling reference")
+	}
+	if m3.ID != 0 {
+		t.Fatalf("Save inserted object with a dangling reference")
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &ReferenceMember{} }, GetCountOptions{})
+	if cnt != 2 {
+		t.Fatalf("Save inserted invalid number of objects, want %d, got %d", 2, cnt)
+	}
+}
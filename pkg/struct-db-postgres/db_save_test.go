@@ -1,6 +1,13 @@
 package structdbpostgres
 
-import "testing"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+)
 
 // TestSave tests if Save properly inserts and updates object in the database
 func TestSave(t *testing.T) {
@@ -122,3 +129,399 @@ func TestSaveInsertWithIDAndNoInsert(t *testing.T) {
 		t.Fatalf("Save failed to not insert struct with provided ID in the table when NoInsert")
 	}
 }
+
+// TestSaveWithResult tests if SaveWithResult properly tells whether the row was inserted or updated
+func TestSaveWithResult(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 99999
+	res, err := testController.SaveWithResult(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("SaveWithResult failed to insert struct with provided ID to the table: %s", err.Op)
+	}
+	if !res.Inserted {
+		t.Fatalf("SaveWithResult failed to return that the row was inserted")
+	}
+
+	ts.FirstName = "UpdatedProvidedID"
+	res, err = testController.SaveWithResult(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("SaveWithResult failed to update struct with provided ID in the table: %s", err.Op)
+	}
+	if res.Inserted {
+		t.Fatalf("SaveWithResult failed to return that the row was updated")
+	}
+}
+
+// TestSaveForceInsertWithID tests if Save inserts object with provided ID and bumps ID sequence when requested
+func TestSaveForceInsertWithID(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 500
+	err := testController.Save(ts, SaveOptions{
+		ForceInsertWithID: true,
+		SyncIDSequence:    true,
+	})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with forced ID to the table: %s", err.Op)
+	}
+	if ts.ID != 500 {
+		t.Fatalf("Save failed to insert struct with forced ID to the table, got ID %d", ts.ID)
+	}
+
+	// inserting the same ID again must fail, as there is no update
+	ts.Key = ts.Key + "2"
+	err = testController.Save(ts, SaveOptions{
+		ForceInsertWithID: true,
+	})
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("Save failed to return error when inserting struct with forced ID that already exists")
+	}
+
+	// next object without ID should get an ID from the bumped sequence
+	ts2 := getTestStructWithData()
+	ts2.ID = 0
+	ts2.Key = ts2.Key + "3"
+	err = testController.Save(ts2, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct after inserting one with forced ID: %s", err.Op)
+	}
+	if ts2.ID != 501 {
+		t.Fatalf("Save failed to bump ID sequence after inserting struct with forced ID, got ID %d", ts2.ID)
+	}
+
+	err = testController.Save(&TestStruct{}, SaveOptions{ForceInsertWithID: true})
+	if err == nil || err.Op != "MissingID" && err.Op != "Validate" {
+		t.Fatalf("Save failed to return error when forcing insert of struct without ID")
+	}
+}
+
+// TestSyncSequence tests if SyncSequence sets ID sequence after rows inserted with IDs so that next insert does not
+// collide with them
+func TestSyncSequence(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 4; i++ {
+		ts := getTestStructWithData()
+		ts.ID = int64(i)
+		ts.Key = fmt.Sprintf("%s%d", ts.Key, i)
+		testController.Save(ts, SaveOptions{ForceInsertWithID: true})
+	}
+
+	// sequence still starts at 1 so the insert collides with an existing row
+	ts := getTestStructWithData()
+	ts.ID = 0
+	err := testController.Save(ts, SaveOptions{})
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("Save failed to collide with row inserted with ID before sequence is synced")
+	}
+
+	err = testController.SyncSequence(&TestStruct{})
+	if err != nil {
+		t.Fatalf("SyncSequence failed: %s", err.Op)
+	}
+
+	ts.ID = 0
+	err = testController.Save(ts, SaveOptions{})
+	if err != nil || ts.ID != 4 {
+		t.Fatalf("Save failed to get ID from synced sequence, want %d, got %d", 4, ts.ID)
+	}
+
+	// on an empty table the sequence starts again at 1
+	recreateTestStructTable()
+	err = testController.SyncSequence(&TestStruct{})
+	if err != nil {
+		t.Fatalf("SyncSequence failed on an empty table: %s", err.Op)
+	}
+	ts.ID = 0
+	err = testController.Save(ts, SaveOptions{})
+	if err != nil || ts.ID != 1 {
+		t.Fatalf("Save failed to get ID from sequence synced on an empty table, want %d, got %d", 1, ts.ID)
+	}
+
+	err = testController.SyncSequence(&UuidTestStruct{})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("SyncSequence failed to return error for string primary key")
+	}
+}
+
+// TestSaveWithUpdateColumns tests if Save updates only specified columns when object with ID already exists
+func TestSaveWithUpdateColumns(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 700
+	err := testController.Save(ts, SaveOptions{UpdateColumns: []string{"FirstName"}})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with update columns: %s", err.Op)
+	}
+
+	ts.FirstName = "Updated"
+	ts.LastName = "NotUpdated"
+	ts.Age = 99
+	err = testController.Save(ts, SaveOptions{UpdateColumns: []string{"FirstName", "Age"}})
+	if err != nil {
+		t.Fatalf("Save failed to update struct with update columns: %s", err.Op)
+	}
+
+	ts2 := &TestStruct{}
+	testController.Load(ts2, "700", LoadOptions{})
+	if ts2.FirstName != "Updated" || ts2.Age != 99 {
+		t.Fatalf("Save failed to update specified columns")
+	}
+	if ts2.LastName != "Smith" {
+		t.Fatalf("Save failed to keep value of column that was not specified")
+	}
+
+	err = testController.Save(ts, SaveOptions{UpdateColumns: []string{"Missing"}})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Save failed to return error for update column that does not exist")
+	}
+}
+
+type testIDGenerator struct {
+	last int64
+}
+
+func (g *testIDGenerator) NewID() interface{} {
+	return atomic.AddInt64(&g.last, 1)
+}
+
+// TestSaveWithIDGenerator tests if Save uses controller's ID generator for new objects
+func TestSaveWithIDGenerator(t *testing.T) {
+	recreateTestStructTable()
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{IDGenerator: &testIDGenerator{last: 1000000}})
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	res, err := c.SaveWithResult(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with generated ID: %s", err.Op)
+	}
+	if ts.ID != 1000001 || !res.Inserted {
+		t.Fatalf("Save failed to use generated ID, got %d", ts.ID)
+	}
+
+	ts2 := &TestStruct{}
+	c.Load(ts2, "1000001", LoadOptions{})
+	if ts2.ID != 1000001 || ts2.FirstName != ts.FirstName {
+		t.Fatalf("Save failed to insert struct with generated ID in the database")
+	}
+
+	// object with ID is updated, and generator is not called
+	ts.FirstName = "Updated"
+	res, err = c.SaveWithResult(ts, SaveOptions{})
+	if err != nil || res.Inserted || ts.ID != 1000001 {
+		t.Fatalf("Save failed to update struct with generated ID")
+	}
+}
+
+// TestSaveWithConflictUpdateWhere tests if Save skips updating existing object when condition is not met
+func TestSaveWithConflictUpdateWhere(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 800
+	ts.Age = 50
+	res, err := testController.SaveWithResult(ts, SaveOptions{ConflictUpdateWhere: "EXCLUDED.Age > .Age"})
+	if err != nil || !res.Inserted {
+		t.Fatalf("Save failed to insert struct with conflict update condition")
+	}
+
+	// lower age does not meet the condition so the row must not be updated
+	ts.Age = 40
+	ts.FirstName = "Skipped"
+	res, err = testController.SaveWithResult(ts, SaveOptions{ConflictUpdateWhere: "EXCLUDED.Age > .Age"})
+	if err != nil {
+		t.Fatalf("Save failed when conflict update was skipped: %s", err.Op)
+	}
+	if !res.Skipped || res.Inserted {
+		t.Fatalf("Save failed to return that the update was skipped")
+	}
+
+	ts2 := &TestStruct{}
+	testController.Load(ts2, "800", LoadOptions{})
+	if ts2.Age != 50 || ts2.FirstName == "Skipped" {
+		t.Fatalf("Save failed to skip update when condition was not met")
+	}
+
+	ts.Age = 60
+	ts.FirstName = "Updated"
+	res, err = testController.SaveWithResult(ts, SaveOptions{ConflictUpdateWhere: "EXCLUDED.Age > .Age"})
+	if err != nil || res.Skipped || res.Inserted {
+		t.Fatalf("Save failed to update struct when condition was met")
+	}
+	testController.Load(ts2, "800", LoadOptions{})
+	if ts2.Age != 60 || ts2.FirstName != "Updated" {
+		t.Fatalf("Save failed to update struct when condition was met")
+	}
+}
+
+// TestSaveWithConflictConstraint tests if Save updates existing row when object conflicts with it on the constraint
+func TestSaveWithConflictConstraint(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	res, err := testController.SaveWithResult(ts, SaveOptions{ConflictConstraint: "struct2db_test_structs_key_key"})
+	if err != nil || !res.Inserted || ts.ID == 0 {
+		t.Fatalf("Save failed to insert struct with conflict constraint")
+	}
+	id := ts.ID
+
+	ts2 := getTestStructWithData()
+	ts2.ID = 0
+	ts2.Key = ts.Key
+	ts2.FirstName = "Updated"
+	res, err = testController.SaveWithResult(ts2, SaveOptions{
+		ConflictConstraint: "struct2db_test_structs_key_key",
+		UpdateColumns:      []string{"FirstName"},
+	})
+	if err != nil || res.Inserted || ts2.ID != id {
+		t.Fatalf("Save failed to update existing row on conflict with the constraint")
+	}
+
+	ts3 := &TestStruct{}
+	testController.Load(ts3, fmt.Sprintf("%d", id), LoadOptions{})
+	if ts3.FirstName != "Updated" {
+		t.Fatalf("Save failed to update specified column on conflict with the constraint")
+	}
+
+	err = testController.Save(ts2, SaveOptions{ConflictConstraint: "uq; DROP TABLE users"})
+	if err == nil || err.Op != "InvalidName" {
+		t.Fatalf("Save failed to return error for invalid constraint name")
+	}
+
+	err = testController.Save(ts2, SaveOptions{ConflictConstraint: "struct2db_test_structs_key_key", NoInsert: true})
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("Save failed to return error for ConflictConstraint used with NoInsert")
+	}
+}
+
+// TestSaveWithOnConflictDoNothing tests if Save skips inserting object that violates a unique constraint
+func TestSaveWithOnConflictDoNothing(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	res, err := testController.SaveWithResult(ts, SaveOptions{OnConflictDoNothing: true})
+	if err != nil || !res.Inserted || res.Skipped || ts.ID == 0 {
+		t.Fatalf("Save failed to insert struct with OnConflictDoNothing")
+	}
+
+	ts2 := getTestStructWithData()
+	ts2.ID = 0
+	ts2.Key = ts.Key
+	res, err = testController.SaveWithResult(ts2, SaveOptions{OnConflictDoNothing: true})
+	if err != nil {
+		t.Fatalf("Save failed to skip inserting struct that violates unique constraint: %s", err.Op)
+	}
+	if res.Inserted || !res.Skipped || ts2.ID != 0 {
+		t.Fatalf("Save failed to return skipped insert with zero ID")
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 1 {
+		t.Fatalf("Save inserted invalid number of rows, want %d, got %d", 1, cnt)
+	}
+}
+
+// TestSaveWithSkipValidation tests if Save stores invalid object when validation is skipped
+func TestSaveWithSkipValidation(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	ts.EmailSecondary = "invalid"
+	ts.Age = 500
+	err := testController.Save(ts, SaveOptions{})
+	if err == nil || err.Op != "Validate" {
+		t.Fatalf("Save failed to validate invalid struct")
+	}
+
+	err = testController.Save(ts, SaveOptions{SkipValidation: true})
+	if err != nil {
+		t.Fatalf("Save failed to insert invalid struct when validation is skipped: %s", err.Op)
+	}
+
+	ts2 := &TestStruct{}
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if ts2.EmailSecondary != "invalid" || ts2.Age != 500 {
+		t.Fatalf("Save failed to store invalid values when validation is skipped")
+	}
+
+	err = testController.UpdateMultiple(&TestStruct{}, map[string]interface{}{"Age": 600}, UpdateMultipleOptions{
+		Filters:        map[string]interface{}{"ID": ts.ID},
+		SkipValidation: true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateMultiple failed to update with invalid values when validation is skipped: %s", err.Op)
+	}
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if ts2.Age != 600 {
+		t.Fatalf("UpdateMultiple failed to store invalid values when validation is skipped")
+	}
+}
+
+// TestSaveDefaults tests if SaveDefaults inserts a row with default values and returns it
+func TestSaveDefaults(t *testing.T) {
+	recreateTestStructTable()
+
+	obj, err := testController.SaveDefaults(func() interface{} { return &TestStruct{} })
+	if err != nil {
+		t.Fatalf("SaveDefaults failed to insert row with default values: %s", err.Op)
+	}
+
+	ts := obj.(*TestStruct)
+	if ts.ID == 0 || ts.FirstName != "" || ts.Age != 0 {
+		t.Fatalf("SaveDefaults returned invalid object: %v", ts)
+	}
+
+	ts2 := &TestStruct{}
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if ts2.ID != ts.ID {
+		t.Fatalf("SaveDefaults failed to insert row in the database")
+	}
+
+	// there is a unique key column, so second row with default values must fail
+	_, err = testController.SaveDefaults(func() interface{} { return &TestStruct{} })
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("SaveDefaults failed to return error when default values violate unique constraint")
+	}
+}
+
+// TestSaveWithLogger tests if failed validation in Save is logged with model, method and failed fields
+func TestSaveWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{
+		Logger: slog.New(slog.NewJSONHandler(&buf, nil)),
+	})
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	ts.Age = 500
+	err := c.Save(ts, SaveOptions{})
+	if err == nil || err.Op != "Validate" {
+		t.Fatalf("Save failed to validate invalid struct")
+	}
+
+	var entry struct {
+		Msg    string         `json:"msg"`
+		Model  string         `json:"model"`
+		Method string         `json:"method"`
+		Op     string         `json:"op"`
+		Fields map[string]int `json:"fields"`
+	}
+	if e := json.Unmarshal(buf.Bytes(), &entry); e != nil {
+		t.Fatalf("Save failed to log validation failure: %s", e.Error())
+	}
+	if entry.Model != "TestStruct" || entry.Method != "Save" || entry.Op != "Validate" {
+		t.Fatalf("Save logged validation failure with invalid attributes: %s", buf.String())
+	}
+	if _, ok := entry.Fields["Age"]; !ok {
+		t.Fatalf("Save failed to log invalid field: %s", buf.String())
+	}
+}
@@ -0,0 +1,58 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// Test struct for automatic timestamps
+type TimestampTestStruct struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time `2db:"created_at"`
+	UpdatedAt time.Time `2db:"updated_at"`
+}
+
+// TestSaveWithTimestamps tests if Save sets 'created_at' field only on insert and 'updated_at' on every save
+func TestSaveWithTimestamps(t *testing.T) {
+	testController.DropTable(&TimestampTestStruct{})
+	testController.CreateTable(&TimestampTestStruct{})
+
+	ts := &TimestampTestStruct{Name: "First"}
+	err := testController.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with timestamps: %s", err.Op)
+	}
+	if ts.CreatedAt.IsZero() || !ts.UpdatedAt.Equal(ts.CreatedAt) {
+		t.Fatalf("Save failed to set timestamps on insert")
+	}
+	createdAt := ts.CreatedAt
+
+	time.Sleep(10 * time.Millisecond)
+
+	// upsert of an object without 'created_at' must keep the existing value
+	ts2 := &TimestampTestStruct{ID: ts.ID, Name: "Second"}
+	res, err := testController.SaveWithResult(ts2, SaveOptions{})
+	if err != nil || res.Inserted {
+		t.Fatalf("Save failed to update struct with timestamps")
+	}
+	if !ts2.CreatedAt.Equal(createdAt) {
+		t.Fatalf("Save failed to return existing created_at, want %v, got %v", createdAt, ts2.CreatedAt)
+	}
+	if !ts2.UpdatedAt.After(createdAt) {
+		t.Fatalf("Save failed to set updated_at on update")
+	}
+
+	ts3 := &TimestampTestStruct{ID: ts.ID, Name: "Third"}
+	err = testController.Save(ts3, SaveOptions{NoInsert: true})
+	if err != nil {
+		t.Fatalf("Save failed to update struct with timestamps with NoInsert: %s", err.Op)
+	}
+
+	ts4 := &TimestampTestStruct{}
+	testController.Load(ts4, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if ts4.Name != "Third" || !ts4.CreatedAt.Equal(createdAt) || !ts4.UpdatedAt.Equal(ts3.UpdatedAt) {
+		t.Fatalf("Save failed to keep created_at and set updated_at in the database")
+	}
+}
@@ -0,0 +1,42 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// TestWithTableSuffix tests if controller with table suffix runs queries on the suffixed table only
+func TestWithTableSuffix(t *testing.T) {
+	recreateTestStructTable()
+
+	c2 := testController.WithTableSuffix("_v2")
+	c2.DropTable(&TestStruct{})
+	err := c2.CreateTable(&TestStruct{})
+	if err != nil {
+		t.Fatalf("CreateTable failed to create suffixed table: %s", err.Op)
+	}
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	err = c2.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert object into suffixed table: %s", err.Op)
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	cnt, _ := c2.GetCount(newObjFunc, GetCountOptions{})
+	if cnt != 1 {
+		t.Fatalf("GetCount returned invalid number of rows in suffixed table, want %d, got %d", 1, cnt)
+	}
+	cnt, _ = testController.GetCount(newObjFunc, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("Save inserted object into table without suffix")
+	}
+
+	var tblCnt int64
+	dbConn.QueryRow("SELECT COUNT(*) FROM struct2db_test_structs_v2").Scan(&tblCnt)
+	if tblCnt != 1 {
+		t.Fatalf("Save failed to insert row into struct2db_test_structs_v2")
+	}
+
+	c2.DropTable(&TestStruct{})
+}
@@ -0,0 +1,64 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// Test struct for ToggleField
+type ToggleTestStruct struct {
+	ID     int64
+	Active bool
+	Age    int
+}
+
+// TestToggleField tests if ToggleField negates bool field in the database and in the object
+func TestToggleField(t *testing.T) {
+	testController.DropTable(&ToggleTestStruct{})
+	testController.CreateTable(&ToggleTestStruct{})
+
+	ts := &ToggleTestStruct{Age: 30}
+	testController.Save(ts, SaveOptions{})
+
+	err := testController.ToggleField(ts, "Active")
+	if err != nil {
+		t.Fatalf("ToggleField failed to toggle field: %s", err.Op)
+	}
+	if !ts.Active {
+		t.Fatalf("ToggleField failed to set new value in the object")
+	}
+
+	ts2 := &ToggleTestStruct{}
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if !ts2.Active {
+		t.Fatalf("ToggleField failed to toggle field in the database")
+	}
+
+	// Toggle the field concurrently, even number of times, so that value gets back to the one from the start
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tsCopy := &ToggleTestStruct{ID: ts.ID}
+			testController.ToggleField(tsCopy, "Active")
+		}()
+	}
+	wg.Wait()
+
+	testController.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if !ts2.Active {
+		t.Fatalf("ToggleField failed to toggle field atomically")
+	}
+
+	err = testController.ToggleField(ts, "Age")
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("ToggleField failed to return error for a field that is not a bool")
+	}
+
+	err = testController.ToggleField(&ToggleTestStruct{}, "Active")
+	if err == nil || err.Op != "MissingID" {
+		t.Fatalf("ToggleField failed to return error for an object without ID")
+	}
+}
@@ -0,0 +1,66 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestUpdateByIDValues tests if UpdateByIDValues sets different values in many objects at once
+func TestUpdateByIDValues(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 6; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30
+		testController.Save(ts, SaveOptions{})
+	}
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+
+	// small batch size makes objects to be updated in several queries
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{BatchSize: 2})
+	cnt, err := c.UpdateByIDValues(newObjFunc, map[int64]map[string]interface{}{
+		1:  {"Age": 41, "Price": 100},
+		2:  {"Age": 42, "Price": 200},
+		4:  {"Age": 44, "Price": 400},
+		5:  {"Age": 45, "Price": 500},
+		99: {"Age": 99, "Price": 999},
+	})
+	if err != nil {
+		t.Fatalf("UpdateByIDValues failed to update objects: %s", err.Err.Error())
+	}
+	if cnt != 4 {
+		t.Fatalf("UpdateByIDValues returned invalid number of updated rows, want %v, got %v", 4, cnt)
+	}
+
+	for id, want := range map[int64][2]int{1: {41, 100}, 2: {42, 200}, 3: {30, 444}, 4: {44, 400}, 5: {45, 500}} {
+		ts := &TestStruct{}
+		testController.Load(ts, fmt.Sprintf("%d", id), LoadOptions{})
+		if ts.Age != want[0] || ts.Price != want[1] {
+			t.Fatalf("UpdateByIDValues failed to update object %d, want %v, got %v %v", id, want, ts.Age, ts.Price)
+		}
+	}
+
+	_, err = testController.UpdateByIDValues(newObjFunc, map[int64]map[string]interface{}{
+		1: {"Age": 41, "Price": 100},
+		2: {"Age": 42},
+	})
+	if err == nil || err.Op != "InvalidValue" {
+		t.Fatalf("UpdateByIDValues failed to return error for inconsistent fields")
+	}
+
+	_, err = testController.UpdateByIDValues(newObjFunc, map[int64]map[string]interface{}{
+		1: {"Age": "old"},
+	})
+	if err == nil || err.Op != "InvalidValue" {
+		t.Fatalf("UpdateByIDValues failed to return error for value of invalid type")
+	}
+
+	_, err = testController.UpdateByIDValues(newObjFunc, map[int64]map[string]interface{}{
+		1: {"Age": 500},
+	})
+	if err == nil || err.Op != "ValidateValues" {
+		t.Fatalf("UpdateByIDValues failed to validate values")
+	}
+}
@@ -49,3 +49,135 @@ func TestUpdateMultiple(t *testing.T) {
 		t.Fatalf("UpdateMultiple updated invalid number of rows, there are %d rows left, instead of %d", cnt, 150)
 	}
 }
+
+// TestUpdateMultipleWithoutFilters tests if UpdateMultiple refuses to update all the rows when no filters are
+// passed, unless AllowFullTableUpdate is set
+func TestUpdateMultipleWithoutFilters(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 11; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 30
+		testController.Save(ts, SaveOptions{})
+	}
+
+	err := testController.UpdateMultiple(&TestStruct{}, map[string]interface{}{
+		"Age": 98,
+	}, UpdateMultipleOptions{})
+	if err == nil || err.Op != "UnsafeFullTable" {
+		t.Fatalf("UpdateMultiple failed to refuse updating all rows without filters")
+	}
+
+	cnt, _ := testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{
+		Filters: map[string]interface{}{
+			"Age": 98,
+		},
+	})
+	if cnt != 0 {
+		t.Fatalf("UpdateMultiple updated rows without filters, there are %d rows updated", cnt)
+	}
+
+	err = testController.UpdateMultiple(&TestStruct{}, map[string]interface{}{
+		"Age": 98,
+	}, UpdateMultipleOptions{
+		AllowFullTableUpdate: true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateMultiple failed to update all rows when AllowFullTableUpdate is set: %s", err.Op)
+	}
+
+	cnt, _ = testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{
+		Filters: map[string]interface{}{
+			"Age": 98,
+		},
+	})
+	if cnt != 10 {
+		t.Fatalf("UpdateMultiple updated invalid number of rows, there are %d rows updated, instead of %d", cnt, 10)
+	}
+}
+
+// TestUpdateMultipleReturning tests if UpdateMultipleReturning returns objects with updated values
+func TestUpdateMultipleReturning(t *testing.T) {
+	recreateTestStructTable()
+
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i
+		testController.Save(ts, SaveOptions{})
+	}
+
+	xobj, err := testController.UpdateMultipleReturning(func() interface{} { return &TestStruct{} }, map[string]interface{}{
+		"FirstName": "Updated",
+	},
+		UpdateMultipleOptions{
+			Filters: map[string]interface{}{
+				"_raw": []interface{}{
+					".Age > ?",
+					25,
+				},
+			},
+		})
+	if err != nil {
+		t.Fatalf("UpdateMultipleReturning failed to update objects: %s", err.Op)
+	}
+	if len(xobj) != 5 {
+		t.Fatalf("UpdateMultipleReturning returned invalid number of objects, want %v, got %v", 5, len(xobj))
+	}
+	for _, obj := range xobj {
+		ts := obj.(*TestStruct)
+		if ts.FirstName != "Updated" || ts.Age <= 25 || ts.ID == 0 || ts.LastName != "Smith" {
+			t.Fatalf("UpdateMultipleReturning returned object without updated values: %v", ts)
+		}
+	}
+
+	_, err = testController.UpdateMultipleReturning(func() interface{} { return &TestStruct{} }, map[string]interface{}{
+		"FirstName": "Updated",
+	}, UpdateMultipleOptions{})
+	if err == nil || err.Op != "UnsafeFullTable" {
+		t.Fatalf("UpdateMultipleReturning failed to refuse updating all rows without filters")
+	}
+}
+
+// TestUpdateMultipleReturningIDs tests if UpdateMultipleReturningIDs returns IDs of updated rows only
+func TestUpdateMultipleReturningIDs(t *testing.T) {
+	recreateTestStructTable()
+
+	want := map[int64]bool{}
+	for i := 1; i < 21; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Age = 10 + i
+		testController.Save(ts, SaveOptions{})
+		if ts.Age > 25 {
+			want[ts.ID] = true
+		}
+	}
+
+	ids, err := testController.UpdateMultipleReturningIDs(&TestStruct{}, map[string]interface{}{
+		"FirstName": "Updated",
+	}, UpdateMultipleOptions{
+		Filters: map[string]interface{}{"Age": Gt(25)},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMultipleReturningIDs failed to update objects: %s", err.Op)
+	}
+	if len(ids) != len(want) {
+		t.Fatalf("UpdateMultipleReturningIDs returned invalid number of IDs, want %v, got %v", len(want), len(ids))
+	}
+	for _, id := range ids {
+		if !want[id] {
+			t.Fatalf("UpdateMultipleReturningIDs returned ID of a row that was not updated: %d", id)
+		}
+	}
+
+	ids, err = testController.UpdateMultipleReturningIDs(&TestStruct{}, map[string]interface{}{
+		"FirstName": "Updated",
+	}, UpdateMultipleOptions{
+		Filters: map[string]interface{}{"Age": 100},
+	})
+	if err != nil || len(ids) != 0 {
+		t.Fatalf("UpdateMultipleReturningIDs failed to return no IDs when no rows match")
+	}
+}
@@ -1,9 +1,13 @@
 package structdbpostgres
 
 import (
+	"context"
 	"fmt"
+	"regexp"
 )
 
+var reViewName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
 // DropTables drop tables in the database for specified objects (see DropTable for a single struct)
 func (c Controller) DropTables(xobj ...interface{}) *ErrController {
 	for _, obj := range xobj {
@@ -26,20 +30,56 @@ func (c Controller) CreateTables(xobj ...interface{}) *ErrController {
 	return nil
 }
 
+// DumpSchema returns "CREATE TABLE" queries, followed by column comments, for specified objects, without
+// executing them, eg. to keep schema file in the repository. Tables are ordered by dependencies, so a struct comes
+// after the ones it joins or refers to with a field like 'ProductKindID', and otherwise they are in the same order
+// as objects. "CREATE VIEW" queries for objects registered with RegisterView come after them. It does not use the
+// database connection
+func (c Controller) DumpSchema(xobj ...interface{}) (string, *ErrController) {
+	schema := ""
+	views := ""
+	for _, obj := range c.sortByDependencies(xobj) {
+		h, err := c.getSQLGenerator(obj, nil, "")
+		if err != nil {
+			return "", err
+		}
+
+		if query, ok := c.views[c.getSQLGeneratorName(obj, false)]; ok {
+			views += h.GetQueryCreateView(query) + ";\n"
+			continue
+		}
+
+		q := h.GetQueryCreateTable()
+		if q == "" {
+			continue
+		}
+		schema += q + ";\n"
+		for _, qc := range h.GetQueriesCommentColumns() {
+			schema += qc + ";\n"
+		}
+	}
+	return schema + views, nil
+}
+
 // CreateTable creates database table to store specified type of objects. It takes struct name and its fields,
 // converts them into table and columns names (all lowercase with underscore), assigns column type based on the
-// field type, and then executes "CREATE TABLE" query on attached DB connection
+// field type, and then executes "CREATE TABLE" query on attached DB connection. Comments from '2db_comment' tags
+// are added to the columns
 func (c Controller) CreateTable(obj interface{}) *ErrController {
 	h, err := c.getSQLGenerator(obj, nil, "")
 	if err != nil {
 		return err
 	}
 
-	_, err2 := c.dbConn.Exec(h.GetQueryCreateTable())
+	_, err2 := c.exec(context.Background(), "CreateTable", h.GetQueryCreateTable())
 	if err2 != nil {
-		return &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err2),
+		return c.newErrDBQuery(err2)
+	}
+
+	for _, q := range h.GetQueriesCommentColumns() {
+		_, err2 = c.exec(context.Background(), "CreateTable", q)
+		if err2 != nil {
+			return c.newErrDBQuery(err2)
 		}
 	}
 	return nil
@@ -53,12 +93,32 @@ func (c Controller) DropTable(obj interface{}) *ErrController {
 		return err
 	}
 
-	_, err2 := c.dbConn.Exec(h.GetQueryDropTable())
+	_, err2 := c.exec(context.Background(), "DropTable", h.GetQueryDropTable())
 	if err2 != nil {
+		return c.newErrDBQuery(err2)
+	}
+	return nil
+}
+
+// RefreshMaterializedView executes "REFRESH MATERIALIZED VIEW" query for a view with specified name, that can be
+// prefixed with schema. With concurrently set, view is refreshed without locking out reads, which requires a
+// unique index on the view
+func (c Controller) RefreshMaterializedView(name string, concurrently bool) *ErrController {
+	if !reViewName.MatchString(name) {
 		return &ErrController{
-			Op:  "DBQuery",
-			Err: fmt.Errorf("Error executing DB query: %w", err2),
+			Op:  "InvalidName",
+			Err: fmt.Errorf("invalid view name %s", name),
 		}
 	}
+
+	q := "REFRESH MATERIALIZED VIEW "
+	if concurrently {
+		q += "CONCURRENTLY "
+	}
+
+	_, err := c.exec(context.Background(), "RefreshMaterializedView", q+name)
+	if err != nil {
+		return c.newErrDBQuery(err)
+	}
 	return nil
 }
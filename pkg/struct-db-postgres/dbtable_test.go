@@ -1,6 +1,7 @@
 package structdbpostgres
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -26,6 +27,62 @@ func TestCreateTables(t *testing.T) {
 	}
 }
 
+type CommentTestStruct struct {
+	ID    int64
+	Email string `2db_comment:"User's primary email"`
+}
+
+// TestCreateTablesWithComments tests if CreateTables adds comments from tags to the columns
+func TestCreateTablesWithComments(t *testing.T) {
+	st := &CommentTestStruct{}
+	testController.DropTables(st)
+	err := testController.CreateTables(st)
+	if err != nil {
+		t.Fatalf("CreateTables failed to create table with column comments: %s", err.Error())
+	}
+	defer testController.DropTables(st)
+
+	var comment string
+	err2 := dbConn.QueryRow("SELECT col_description('struct2db_comment_test_structs'::regclass, 2)").Scan(&comment)
+	if err2 != nil {
+		t.Fatalf("Failed to get column comment: %s", err2.Error())
+	}
+	if comment != "User's primary email" {
+		t.Fatalf("CreateTables failed to add column comment, want %s, got %s", "User's primary email", comment)
+	}
+}
+
+type CheckTestStruct struct {
+	ID       int64
+	Stock    int
+	Reserved int
+	_        struct{} `2db_check:".Stock >= 0"`
+	_        struct{} `2db_check:".Reserved <= .Stock"`
+}
+
+// TestCreateTablesWithChecks tests if CreateTables adds CHECK constraints from tags to the table
+func TestCreateTablesWithChecks(t *testing.T) {
+	st := &CheckTestStruct{}
+	testController.DropTables(st)
+	err := testController.CreateTables(st)
+	if err != nil {
+		t.Fatalf("CreateTables failed to create table with check constraints: %s", err.Error())
+	}
+	defer testController.DropTables(st)
+
+	err = testController.Save(&CheckTestStruct{Stock: 10, Reserved: 5}, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert object that meets check constraints: %s", err.Error())
+	}
+
+	for _, obj := range []*CheckTestStruct{{Stock: -1}, {Stock: 5, Reserved: 6}} {
+		err = testController.Save(obj, SaveOptions{})
+		if err == nil || err.Op != "DBQuery" {
+			t.Fatalf("Save failed to return error for object that violates check constraint: %v", obj)
+		}
+	}
+}
+
 // TestDropTables tests if DropTables drops tables in the database
 func TestDropTables(t *testing.T) {
 	st := &TableTestStruct{}
@@ -42,3 +99,78 @@ func TestDropTables(t *testing.T) {
 		t.Fatalf("DropTables failed to drop the table")
 	}
 }
+
+// TestRefreshMaterializedView tests if RefreshMaterializedView refreshes data in a materialized view
+func TestRefreshMaterializedView(t *testing.T) {
+	st := &TableTestStruct{}
+	testController.DropTables(st)
+	testController.CreateTables(st)
+
+	_, err2 := dbConn.Exec("CREATE MATERIALIZED VIEW struct2db_table_test_structs_cnt AS SELECT COUNT(*) AS cnt FROM struct2db_table_test_structs")
+	if err2 != nil {
+		t.Fatalf("Failed to create materialized view: %s", err2.Error())
+	}
+	defer dbConn.Exec("DROP MATERIALIZED VIEW struct2db_table_test_structs_cnt")
+	_, err2 = dbConn.Exec("CREATE UNIQUE INDEX ON struct2db_table_test_structs_cnt (cnt)")
+	if err2 != nil {
+		t.Fatalf("Failed to create index on materialized view: %s", err2.Error())
+	}
+
+	testController.Save(&TableTestStruct{Flags: 1}, SaveOptions{})
+	testController.Save(&TableTestStruct{Flags: 2}, SaveOptions{})
+
+	err := testController.RefreshMaterializedView("struct2db_table_test_structs_cnt", false)
+	if err != nil {
+		t.Fatalf("RefreshMaterializedView failed to refresh view: %s", err.Error())
+	}
+
+	var cnt int
+	dbConn.QueryRow("SELECT cnt FROM struct2db_table_test_structs_cnt").Scan(&cnt)
+	if cnt != 2 {
+		t.Fatalf("RefreshMaterializedView failed to refresh data in the view, want %v, got %v", 2, cnt)
+	}
+
+	testController.Save(&TableTestStruct{Flags: 3}, SaveOptions{})
+	err = testController.RefreshMaterializedView("public.struct2db_table_test_structs_cnt", true)
+	if err != nil {
+		t.Fatalf("RefreshMaterializedView failed to refresh view concurrently: %s", err.Error())
+	}
+	dbConn.QueryRow("SELECT cnt FROM struct2db_table_test_structs_cnt").Scan(&cnt)
+	if cnt != 3 {
+		t.Fatalf("RefreshMaterializedView failed to refresh data in the view concurrently, want %v, got %v", 3, cnt)
+	}
+
+	err = testController.RefreshMaterializedView("view; DROP TABLE users", false)
+	if err == nil || err.Op != "InvalidName" {
+		t.Fatalf("RefreshMaterializedView failed to return error for invalid view name")
+	}
+}
+
+// TestDumpSchema tests if DumpSchema returns queries creating tables and views
+func TestDumpSchema(t *testing.T) {
+	c := NewController(nil, "dump_", nil)
+	c.RegisterView(&ViewTestActiveItem{}, "SELECT 1")
+
+	got, err := c.DumpSchema(&ViewTestActiveItem{}, &TableTestStruct{}, &ProductKind{})
+	if err != nil {
+		t.Fatalf("DumpSchema failed to return schema: %s", err.Op)
+	}
+
+	want := "CREATE TABLE dump_table_test_structs (table_test_struct_id SERIAL PRIMARY KEY,table_test_struct_flags BIGINT NOT NULL DEFAULT 0);\n"
+	want += "CREATE TABLE dump_product_kinds (product_kind_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '');\n"
+	want += "CREATE VIEW dump_view_test_active_items AS SELECT 1;\n"
+	if got != want {
+		t.Fatalf("DumpSchema returned invalid schema, want %v, got %v", want, got)
+	}
+
+	got, err = c.DumpSchema(&Product{}, &ProductGroup{}, &ProductKind{})
+	if err != nil {
+		t.Fatalf("DumpSchema failed to return schema with dependencies: %s", err.Op)
+	}
+	kinds := strings.Index(got, "CREATE TABLE dump_product_kinds ")
+	products := strings.Index(got, "CREATE TABLE dump_products ")
+	groups := strings.Index(got, "CREATE TABLE dump_product_groups ")
+	if kinds == -1 || kinds > products || products > groups {
+		t.Fatalf("DumpSchema failed to order tables by dependencies, got %v", got)
+	}
+}
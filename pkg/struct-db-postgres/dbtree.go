@@ -0,0 +1,79 @@
+package structdbpostgres
+
+import (
+	"fmt"
+)
+
+// GetDescendants returns objects that are below the 'path' in the tree, eg. 'electronics.phones' for
+// 'electronics'. Struct must have a path field, tagged with 'path'. Other options work the same way as in Get
+func (c Controller) GetDescendants(newObjFunc func() interface{}, path string, options GetOptions) ([]interface{}, *ErrController) {
+	filters, err := c.getPathFilters(newObjFunc(), path, false, options.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	options.Filters = filters
+	return c.Get(newObjFunc, options)
+}
+
+// GetAncestors returns objects that are above the 'path' in the tree, eg. 'electronics' and 'electronics.phones'
+// for 'electronics.phones.android'. Struct must have a path field, tagged with 'path'. Other options work the same
+// way as in Get
+func (c Controller) GetAncestors(newObjFunc func() interface{}, path string, options GetOptions) ([]interface{}, *ErrController) {
+	filters, err := c.getPathFilters(newObjFunc(), path, true, options.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	options.Filters = filters
+	return c.Get(newObjFunc, options)
+}
+
+// getPathFilters returns filters with a raw condition that matches descendants or ancestors of 'path', excluding
+// the 'path' itself. When filters already contain a raw condition, both of them must be met
+func (c Controller) getPathFilters(obj interface{}, path string, ancestors bool, filters map[string]interface{}) (map[string]interface{}, *ErrController) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	f := h.GetPathFieldName()
+	if f == "" {
+		return nil, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("struct does not have a path field"),
+		}
+	}
+
+	var cond string
+	var values []interface{}
+	switch {
+	case h.IsPathLtree() && ancestors:
+		cond = fmt.Sprintf(".%s @> ? AND .%s <> ?", f, f)
+		values = []interface{}{path, path}
+	case h.IsPathLtree():
+		cond = fmt.Sprintf(".%s <@ ? AND .%s <> ?", f, f)
+		values = []interface{}{path, path}
+	case ancestors:
+		// starts_with is used instead of LIKE so that '%' and '_' in paths are not treated as wildcards
+		cond = fmt.Sprintf("starts_with(?::text, .%s || '.')", f)
+		values = []interface{}{path}
+	default:
+		cond = fmt.Sprintf("starts_with(.%s, ?::text || '.')", f)
+		values = []interface{}{path}
+	}
+
+	pathFilters := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		pathFilters[k] = v
+	}
+
+	raw, ok := filters["_raw"].([]interface{})
+	if ok && len(raw) > 0 && raw[0].(string) != "" {
+		cond = fmt.Sprintf("(%s) AND (%s)", raw[0].(string), cond)
+		values = append(append([]interface{}{}, raw[1:]...), values...)
+	}
+	pathFilters["_raw"] = append([]interface{}{cond}, values...)
+
+	return pathFilters, nil
+}
@@ -0,0 +1,82 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+type TreeCategory struct {
+	ID   int64
+	Name string
+	Path string `2db:"path db_type:ltree"`
+}
+
+type TreeFolder struct {
+	ID   int64
+	Name string
+	Path string `2db:"path"`
+}
+
+// TestGetDescendantsAndAncestors tests if objects below and above a path are returned, for ltree and string paths
+func TestGetDescendantsAndAncestors(t *testing.T) {
+	_, err := dbConn.Exec("CREATE EXTENSION IF NOT EXISTS ltree")
+	if err != nil {
+		t.Fatalf("Failed to create ltree extension: %s", err.Error())
+	}
+
+	paths := []string{"electronics", "electronics.phones", "electronics.phones.android", "electronics_old", "garden"}
+
+	testController.DropTable(&TreeCategory{})
+	testController.CreateTable(&TreeCategory{})
+	testController.DropTable(&TreeFolder{})
+	testController.CreateTable(&TreeFolder{})
+	for _, p := range paths {
+		testController.Save(&TreeCategory{Name: p, Path: p}, SaveOptions{})
+		testController.Save(&TreeFolder{Name: p, Path: p}, SaveOptions{})
+	}
+
+	for _, newObjFunc := range []func() interface{}{
+		func() interface{} { return &TreeCategory{} },
+		func() interface{} { return &TreeFolder{} },
+	} {
+		xobj, errCtl := testController.GetDescendants(newObjFunc, "electronics", GetOptions{Order: []string{"ID", "asc"}})
+		if errCtl != nil {
+			t.Fatalf("GetDescendants failed to get objects: %s", errCtl.Err.Error())
+		}
+		if len(xobj) != 2 || getTreeTestPath(xobj[0]) != "electronics.phones" || getTreeTestPath(xobj[1]) != "electronics.phones.android" {
+			t.Fatalf("GetDescendants returned invalid objects")
+		}
+
+		xobj, errCtl = testController.GetAncestors(newObjFunc, "electronics.phones.android", GetOptions{Order: []string{"ID", "asc"}})
+		if errCtl != nil {
+			t.Fatalf("GetAncestors failed to get objects: %s", errCtl.Err.Error())
+		}
+		if len(xobj) != 2 || getTreeTestPath(xobj[0]) != "electronics" || getTreeTestPath(xobj[1]) != "electronics.phones" {
+			t.Fatalf("GetAncestors returned invalid objects")
+		}
+
+		// raw filter passed in options must be met as well
+		xobj, errCtl = testController.GetDescendants(newObjFunc, "electronics", GetOptions{
+			Filters: map[string]interface{}{
+				"_raw": []interface{}{".Name = ?", "electronics.phones"},
+			},
+		})
+		if errCtl != nil || len(xobj) != 1 {
+			t.Fatalf("GetDescendants failed to apply raw filter")
+		}
+	}
+
+	_, errCtl := testController.GetDescendants(func() interface{} { return &TestStruct{} }, "electronics", GetOptions{})
+	if errCtl == nil || errCtl.Op != "InvalidField" {
+		t.Fatalf("GetDescendants failed to return error for struct without path field")
+	}
+}
+
+func getTreeTestPath(obj interface{}) string {
+	switch o := obj.(type) {
+	case *TreeCategory:
+		return o.Path
+	case *TreeFolder:
+		return o.Path
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package structdbpostgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// RegisterView marks struct as a read-only model backed by a database view defined by 'query', eg. 'SELECT ...
+// FROM ...'. Columns returned by the query must match struct fields, the same way table columns do. Get, GetCount
+// and other read methods work with such struct normally, while Save, Delete and other mutations return an error
+func (c Controller) RegisterView(obj interface{}, query string) *ErrController {
+	_, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	c.views[c.getSQLGeneratorName(obj, false)] = query
+	return nil
+}
+
+// CreateViews creates views in the database for specified objects, that have been registered with RegisterView
+func (c Controller) CreateViews(xobj ...interface{}) *ErrController {
+	for _, obj := range xobj {
+		h, err := c.getSQLGenerator(obj, nil, "")
+		if err != nil {
+			return err
+		}
+
+		query, ok := c.views[c.getSQLGeneratorName(obj, false)]
+		if !ok {
+			return &ErrController{
+				Op:  "NotView",
+				Err: fmt.Errorf("struct has not been registered as a view"),
+			}
+		}
+
+		_, err2 := c.exec(context.Background(), "CreateViews", h.GetQueryCreateView(query))
+		if err2 != nil {
+			return c.newErrDBQuery(err2)
+		}
+	}
+	return nil
+}
+
+// DropViews drops views in the database for specified objects
+func (c Controller) DropViews(xobj ...interface{}) *ErrController {
+	for _, obj := range xobj {
+		h, err := c.getSQLGenerator(obj, nil, "")
+		if err != nil {
+			return err
+		}
+
+		_, err2 := c.exec(context.Background(), "DropViews", h.GetQueryDropView())
+		if err2 != nil {
+			return c.newErrDBQuery(err2)
+		}
+	}
+	return nil
+}
+
+// checkNotView returns ErrController when struct has been registered as a view, and cannot be modified
+func (c Controller) checkNotView(obj interface{}) *ErrController {
+	if _, ok := c.views[c.getSQLGeneratorName(obj, false)]; ok {
+		return &ErrController{
+			Op:  "ReadOnly",
+			Err: fmt.Errorf("struct is a read-only view"),
+		}
+	}
+	return nil
+}
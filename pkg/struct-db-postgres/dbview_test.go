@@ -0,0 +1,71 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+type ViewTestItem struct {
+	ID     int64
+	Name   string
+	Active bool
+}
+
+type ViewTestActiveItem struct {
+	ID   int64
+	Name string
+}
+
+// TestViews tests if struct registered as a view can be created, read and not modified
+func TestViews(t *testing.T) {
+	testController.DropViews(&ViewTestActiveItem{})
+	testController.DropTable(&ViewTestItem{})
+	testController.CreateTable(&ViewTestItem{})
+
+	testController.Save(&ViewTestItem{Name: "First", Active: true}, SaveOptions{})
+	testController.Save(&ViewTestItem{Name: "Second", Active: false}, SaveOptions{})
+	testController.Save(&ViewTestItem{Name: "Third", Active: true}, SaveOptions{})
+
+	err := testController.RegisterView(&ViewTestActiveItem{}, "SELECT view_test_item_id AS view_test_active_item_id, name FROM struct2db_view_test_items WHERE active")
+	if err != nil {
+		t.Fatalf("RegisterView failed to register view: %s", err.Op)
+	}
+	err = testController.CreateViews(&ViewTestActiveItem{})
+	if err != nil {
+		t.Fatalf("CreateViews failed to create view: %s", err.Err.Error())
+	}
+
+	newObjFunc := func() interface{} { return &ViewTestActiveItem{} }
+
+	items, err := testController.Get(newObjFunc, GetOptions{Order: []string{"ID", "asc"}})
+	if err != nil {
+		t.Fatalf("Get failed to return objects from view: %s", err.Op)
+	}
+	if len(items) != 2 || items[0].(*ViewTestActiveItem).Name != "First" || items[1].(*ViewTestActiveItem).Name != "Third" {
+		t.Fatalf("Get failed to return correct objects from view")
+	}
+
+	cnt, err := testController.GetCount(newObjFunc, GetCountOptions{Filters: map[string]interface{}{"Name": "Third"}})
+	if err != nil || cnt != 1 {
+		t.Fatalf("GetCount failed to count objects in view")
+	}
+
+	item := items[0].(*ViewTestActiveItem)
+	item.Name = "Changed"
+	err = testController.Save(item, SaveOptions{})
+	if err == nil || err.Op != "ReadOnly" {
+		t.Fatalf("Save failed to return error for view")
+	}
+	err = testController.Delete(item, DeleteOptions{})
+	if err == nil || err.Op != "ReadOnly" {
+		t.Fatalf("Delete failed to return error for view")
+	}
+	err = testController.UpdateMultiple(item, map[string]interface{}{"Name": "Changed"}, UpdateMultipleOptions{AllowFullTableUpdate: true})
+	if err == nil || err.Op != "ReadOnly" {
+		t.Fatalf("UpdateMultiple failed to return error for view")
+	}
+
+	err = testController.CreateViews(&ViewTestItem{})
+	if err == nil || err.Op != "NotView" {
+		t.Fatalf("CreateViews failed to return error for struct that is not a view")
+	}
+}
@@ -0,0 +1,57 @@
+package structdbpostgres
+
+// RegisterDefaultGetOptions sets options that Get, and the methods built on it such as GetFirst, use for the
+// struct by default, eg. a filter excluding archived objects or an order. They are merged with options passed to the
+// call: default filters are added as a filter group, so they are AND-combined with the call's filters, also on the
+// same field, filter groups are added, and Order (or OrderBy), Limit, Fields and StatementTimeout are used only
+// when the call does not set them.
+// Default StatementTimeout is not used in a transaction, and EncodeCursor uses default Order too. HydrateJoined
+// is set when either of them sets it. Other options are always taken from the call. Defaults are skipped with
+// SkipDefaultOptions
+func (c Controller) RegisterDefaultGetOptions(obj interface{}, options GetOptions) *ErrController {
+	_, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	c.defaultGetOptions[c.getSQLGeneratorName(obj, false)] = options
+	return nil
+}
+
+// getOptionsWithDefaults returns options merged with the default ones registered for the struct
+func (c Controller) getOptionsWithDefaults(obj interface{}, options GetOptions) GetOptions {
+	if options.SkipDefaultOptions {
+		return options
+	}
+	defaults, ok := c.defaultGetOptions[c.getSQLGeneratorName(obj, false)]
+	if !ok {
+		return options
+	}
+
+	// Default filters are a separate group so that a call filter on the same field does not replace them
+	groups := []FilterGroup{}
+	if len(defaults.Filters) > 0 {
+		groups = append(groups, FilterGroup{Filters: defaults.Filters})
+	}
+	groups = append(groups, defaults.FilterGroups...)
+	if len(groups) > 0 {
+		options.FilterGroups = append(groups, options.FilterGroups...)
+	}
+
+	if len(options.Order) == 0 && len(options.OrderBy) == 0 {
+		options.Order = defaults.Order
+		options.OrderBy = defaults.OrderBy
+	}
+	if options.Limit == 0 {
+		options.Limit = defaults.Limit
+	}
+	if len(options.Fields) == 0 {
+		options.Fields = defaults.Fields
+	}
+	// Statement timeout is not supported in a transaction so the default one is not used there
+	if options.StatementTimeout == 0 && c.tx == nil {
+		options.StatementTimeout = defaults.StatementTimeout
+	}
+	options.HydrateJoined = options.HydrateJoined || defaults.HydrateJoined
+	return options
+}
@@ -0,0 +1,106 @@
+package structdbpostgres
+
+import (
+	"testing"
+	"time"
+)
+
+// Test struct for default GetOptions
+type DefaultsTestItem struct {
+	ID       int64
+	Name     string
+	Archived bool
+	Position int
+}
+
+// TestGetWithDefaultOptions tests if Get merges options registered for the struct with the ones passed to it
+func TestGetWithDefaultOptions(t *testing.T) {
+	c := NewController(dbConn, "struct2db_", nil)
+	c.DropTable(&DefaultsTestItem{})
+	c.CreateTable(&DefaultsTestItem{})
+
+	c.Save(&DefaultsTestItem{Name: "First", Position: 3}, SaveOptions{})
+	c.Save(&DefaultsTestItem{Name: "Second", Position: 1}, SaveOptions{})
+	c.Save(&DefaultsTestItem{Name: "Third", Position: 2, Archived: true}, SaveOptions{})
+	c.Save(&DefaultsTestItem{Name: "Fourth", Position: 4}, SaveOptions{})
+
+	err := c.RegisterDefaultGetOptions(&DefaultsTestItem{}, GetOptions{
+		Filters: map[string]interface{}{"Archived": false},
+		Order:   []string{"Position", "asc"},
+	})
+	if err != nil {
+		t.Fatalf("RegisterDefaultGetOptions failed: %s", err.Op)
+	}
+
+	newObjFunc := func() interface{} { return &DefaultsTestItem{} }
+	getNames := func(options GetOptions) string {
+		items, err := c.Get(newObjFunc, options)
+		if err != nil {
+			t.Fatalf("Get failed with default options: %s", err.Op)
+		}
+		names := ""
+		for _, item := range items {
+			names += item.(*DefaultsTestItem).Name + ","
+		}
+		return names
+	}
+
+	if got := getNames(GetOptions{}); got != "Second,First,Fourth," {
+		t.Fatalf("Get failed to use default filters and order, got %s", got)
+	}
+
+	got := getNames(GetOptions{Filters: map[string]interface{}{"Position": Gt(1)}})
+	if got != "First,Fourth," {
+		t.Fatalf("Get failed to combine default filters with the passed ones, got %s", got)
+	}
+
+	if got := getNames(GetOptions{Order: []string{"Name", "asc"}}); got != "First,Fourth,Second," {
+		t.Fatalf("Get failed to override default order, got %s", got)
+	}
+
+	if got := getNames(GetOptions{Filters: map[string]interface{}{"Archived": true}}); got != "" {
+		t.Fatalf("Get failed to keep default filter on the same field, got %s", got)
+	}
+
+	got = getNames(GetOptions{
+		Filters:      map[string]interface{}{"Position": Lt(4)},
+		FilterGroups: []FilterGroup{{Conjunction: RawConjuctionOR, Filters: map[string]interface{}{"Archived": true, "Name": "First"}}},
+	})
+	if got != "First," {
+		t.Fatalf("Get failed to combine default filters with the passed filter groups, got %s", got)
+	}
+
+	if got := getNames(GetOptions{SkipDefaultOptions: true, Order: []string{"ID", "asc"}}); got != "First,Second,Third,Fourth," {
+		t.Fatalf("Get failed to skip default options, got %s", got)
+	}
+
+	// Default statement timeout is not used in a transaction, where it would be an error
+	err = c.RegisterDefaultGetOptions(&DefaultsTestItem{}, GetOptions{
+		Order:            []string{"Position", "asc"},
+		StatementTimeout: 5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("RegisterDefaultGetOptions failed: %s", err.Op)
+	}
+	tx, err := c.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed: %s", err.Op)
+	}
+	items, err := tx.Get(newObjFunc, GetOptions{})
+	tx.Rollback()
+	if err != nil || len(items) != 4 {
+		t.Fatalf("Tx.Get failed with default statement timeout")
+	}
+
+	// Cursor is encoded with the default order
+	options := GetOptions{Limit: 3}
+	items, _ = c.Get(newObjFunc, options)
+	options.Cursor, err = c.EncodeCursor(items[len(items)-1], options)
+	if err != nil {
+		t.Fatalf("EncodeCursor failed with default order: %s", err.Op)
+	}
+	items, err = c.Get(newObjFunc, options)
+	if err != nil || len(items) != 1 || items[0].(*DefaultsTestItem).Name != "Fourth" {
+		t.Fatalf("Get failed to return next page with cursor encoded with default order")
+	}
+}
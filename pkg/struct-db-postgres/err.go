@@ -1,10 +1,18 @@
 package structdbpostgres
 
+import "errors"
+
+// ErrNotExist is wrapped by the error that is returned when there is no object to return, eg. from GetFirst
+var ErrNotExist = errors.New("object does not exist")
+
 // ErrController wraps original error that occurred in Err with name of the operation/step that failed, which is
-// in Op field
+// in Op field. When a database query fails, the query is in Query field, and its arguments are in Args only when
+// IncludeQueryArgs is set in ControllerConfig
 type ErrController struct {
-	Op  string
-	Err error
+	Op    string
+	Err   error
+	Query string
+	Args  []interface{}
 }
 
 func (e *ErrController) Error() string {
@@ -0,0 +1,57 @@
+package fake
+
+import (
+	stdb "github.com/mikolajgs/prototyping/pkg/struct-db-postgres"
+	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
+)
+
+// RegisterDefaultGetOptions sets options that Get uses for the struct by default, and merges them with options
+// passed to the call the same way as Controller does
+func (c *FakeController) RegisterDefaultGetOptions(obj interface{}, options stdb.GetOptions) *stdb.ErrController {
+	// EncodeCursor of Controller is used, and it encodes the cursor with the default order
+	errCtl := c.ctl.RegisterDefaultGetOptions(obj, options)
+	if errCtl != nil {
+		return errCtl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.defaultGetOptions[stsql.GetStructName(obj)] = options
+	return nil
+}
+
+// getOptionsWithDefaults returns options merged with the default ones registered for the struct
+func (c *FakeController) getOptionsWithDefaults(obj interface{}, options stdb.GetOptions) stdb.GetOptions {
+	if options.SkipDefaultOptions {
+		return options
+	}
+
+	c.mu.Lock()
+	defaults, ok := c.defaultGetOptions[stsql.GetStructName(obj)]
+	c.mu.Unlock()
+	if !ok {
+		return options
+	}
+
+	groups := []stdb.FilterGroup{}
+	if len(defaults.Filters) > 0 {
+		groups = append(groups, stdb.FilterGroup{Filters: defaults.Filters})
+	}
+	groups = append(groups, defaults.FilterGroups...)
+	if len(groups) > 0 {
+		options.FilterGroups = append(groups, options.FilterGroups...)
+	}
+
+	if len(options.Order) == 0 && len(options.OrderBy) == 0 {
+		options.Order = defaults.Order
+		options.OrderBy = defaults.OrderBy
+	}
+	if options.Limit == 0 {
+		options.Limit = defaults.Limit
+	}
+	if len(options.Fields) == 0 {
+		options.Fields = defaults.Fields
+	}
+	return options
+}
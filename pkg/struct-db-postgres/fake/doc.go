@@ -0,0 +1,4 @@
+// Package fake contains an in-memory implementation of structdbpostgres.Store that can be used in unit tests
+// instead of a Controller connected to a PostgreSQL database.
+
+package fake
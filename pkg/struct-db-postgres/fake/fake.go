@@ -0,0 +1,324 @@
+package fake
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+
+	stdb "github.com/mikolajgs/prototyping/pkg/struct-db-postgres"
+	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
+)
+
+var _ stdb.Store = (*FakeController)(nil)
+
+// Save validates object and stores its copy. If integer ID is not present then a new one is assigned to the object.
+// String primary key is not generated so it must be set
+func (c *FakeController) Save(obj interface{}, options stdb.SaveOptions) *stdb.ErrController {
+	if !options.SkipValidation {
+		b, invalidFields, err := c.ctl.Validate(obj, nil)
+		if err != nil {
+			return &stdb.ErrController{
+				Op:  "Validate",
+				Err: fmt.Errorf("Error when trying to validate: %w", err),
+			}
+		}
+		if !b {
+			return &stdb.ErrController{
+				Op: "Validate",
+				Err: &stdb.ErrValidation{
+					Fields: invalidFields,
+				},
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := stsql.GetStructName(obj)
+	if c.objs[n] == nil {
+		c.objs[n] = make(map[interface{}]interface{})
+	}
+
+	idField := reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj))
+	id := c.getObjID(obj)
+	if options.ForceInsertWithID {
+		if idField.IsZero() {
+			return &stdb.ErrController{
+				Op:  "MissingID",
+				Err: fmt.Errorf("object does not have an ID"),
+			}
+		}
+		if _, ok := c.objs[n][id]; ok {
+			return &stdb.ErrController{
+				Op:  "DBQuery",
+				Err: fmt.Errorf("Error executing DB query: duplicate ID %v", id),
+			}
+		}
+	}
+
+	if idField.IsZero() {
+		if idField.Kind() == reflect.String {
+			return &stdb.ErrController{
+				Op:  "MissingID",
+				Err: fmt.Errorf("object does not have an ID"),
+			}
+		}
+		c.lastIDs[n]++
+		id = c.lastIDs[n]
+		idField.SetInt(c.lastIDs[n])
+	} else {
+		if _, ok := c.objs[n][id]; !ok && options.NoInsert {
+			return nil
+		}
+		if idInt, ok := id.(int64); ok && idInt > c.lastIDs[n] {
+			c.lastIDs[n] = idInt
+		}
+	}
+
+	c.setTimestamps(obj, c.objs[n][id])
+
+	c.objs[n][id] = c.copyObj(obj)
+	return nil
+}
+
+// Load sets object's fields with values of stored object with a specific id. If it does not exist, all field
+// values in the struct are zeroed
+func (c *FakeController) Load(obj interface{}, id string, options stdb.LoadOptions) *stdb.ErrController {
+	var key interface{} = id
+	if reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj)).Kind() != reflect.String {
+		idInt, err := strconv.Atoi(id)
+		if err != nil {
+			return &stdb.ErrController{
+				Op:  "IDToInt",
+				Err: fmt.Errorf("Error converting string to int: %w", err),
+			}
+		}
+		key = int64(idInt)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stored, ok := c.objs[stsql.GetStructName(obj)][key]
+	if !ok {
+		c.ctl.ResetFields(obj)
+		return nil
+	}
+
+	reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(stored).Elem())
+	return nil
+}
+
+// Delete removes stored object when ID field is set. Once deleted, all field values are zeroed
+func (c *FakeController) Delete(obj interface{}, options stdb.DeleteOptions) *stdb.ErrController {
+	if reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj)).IsZero() {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.objs[stsql.GetStructName(obj)], c.getObjID(obj))
+	c.ctl.ResetFields(obj)
+	return nil
+}
+
+// DeleteMultiple removes stored objects that match specified filters
+func (c *FakeController) DeleteMultiple(obj interface{}, options stdb.DeleteMultipleOptions) *stdb.ErrController {
+	if len(options.Filters) == 0 && !options.AllowFullTableUpdate {
+		return &stdb.ErrController{
+			Op:  "UnsafeFullTable",
+			Err: fmt.Errorf("refusing to delete all rows without filters"),
+		}
+	}
+
+	errCtl := c.validateFilters(obj, options.Filters)
+	if errCtl != nil {
+		return errCtl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := stsql.GetStructName(obj)
+	for id, stored := range c.objs[n] {
+		match, errCtl := c.matchFilters(stored, options.Filters)
+		if errCtl != nil {
+			return errCtl
+		}
+		if match {
+			delete(c.objs[n], id)
+		}
+	}
+	return nil
+}
+
+// UpdateMultiple updates specific fields in stored objects that match specified filters
+func (c *FakeController) UpdateMultiple(obj interface{}, values map[string]interface{}, options stdb.UpdateMultipleOptions) *stdb.ErrController {
+	if len(options.Filters) == 0 && !options.AllowFullTableUpdate {
+		return &stdb.ErrController{
+			Op:  "UnsafeFullTable",
+			Err: fmt.Errorf("refusing to update all rows without filters"),
+		}
+	}
+
+	if len(values) < 1 {
+		return &stdb.ErrController{
+			Op:  "MissingValues",
+			Err: fmt.Errorf("missing values for update"),
+		}
+	}
+
+	if options.ConvertValuesFromString {
+		values = c.ctl.StringToFieldValues(obj, values)
+	}
+
+	if !options.SkipValidation {
+		b, invalidFields, err := c.ctl.Validate(obj, values)
+		if err != nil {
+			return &stdb.ErrController{
+				Op:  "ValidateValues",
+				Err: fmt.Errorf("Error when trying to validate values: %w", err),
+			}
+		}
+		if !b {
+			return &stdb.ErrController{
+				Op: "ValidateValues",
+				Err: &stdb.ErrValidation{
+					Fields: invalidFields,
+				},
+			}
+		}
+	}
+
+	errCtl := c.validateFilters(obj, options.Filters)
+	if errCtl != nil {
+		return errCtl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, stored := range c.objs[stsql.GetStructName(obj)] {
+		match, errCtl := c.matchFilters(stored, options.Filters)
+		if errCtl != nil {
+			return errCtl
+		}
+		if !match {
+			continue
+		}
+		for k, v := range values {
+			errCtl := c.setField(stored, k, v)
+			if errCtl != nil {
+				return errCtl
+			}
+		}
+	}
+	return nil
+}
+
+// Get returns a list of copies of stored objects that match specified filters, ordered, limited and offset. With
+// Cursor, only objects after it are returned
+func (c *FakeController) Get(newObjFunc func() interface{}, options stdb.GetOptions) ([]interface{}, *stdb.ErrController) {
+	obj := newObjFunc()
+	options = c.getOptionsWithDefaults(obj, options)
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
+
+	errCtl := c.validateFilters(obj, options.Filters)
+	if errCtl != nil {
+		return nil, errCtl
+	}
+	for _, group := range options.FilterGroups {
+		errCtl = c.validateFilters(obj, group.Filters)
+		if errCtl != nil {
+			return nil, errCtl
+		}
+	}
+	errCtl = c.validateFields(obj, options.Fields)
+	if errCtl != nil {
+		return nil, errCtl
+	}
+	order, errCtl := c.getOrder(obj, options.Order, options.OrderBy)
+	if errCtl != nil {
+		return nil, errCtl
+	}
+	var ks *keyset
+	if options.Cursor != "" {
+		order = c.getCursorOrder(obj, order, options.DisableOrderTiebreaker)
+		ks, errCtl = c.decodeCursor(obj, order, options.Cursor)
+		if errCtl != nil {
+			return nil, errCtl
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	matched, errCtl := c.getMatching(obj, options.Filters)
+	if errCtl != nil {
+		return nil, errCtl
+	}
+	matched, errCtl = c.getMatchingGroups(matched, options.FilterGroups)
+	if errCtl != nil {
+		return nil, errCtl
+	}
+	if ks != nil {
+		matched = c.getMatchingKeyset(matched, ks)
+	}
+
+	c.sortObjs(matched, order)
+
+	if options.Offset > 0 {
+		if options.Offset >= len(matched) {
+			matched = matched[:0]
+		} else {
+			matched = matched[options.Offset:]
+		}
+	}
+	if options.Limit > 0 && options.Limit < len(matched) {
+		matched = matched[:options.Limit]
+	}
+	if c.maxRows > 0 && len(matched) > c.maxRows {
+		return nil, &stdb.ErrController{
+			Op:  "TooManyRows",
+			Err: fmt.Errorf("query returned more than %d rows", c.maxRows),
+		}
+	}
+
+	var v []interface{}
+	for _, stored := range matched {
+		newObj := newObjFunc()
+		reflect.ValueOf(newObj).Elem().Set(reflect.ValueOf(stored).Elem())
+		if len(options.Fields) > 0 {
+			c.zeroFieldsExcept(newObj, options.Fields)
+		}
+
+		if options.RowObjTransformFunc != nil {
+			v = append(v, options.RowObjTransformFunc(newObj))
+			continue
+		}
+		v = append(v, newObj)
+	}
+	return v, nil
+}
+
+// GetCount returns count of stored objects that match specified filters
+func (c *FakeController) GetCount(newObjFunc func() interface{}, options stdb.GetCountOptions) (int64, *stdb.ErrController) {
+	obj := newObjFunc()
+	options.Filters = c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
+
+	errCtl := c.validateFilters(obj, options.Filters)
+	if errCtl != nil {
+		return 0, errCtl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	matched, errCtl := c.getMatching(obj, options.Filters)
+	if errCtl != nil {
+		return 0, errCtl
+	}
+	return int64(len(matched)), nil
+}
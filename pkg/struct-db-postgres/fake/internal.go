@@ -0,0 +1,467 @@
+package fake
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	stdb "github.com/mikolajgs/prototyping/pkg/struct-db-postgres"
+	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
+)
+
+func (c *FakeController) copyObj(obj interface{}) interface{} {
+	v := reflect.ValueOf(obj).Elem()
+	cp := reflect.New(v.Type())
+	cp.Elem().Set(v)
+	return cp.Interface()
+}
+
+// getIDFieldName returns name of the primary key field, which is the string or integer field with 'pk' tag, the
+// same as in Controller, or ID when there is none
+func (c *FakeController) getIDFieldName(obj interface{}) string {
+	t := reflect.TypeOf(obj).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		k := t.Field(i).Type.Kind()
+		if k != reflect.String && k != reflect.Int64 && k != reflect.Int {
+			continue
+		}
+		for _, opt := range strings.Split(t.Field(i).Tag.Get(c.tagName), " ") {
+			if opt == "pk" {
+				return t.Field(i).Name
+			}
+		}
+	}
+	return "ID"
+}
+
+// getObjID returns value of the primary key field, which is the key of stored object. It is a string or an int64
+func (c *FakeController) getObjID(obj interface{}) interface{} {
+	v := reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj))
+	if v.Kind() == reflect.String {
+		return v.String()
+	}
+	return v.Int()
+}
+
+// getTimestampFieldName returns name of the time.Time field with 'created_at' or 'updated_at' tag, or empty string
+func (c *FakeController) getTimestampFieldName(obj interface{}, tag string) string {
+	t := reflect.TypeOf(obj).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type != reflect.TypeOf(time.Time{}) {
+			continue
+		}
+		for _, opt := range strings.Split(t.Field(i).Tag.Get(c.tagName), " ") {
+			if opt == tag {
+				return t.Field(i).Name
+			}
+		}
+	}
+	return ""
+}
+
+// setTimestamps sets 'updated_at' field to the current time, and 'created_at' field when object is new, the same as
+// Controller does. Stored object keeps the time it was created at
+func (c *FakeController) setTimestamps(obj interface{}, stored interface{}) {
+	now := reflect.ValueOf(time.Now().Truncate(time.Microsecond))
+	val := reflect.ValueOf(obj).Elem()
+	if createdAt := c.getTimestampFieldName(obj, "created_at"); createdAt != "" {
+		if stored != nil {
+			val.FieldByName(createdAt).Set(reflect.ValueOf(stored).Elem().FieldByName(createdAt))
+		} else if val.FieldByName(createdAt).IsZero() {
+			val.FieldByName(createdAt).Set(now)
+		}
+	}
+	if updatedAt := c.getTimestampFieldName(obj, "updated_at"); updatedAt != "" {
+		val.FieldByName(updatedAt).Set(now)
+	}
+}
+
+// removeEmptyFilters returns copy of filters without ones that have an empty string value, or zero value of any
+// type when zero is true, the same as Controller does. Lists are kept
+func (c *FakeController) removeEmptyFilters(filters map[string]interface{}, empty bool, zero bool) map[string]interface{} {
+	if (!empty && !zero) || len(filters) == 0 {
+		return filters
+	}
+
+	f := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		if !strings.HasPrefix(k, "_") && !stsql.IsFilterValueList(v) {
+			if str, ok := v.(string); ok && str == "" {
+				continue
+			}
+			if zero && (v == nil || reflect.ValueOf(v).IsZero()) {
+				continue
+			}
+		}
+		f[k] = v
+	}
+	return f
+}
+
+func (c *FakeController) validateFilters(obj interface{}, filters map[string]interface{}) *stdb.ErrController {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	b, invalidFields, err := c.ctl.Validate(obj, filters)
+	if err != nil {
+		return &stdb.ErrController{
+			Op:  "ValidateFilters",
+			Err: fmt.Errorf("Error when trying to validate filters: %w", err),
+		}
+	}
+	if !b {
+		return &stdb.ErrController{
+			Op: "ValidateFilters",
+			Err: &stdb.ErrValidation{
+				Fields: invalidFields,
+			},
+		}
+	}
+	return nil
+}
+
+// getMatching returns stored objects of the same type as obj that match filters, sorted by primary key
+func (c *FakeController) getMatching(obj interface{}, filters map[string]interface{}) ([]interface{}, *stdb.ErrController) {
+	stored := c.objs[stsql.GetStructName(obj)]
+
+	ids := []interface{}{}
+	for id := range stored {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return c.compareValues(reflect.ValueOf(ids[i]), reflect.ValueOf(ids[j])) < 0
+	})
+
+	matched := []interface{}{}
+	for _, id := range ids {
+		match, errCtl := c.matchFilters(stored[id], filters)
+		if errCtl != nil {
+			return nil, errCtl
+		}
+		if match {
+			matched = append(matched, stored[id])
+		}
+	}
+	return matched, nil
+}
+
+// getMatchingGroups returns objects that match all the filter groups
+func (c *FakeController) getMatchingGroups(objs []interface{}, groups []stdb.FilterGroup) ([]interface{}, *stdb.ErrController) {
+	if len(groups) == 0 {
+		return objs, nil
+	}
+	matched := []interface{}{}
+	for _, obj := range objs {
+		match := true
+		for _, group := range groups {
+			var errCtl *stdb.ErrController
+			match, errCtl = c.matchFilterGroup(obj, group)
+			if errCtl != nil {
+				return nil, errCtl
+			}
+			if !match {
+				break
+			}
+		}
+		if match {
+			matched = append(matched, obj)
+		}
+	}
+	return matched, nil
+}
+
+// matchFilterGroup checks if object matches filters of the group, all of them or, with RawConjuctionOR, any of them
+func (c *FakeController) matchFilterGroup(obj interface{}, group stdb.FilterGroup) (bool, *stdb.ErrController) {
+	if group.Conjunction != stdb.RawConjuctionOR {
+		return c.matchFilters(obj, group.Filters)
+	}
+	match := len(group.Filters) == 0
+	for k, fv := range group.Filters {
+		m, errCtl := c.matchFilters(obj, map[string]interface{}{k: fv})
+		if errCtl != nil {
+			return false, errCtl
+		}
+		match = match || m
+	}
+	return match, nil
+}
+
+// matchFilters checks if object's fields are equal to filter values
+func (c *FakeController) matchFilters(obj interface{}, filters map[string]interface{}) (bool, *stdb.ErrController) {
+	v := reflect.ValueOf(obj).Elem()
+	for k, fv := range filters {
+		if k == "_rawConjuction" {
+			continue
+		}
+		if _, ok := fv.(stsql.Raw); ok || k == "_raw" {
+			return false, &stdb.ErrController{
+				Op:  "UnsupportedFilter",
+				Err: fmt.Errorf("filter %s is not supported by FakeController", k),
+			}
+		}
+
+		field := v.FieldByName(k)
+		// Filter named ID is for the primary key field when there is no ID field, the same as in Controller
+		if !field.IsValid() && k == "ID" {
+			field = v.FieldByName(c.getIDFieldName(obj))
+		}
+		if !field.IsValid() {
+			return false, &stdb.ErrController{
+				Op:  "InvalidFilter",
+				Err: fmt.Errorf("field %s does not exist", k),
+			}
+		}
+
+		if stsql.IsFilterValueList(fv) {
+			match, errCtl := c.matchAny(field, k, reflect.ValueOf(fv))
+			if errCtl != nil || !match {
+				return false, errCtl
+			}
+			continue
+		}
+
+		op := "="
+		if f, ok := fv.(stsql.Filter); ok {
+			op = f.Operator()
+			fv = f.Value()
+		}
+
+		// Only pointer fields can be NULL
+		if op == "IS NULL" || op == "IS NOT NULL" {
+			isNull := field.Kind() == reflect.Ptr && field.IsNil()
+			if isNull != (op == "IS NULL") {
+				return false, nil
+			}
+			continue
+		}
+
+		if op == "LIKE" || op == "ILIKE" {
+			pattern, _ := fv.(string)
+			if field.Kind() != reflect.String || !c.matchLike(field.String(), pattern, op == "ILIKE") {
+				return false, nil
+			}
+			continue
+		}
+
+		val := reflect.ValueOf(fv)
+		if !val.IsValid() || !val.Type().ConvertibleTo(field.Type()) {
+			return false, &stdb.ErrController{
+				Op:  "InvalidFilter",
+				Err: fmt.Errorf("invalid value for filter %s", k),
+			}
+		}
+		if !c.matchOperator(op, c.compareValues(field, val.Convert(field.Type()))) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// matchAny returns true when field value is equal to any of the items in the list
+func (c *FakeController) matchAny(field reflect.Value, k string, list reflect.Value) (bool, *stdb.ErrController) {
+	for j := 0; j < list.Len(); j++ {
+		val := list.Index(j)
+		if val.Kind() == reflect.Interface {
+			val = val.Elem()
+		}
+		if !val.IsValid() || !val.Type().ConvertibleTo(field.Type()) {
+			return false, &stdb.ErrController{
+				Op:  "InvalidFilter",
+				Err: fmt.Errorf("invalid value for filter %s", k),
+			}
+		}
+		if c.compareValues(field, val.Convert(field.Type())) == 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// matchLike returns true when value matches LIKE pattern, where '%' is any string, '_' is any character and
+// backslash escapes them
+func (c *FakeController) matchLike(value string, pattern string, caseInsensitive bool) bool {
+	re := "(?s)^"
+	if caseInsensitive {
+		re = "(?is)^"
+	}
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			re += regexp.QuoteMeta(string(r))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			re += ".*"
+		case r == '_':
+			re += "."
+		default:
+			re += regexp.QuoteMeta(string(r))
+		}
+	}
+	return regexp.MustCompile(re + "$").MatchString(value)
+}
+
+// matchOperator returns true when result of comparing field value with filter value satisfies the operator
+func (c *FakeController) matchOperator(op string, cmp int) bool {
+	switch op {
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case "!=":
+		return cmp != 0
+	default:
+		return cmp == 0
+	}
+}
+
+// setField sets object's field value, converting it to field's type
+func (c *FakeController) setField(obj interface{}, fieldName string, value interface{}) *stdb.ErrController {
+	field := reflect.ValueOf(obj).Elem().FieldByName(fieldName)
+	if !field.IsValid() {
+		return nil
+	}
+
+	val := reflect.ValueOf(value)
+	if !val.IsValid() || !val.Type().ConvertibleTo(field.Type()) {
+		return &stdb.ErrController{
+			Op:  "InvalidValue",
+			Err: fmt.Errorf("invalid value for field %s", fieldName),
+		}
+	}
+	field.Set(val.Convert(field.Type()))
+	return nil
+}
+
+// validateFields checks if object has all the fields, eg. from GetOptions.Fields
+func (c *FakeController) validateFields(obj interface{}, fieldNames []string) *stdb.ErrController {
+	v := reflect.ValueOf(obj).Elem()
+	for _, fieldName := range fieldNames {
+		if !v.FieldByName(fieldName).IsValid() {
+			return &stdb.ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s does not exist", fieldName),
+			}
+		}
+	}
+	return nil
+}
+
+// zeroFieldsExcept sets all object's fields, apart from primary key and the ones in fieldNames, to zero values, which
+// is what Get with GetOptions.Fields returns
+func (c *FakeController) zeroFieldsExcept(obj interface{}, fieldNames []string) {
+	keep := map[string]bool{c.getIDFieldName(obj): true}
+	for _, fieldName := range fieldNames {
+		keep[fieldName] = true
+	}
+
+	v := reflect.ValueOf(obj).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if !keep[v.Type().Field(i).Name] && v.Field(i).CanSet() {
+			v.Field(i).Set(reflect.Zero(v.Field(i).Type()))
+		}
+	}
+}
+
+// getOrder returns order, or orderBy turned into the same list of field and direction pairs
+func (c *FakeController) getOrder(obj interface{}, order []string, orderBy []stdb.OrderBy) ([]string, *stdb.ErrController) {
+	if len(orderBy) == 0 {
+		return order, nil
+	}
+	if len(order) > 0 {
+		return nil, &stdb.ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("Order and OrderBy cannot be both set"),
+		}
+	}
+
+	v := reflect.ValueOf(obj).Elem()
+	for _, o := range orderBy {
+		if !v.FieldByName(o.Field).IsValid() {
+			return nil, &stdb.ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s does not exist", o.Field),
+			}
+		}
+		d := "asc"
+		if o.Desc {
+			d = "desc"
+		}
+		order = append(order, o.Field, d)
+	}
+	return order, nil
+}
+
+// sortObjs sorts objects using order which is a list of field name and direction pairs, eg. {"Age", "desc"}
+func (c *FakeController) sortObjs(objs []interface{}, order []string) {
+	if len(order) == 0 {
+		return
+	}
+
+	sort.SliceStable(objs, func(i, j int) bool {
+		vi := reflect.ValueOf(objs[i]).Elem()
+		vj := reflect.ValueOf(objs[j]).Elem()
+		for o := 0; o < len(order); o = o + 2 {
+			fi := vi.FieldByName(order[o])
+			fj := vj.FieldByName(order[o])
+			if !fi.IsValid() {
+				continue
+			}
+
+			cmp := c.compareValues(fi, fj)
+			if cmp == 0 {
+				continue
+			}
+			// Field at the end without direction is ascending
+			if o+1 < len(order) && order[o+1] == "desc" {
+				return cmp > 0
+			}
+			return cmp < 0
+		}
+		return false
+	})
+}
+
+func (c *FakeController) compareValues(a reflect.Value, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.compareOrdered(a.Int() < b.Int(), a.Int() > b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.compareOrdered(a.Uint() < b.Uint(), a.Uint() > b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return c.compareOrdered(a.Float() < b.Float(), a.Float() > b.Float())
+	case reflect.String:
+		return c.compareOrdered(a.String() < b.String(), a.String() > b.String())
+	case reflect.Bool:
+		return c.compareOrdered(!a.Bool() && b.Bool(), a.Bool() && !b.Bool())
+	case reflect.Struct:
+		ta, okA := a.Interface().(time.Time)
+		tb, okB := b.Interface().(time.Time)
+		if !okA || !okB {
+			return 0
+		}
+		return c.compareOrdered(ta.Before(tb), ta.After(tb))
+	default:
+		return 0
+	}
+}
+
+func (c *FakeController) compareOrdered(less bool, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
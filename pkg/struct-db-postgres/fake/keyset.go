@@ -0,0 +1,116 @@
+package fake
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	stdb "github.com/mikolajgs/prototyping/pkg/struct-db-postgres"
+)
+
+// EncodeCursor returns a cursor for keyset pagination that is passed in GetOptions.Cursor, the same as the one
+// from Controller, so both of them can be used with the same code
+func (c *FakeController) EncodeCursor(obj interface{}, options stdb.GetOptions) (string, *stdb.ErrController) {
+	return c.ctl.EncodeCursor(obj, options)
+}
+
+// keyset is decoded cursor with values of the ordered fields of the last object of the previous page
+type keyset struct {
+	fields []string
+	values []reflect.Value
+	desc   bool
+}
+
+// getCursorOrder returns order with the primary key added at the end, the same way as Controller adds it, unless
+// the tiebreaker is disabled or the order already has it
+func (c *FakeController) getCursorOrder(obj interface{}, order []string, disableTiebreaker bool) []string {
+	idField := c.getIDFieldName(obj)
+	if disableTiebreaker {
+		return order
+	}
+	for i := 0; i < len(order); i += 2 {
+		if order[i] == idField {
+			return order
+		}
+	}
+	return append(append([]string{}, order...), idField, "asc")
+}
+
+// decodeCursor returns values from the cursor, converted to types of the ordered fields. All of them must be
+// ordered in the same direction
+func (c *FakeController) decodeCursor(obj interface{}, order []string, cursor string) (*keyset, *stdb.ErrController) {
+	if len(order) == 0 || len(order)%2 != 0 {
+		return nil, &stdb.ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("cursor requires order with pairs of field name and direction"),
+		}
+	}
+
+	t := reflect.TypeOf(obj).Elem()
+	ks := &keyset{desc: order[1] == "desc"}
+	for i := 0; i < len(order); i += 2 {
+		if _, ok := t.FieldByName(order[i]); !ok {
+			return nil, &stdb.ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s in order does not exist", order[i]),
+			}
+		}
+		if (order[i+1] == "desc") != ks.desc {
+			return nil, &stdb.ErrController{
+				Op:  "InvalidOptions",
+				Err: fmt.Errorf("cursor requires all the fields in order, including ID, to have the same direction"),
+			}
+		}
+		ks.fields = append(ks.fields, order[i])
+	}
+
+	var raw []json.RawMessage
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(b, &raw)
+	}
+	if err == nil && len(raw) != len(ks.fields) {
+		err = fmt.Errorf("cursor has %d values and order has %d fields", len(raw), len(ks.fields))
+	}
+	if err != nil {
+		return nil, &stdb.ErrController{
+			Op:  "InvalidCursor",
+			Err: fmt.Errorf("error decoding cursor: %w", err),
+		}
+	}
+
+	for i, f := range ks.fields {
+		sf, _ := t.FieldByName(f)
+		val := reflect.New(sf.Type)
+		err = json.Unmarshal(raw[i], val.Interface())
+		if err != nil {
+			return nil, &stdb.ErrController{
+				Op:  "InvalidCursor",
+				Err: fmt.Errorf("error decoding value of %s from cursor: %w", f, err),
+			}
+		}
+		ks.values = append(ks.values, val.Elem())
+	}
+	return ks, nil
+}
+
+// getMatchingKeyset returns objects that come after the cursor, comparing values of the ordered fields one by one,
+// as the row value comparison in the database does
+func (c *FakeController) getMatchingKeyset(objs []interface{}, ks *keyset) []interface{} {
+	matched := []interface{}{}
+	for _, obj := range objs {
+		v := reflect.ValueOf(obj).Elem()
+		cmp := 0
+		for i, f := range ks.fields {
+			cmp = c.compareValues(v.FieldByName(f), ks.values[i])
+			if cmp != 0 {
+				break
+			}
+		}
+		if (!ks.desc && cmp > 0) || (ks.desc && cmp < 0) {
+			matched = append(matched, obj)
+		}
+	}
+	return matched
+}
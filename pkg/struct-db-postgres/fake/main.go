@@ -0,0 +1,44 @@
+package fake
+
+import (
+	"sync"
+
+	stdb "github.com/mikolajgs/prototyping/pkg/struct-db-postgres"
+)
+
+// FakeController keeps objects in memory, in maps per struct name, and implements the same methods as
+// structdbpostgres.Controller does. Objects are keyed by their primary key, which is ID or the field with 'pk'
+// tag, and it can be an integer or a string. Keyset pagination with Cursor, default Get options, MaxRows, ignoring
+// empty filters and 'created_at' and 'updated_at' fields work the same as in Controller. Only basic filters
+// (field-value pairs) are supported, and the '_raw' filter and Raw values are not. There is no cascade delete, and
+// StatementTimeout is ignored.
+type FakeController struct {
+	// Controller is used only for methods that do not require database connection, such as Validate
+	ctl               *stdb.Controller
+	objs              map[string]map[interface{}]interface{}
+	lastIDs           map[string]int64
+	defaultGetOptions map[string]stdb.GetOptions
+	tagName           string
+	maxRows           int
+	mu                sync.Mutex
+}
+
+// NewFakeController returns new FakeController object. Config is used the same way as in structdbpostgres.NewController
+func NewFakeController(cfg *stdb.ControllerConfig) *FakeController {
+	tagName := "2db"
+	if cfg != nil && cfg.TagName != "" {
+		tagName = cfg.TagName
+	}
+	maxRows := 0
+	if cfg != nil && cfg.MaxRows > 0 {
+		maxRows = cfg.MaxRows
+	}
+	return &FakeController{
+		ctl:               stdb.NewController(nil, "", cfg),
+		objs:              make(map[string]map[interface{}]interface{}),
+		lastIDs:           make(map[string]int64),
+		defaultGetOptions: make(map[string]stdb.GetOptions),
+		tagName:           tagName,
+		maxRows:           maxRows,
+	}
+}
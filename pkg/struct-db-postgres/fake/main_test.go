@@ -0,0 +1,404 @@
+package fake
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	stdb "github.com/mikolajgs/prototyping/pkg/struct-db-postgres"
+)
+
+// Test struct for all the tests
+type TestStruct struct {
+	ID        int64
+	FirstName string `2db:"req lenmin:2 lenmax:30"`
+	Age       int    `2db:"valmin:1 valmax:120"`
+	Price     int
+}
+
+func newTestStruct() interface{} {
+	return &TestStruct{}
+}
+
+func createFakeControllerWithData() *FakeController {
+	c := NewFakeController(nil)
+	for i := 1; i < 11; i++ {
+		c.Save(&TestStruct{
+			FirstName: fmt.Sprintf("Name%02d", i),
+			Age:       10 + i%3,
+			Price:     100,
+		}, stdb.SaveOptions{})
+	}
+	return c
+}
+
+// TestSaveAndLoad tests if Save stores object with a new ID and Load gets it back
+func TestSaveAndLoad(t *testing.T) {
+	c := NewFakeController(nil)
+
+	ts := &TestStruct{FirstName: "John", Age: 37}
+	err := c.Save(ts, stdb.SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to store object: %s", err.Op)
+	}
+	if ts.ID != 1 {
+		t.Fatalf("Save failed to set ID, want %v, got %v", 1, ts.ID)
+	}
+
+	ts.Age = 38
+	ts2 := &TestStruct{}
+	c.Load(ts2, "1", stdb.LoadOptions{})
+	if ts2.FirstName != "John" || ts2.Age != 37 {
+		t.Fatalf("Load failed to get stored copy of the object")
+	}
+
+	err = c.Save(&TestStruct{FirstName: "x", Age: 37}, stdb.SaveOptions{})
+	if err == nil || err.Op != "Validate" {
+		t.Fatalf("Save failed to validate object")
+	}
+
+	ts3 := &TestStruct{ID: 5, FirstName: "Jane"}
+	c.Load(ts3, "2", stdb.LoadOptions{})
+	if ts3.ID != 0 || ts3.FirstName != "" {
+		t.Fatalf("Load failed to zero fields when object does not exist")
+	}
+}
+
+// Test structs with a primary key other than ID
+type SessionTestStruct struct {
+	Uuid   string `2db:"pk"`
+	UserID int64
+}
+
+type PersonTestStruct struct {
+	PersonID int64 `2db:"pk"`
+	Name     string
+}
+
+// TestSaveAndLoadWithPrimaryKey tests if objects are stored under the field with 'pk' tag, which can be a string
+func TestSaveAndLoadWithPrimaryKey(t *testing.T) {
+	c := NewFakeController(nil)
+
+	err := c.Save(&SessionTestStruct{UserID: 1}, stdb.SaveOptions{})
+	if err == nil || err.Op != "MissingID" {
+		t.Fatalf("Save failed to return MissingID for an empty string primary key")
+	}
+
+	uuid := "0b0c9c55-8d6f-4a4e-9d56-6f5a1e2c3b4d"
+	err = c.Save(&SessionTestStruct{Uuid: uuid, UserID: 1}, stdb.SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to store object with string primary key: %s", err.Op)
+	}
+	err = c.Save(&SessionTestStruct{Uuid: uuid, UserID: 2}, stdb.SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to update object with string primary key: %s", err.Op)
+	}
+
+	s := &SessionTestStruct{}
+	err = c.Load(s, uuid, stdb.LoadOptions{})
+	if err != nil || s.Uuid != uuid || s.UserID != 2 {
+		t.Fatalf("Load failed to get object with string primary key")
+	}
+	cnt, _ := c.GetCount(func() interface{} { return &SessionTestStruct{} }, stdb.GetCountOptions{})
+	if cnt != 1 {
+		t.Fatalf("Save stored invalid number of objects with string primary key, want %v, got %v", 1, cnt)
+	}
+
+	c.Delete(s, stdb.DeleteOptions{})
+	cnt, _ = c.GetCount(func() interface{} { return &SessionTestStruct{} }, stdb.GetCountOptions{})
+	if s.Uuid != "" || cnt != 0 {
+		t.Fatalf("Delete failed to remove object with string primary key")
+	}
+
+	p := &PersonTestStruct{Name: "John"}
+	err = c.Save(p, stdb.SaveOptions{})
+	if err != nil || p.PersonID != 1 {
+		t.Fatalf("Save failed to set new PersonID, want %v, got %v", 1, p.PersonID)
+	}
+	c.Save(&PersonTestStruct{PersonID: 5, Name: "Jane"}, stdb.SaveOptions{})
+	p2 := &PersonTestStruct{Name: "Jim"}
+	c.Save(p2, stdb.SaveOptions{})
+	if p2.PersonID != 6 {
+		t.Fatalf("Save failed to set PersonID after the highest one, want %v, got %v", 6, p2.PersonID)
+	}
+
+	p3 := &PersonTestStruct{}
+	err = c.Load(p3, "5", stdb.LoadOptions{})
+	if err != nil || p3.Name != "Jane" {
+		t.Fatalf("Load failed to get object with PersonID primary key")
+	}
+
+	xobj, _ := c.Get(func() interface{} { return &PersonTestStruct{} }, stdb.GetOptions{Fields: []string{"Name"}})
+	if len(xobj) != 3 || xobj[1].(*PersonTestStruct).PersonID != 5 || xobj[2].(*PersonTestStruct).Name != "Jim" {
+		t.Fatalf("Get failed to return objects sorted by PersonID with the primary key set")
+	}
+
+	xobj, _ = c.Get(func() interface{} { return &PersonTestStruct{} }, stdb.GetOptions{
+		Filters: map[string]interface{}{"ID": 5},
+	})
+	if len(xobj) != 1 || xobj[0].(*PersonTestStruct).Name != "Jane" {
+		t.Fatalf("Get failed to return object with ID filter on PersonID primary key")
+	}
+}
+
+// TestGet tests if Get returns objects filtered, ordered and limited
+func TestGet(t *testing.T) {
+	c := createFakeControllerWithData()
+
+	xobj, err := c.Get(newTestStruct, stdb.GetOptions{
+		Order:   []string{"Age", "desc", "FirstName", "asc"},
+		Limit:   3,
+		Offset:  1,
+		Filters: map[string]interface{}{"Price": 100},
+	})
+	if err != nil {
+		t.Fatalf("Get failed to return list of objects: %s", err.Op)
+	}
+	if len(xobj) != 3 {
+		t.Fatalf("Get failed to return list of objects, want %v, got %v", 3, len(xobj))
+	}
+	if xobj[0].(*TestStruct).FirstName != "Name05" || xobj[2].(*TestStruct).FirstName != "Name01" {
+		t.Fatalf("Get failed to return ordered list of objects")
+	}
+
+	cnt, err := c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"Age": 11},
+	})
+	if err != nil {
+		t.Fatalf("GetCount failed to return count: %s", err.Op)
+	}
+	if cnt != 4 {
+		t.Fatalf("GetCount failed to return count, want %v, got %v", 4, cnt)
+	}
+
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"Age": stdb.Gt(10), "FirstName": stdb.Ne("Name02")},
+	})
+	if cnt != 6 {
+		t.Fatalf("GetCount failed to apply filters with operators, want %v, got %v", 6, cnt)
+	}
+
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"FirstName": stdb.ILike("name0%")},
+	})
+	if cnt != 9 {
+		t.Fatalf("GetCount failed to apply filter with ILike, want %v, got %v", 9, cnt)
+	}
+
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"FirstName": stdb.IsNotNull(), "Age": 11},
+	})
+	if cnt != 4 {
+		t.Fatalf("GetCount failed to apply IsNotNull filter, want %v, got %v", 4, cnt)
+	}
+
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"FirstName": stdb.IsNull()},
+	})
+	if cnt != 0 {
+		t.Fatalf("GetCount failed to apply IsNull filter, want %v, got %v", 0, cnt)
+	}
+
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"Age": []int{10, 12}},
+	})
+	if cnt != 6 {
+		t.Fatalf("GetCount failed to apply filter with list of values, want %v, got %v", 6, cnt)
+	}
+
+	xobj, _ = c.Get(newTestStruct, stdb.GetOptions{
+		Filters: map[string]interface{}{"Price": 100},
+		FilterGroups: []stdb.FilterGroup{
+			{Conjunction: stdb.RawConjuctionOR, Filters: map[string]interface{}{"Age": 10, "FirstName": "Name01"}},
+		},
+	})
+	if len(xobj) != 4 {
+		t.Fatalf("Get failed to apply filter groups, want %v, got %v", 4, len(xobj))
+	}
+
+	xobj, _ = c.Get(newTestStruct, stdb.GetOptions{
+		OrderBy: []stdb.OrderBy{{Field: "Age", Desc: true}, {Field: "FirstName"}},
+		Limit:   2,
+	})
+	if len(xobj) != 2 || xobj[0].(*TestStruct).FirstName != "Name02" || xobj[1].(*TestStruct).FirstName != "Name05" {
+		t.Fatalf("Get failed to return objects ordered with OrderBy")
+	}
+
+	_, err = c.Get(newTestStruct, stdb.GetOptions{OrderBy: []stdb.OrderBy{{Field: "Missing"}}})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Get failed to return error for OrderBy field that does not exist")
+	}
+
+	xobj, _ = c.Get(newTestStruct, stdb.GetOptions{
+		Filters: map[string]interface{}{"FirstName": "Name01"},
+		Fields:  []string{"Age"},
+	})
+	if len(xobj) != 1 || xobj[0].(*TestStruct).ID != 1 || xobj[0].(*TestStruct).Age != 11 || xobj[0].(*TestStruct).FirstName != "" {
+		t.Fatalf("Get failed to return only selected fields")
+	}
+
+	_, err = c.Get(newTestStruct, stdb.GetOptions{Fields: []string{"Missing"}})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("Get failed to return error for a field that does not exist")
+	}
+
+	_, err = c.Get(newTestStruct, stdb.GetOptions{
+		Filters: map[string]interface{}{"_raw": []interface{}{".Age > ?", 10}},
+	})
+	if err == nil || err.Op != "UnsupportedFilter" {
+		t.Fatalf("Get failed to return error for unsupported filter")
+	}
+}
+
+// TestGetWithCursor tests if Get returns pages of objects after the cursor
+func TestGetWithCursor(t *testing.T) {
+	c := createFakeControllerWithData()
+
+	options := stdb.GetOptions{
+		Order: []string{"Age", "asc"},
+		Limit: 3,
+	}
+	names := []string{}
+	for i := 0; i < 5; i++ {
+		xobj, err := c.Get(newTestStruct, options)
+		if err != nil {
+			t.Fatalf("Get failed to return page of objects: %s", err.Op)
+		}
+		if len(xobj) == 0 {
+			break
+		}
+		for _, obj := range xobj {
+			names = append(names, obj.(*TestStruct).FirstName)
+		}
+		options.Cursor, err = c.EncodeCursor(xobj[len(xobj)-1], options)
+		if err != nil {
+			t.Fatalf("EncodeCursor failed to return cursor: %s", err.Op)
+		}
+	}
+	want := "Name03 Name06 Name09 Name01 Name04 Name07 Name10 Name02 Name05 Name08"
+	if fmt.Sprint(names) != "["+want+"]" {
+		t.Fatalf("Get failed to return pages of objects after cursor, want %v, got %v", want, names)
+	}
+
+	_, err := c.Get(newTestStruct, stdb.GetOptions{Order: []string{"Age", "asc"}, Cursor: "invalid"})
+	if err == nil || err.Op != "InvalidCursor" {
+		t.Fatalf("Get failed to return error for invalid cursor")
+	}
+
+	_, err = c.Get(newTestStruct, stdb.GetOptions{Order: []string{"Age", "desc"}, Cursor: options.Cursor})
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("Get failed to return error for cursor with order in different directions")
+	}
+}
+
+// TestGetWithDefaultOptionsAndMaxRows tests if Get uses default options, ignores empty filters and refuses to return
+// more than MaxRows objects, the same as Controller
+func TestGetWithDefaultOptionsAndMaxRows(t *testing.T) {
+	c := createFakeControllerWithData()
+
+	errCtl := c.RegisterDefaultGetOptions(&TestStruct{}, stdb.GetOptions{
+		Filters: map[string]interface{}{"Age": 11},
+		Order:   []string{"FirstName", "desc"},
+	})
+	if errCtl != nil {
+		t.Fatalf("RegisterDefaultGetOptions failed: %s", errCtl.Op)
+	}
+
+	xobj, err := c.Get(newTestStruct, stdb.GetOptions{
+		Filters:           map[string]interface{}{"FirstName": "", "Price": 0},
+		IgnoreZeroFilters: true,
+	})
+	if err != nil || len(xobj) != 4 || xobj[0].(*TestStruct).FirstName != "Name10" {
+		t.Fatalf("Get failed to use default options and ignore zero filters")
+	}
+
+	xobj, _ = c.Get(newTestStruct, stdb.GetOptions{Filters: map[string]interface{}{"Age": 12}})
+	if len(xobj) != 0 {
+		t.Fatalf("Get failed to AND-combine default filter with the one on the same field")
+	}
+
+	c2 := NewFakeController(&stdb.ControllerConfig{MaxRows: 5})
+	for i := 1; i < 7; i++ {
+		c2.Save(&TestStruct{FirstName: fmt.Sprintf("Name%02d", i), Age: 10}, stdb.SaveOptions{})
+	}
+	_, err = c2.Get(newTestStruct, stdb.GetOptions{})
+	if err == nil || err.Op != "TooManyRows" {
+		t.Fatalf("Get failed to return error when there are more rows than MaxRows")
+	}
+}
+
+// TimestampTestStruct has fields with time of creation and last update
+type TimestampTestStruct struct {
+	ID        int64
+	Name      string
+	CreatedAt time.Time `2db:"created_at"`
+	UpdatedAt time.Time `2db:"updated_at"`
+}
+
+// TestSaveWithTimestamps tests if Save sets 'created_at' field only on insert and 'updated_at' on every save
+func TestSaveWithTimestamps(t *testing.T) {
+	c := NewFakeController(nil)
+
+	ts := &TimestampTestStruct{Name: "First"}
+	c.Save(ts, stdb.SaveOptions{})
+	if ts.CreatedAt.IsZero() || !ts.UpdatedAt.Equal(ts.CreatedAt) {
+		t.Fatalf("Save failed to set timestamps of a new object")
+	}
+
+	createdAt := ts.CreatedAt
+	time.Sleep(time.Millisecond)
+	c.Save(&TimestampTestStruct{ID: ts.ID, Name: "Second"}, stdb.SaveOptions{})
+
+	ts2 := &TimestampTestStruct{}
+	c.Load(ts2, "1", stdb.LoadOptions{})
+	if !ts2.CreatedAt.Equal(createdAt) || !ts2.UpdatedAt.After(createdAt) {
+		t.Fatalf("Save failed to keep 'created_at' and update 'updated_at' of an existing object")
+	}
+}
+
+// TestUpdateMultipleAndDeleteMultiple tests if UpdateMultiple and DeleteMultiple modify objects that match filters
+func TestUpdateMultipleAndDeleteMultiple(t *testing.T) {
+	c := createFakeControllerWithData()
+
+	err := c.UpdateMultiple(&TestStruct{}, map[string]interface{}{"Price": 200}, stdb.UpdateMultipleOptions{
+		Filters: map[string]interface{}{"Age": 12},
+	})
+	if err != nil {
+		t.Fatalf("UpdateMultiple failed to update objects: %s", err.Op)
+	}
+
+	cnt, _ := c.GetCount(newTestStruct, stdb.GetCountOptions{
+		Filters: map[string]interface{}{"Price": 200},
+	})
+	if cnt != 3 {
+		t.Fatalf("UpdateMultiple updated invalid number of objects, want %v, got %v", 3, cnt)
+	}
+
+	err = c.DeleteMultiple(&TestStruct{}, stdb.DeleteMultipleOptions{})
+	if err == nil || err.Op != "UnsafeFullTable" {
+		t.Fatalf("DeleteMultiple failed to refuse removing all objects without filters")
+	}
+
+	err = c.DeleteMultiple(&TestStruct{}, stdb.DeleteMultipleOptions{
+		Filters: map[string]interface{}{"Price": 200},
+	})
+	if err != nil {
+		t.Fatalf("DeleteMultiple failed to delete objects: %s", err.Op)
+	}
+
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{})
+	if cnt != 7 {
+		t.Fatalf("DeleteMultiple removed invalid number of objects, there are %v left instead of %v", cnt, 7)
+	}
+
+	ts := &TestStruct{}
+	c.Load(ts, "1", stdb.LoadOptions{})
+	c.Delete(ts, stdb.DeleteOptions{})
+	if ts.ID != 0 {
+		t.Fatalf("Delete failed to set ID to 0 on the struct")
+	}
+	cnt, _ = c.GetCount(newTestStruct, stdb.GetCountOptions{})
+	if cnt != 6 {
+		t.Fatalf("Delete failed to remove object, there are %v left instead of %v", cnt, 6)
+	}
+}
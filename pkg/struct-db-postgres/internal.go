@@ -1,21 +1,29 @@
 package structdbpostgres
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"runtime"
+	"sort"
 	"strings"
+	"time"
 
 	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
 )
 
 // getSQLGenerator returns a special StructSQL instance which reflects the struct type to get SQL queries etc.
 func (c *Controller) getSQLGenerator(obj interface{}, generators map[string]*stsql.StructSQL, forceName string) (*stsql.StructSQL, *ErrController) {
-	n := c.getSQLGeneratorName(obj, false)
+	n := c.getSQLGeneratorKey(obj)
 	if c.sqlGenerators[n] == nil {
 		h := stsql.NewStructSQL(obj, stsql.StructSQLOptions{
 			DatabaseTablePrefix:          c.dbTblPrefix,
+			DatabaseTableSuffix:          c.dbTblSuffix,
 			TagName:                      c.tagName,
 			Joined:                       generators,
 			ForceName:                    forceName,
@@ -32,6 +40,16 @@ func (c *Controller) getSQLGenerator(obj interface{}, generators map[string]*sts
 	return c.sqlGenerators[n], nil
 }
 
+// getSQLGeneratorKey returns the key of struct's SQL generator in sqlGenerators. Controller with table suffix has
+// its own generators, and '#' cannot be a part of struct name so the keys do not collide
+func (c *Controller) getSQLGeneratorKey(obj interface{}) string {
+	n := c.getSQLGeneratorName(obj, false)
+	if c.dbTblSuffix != "" {
+		n += "#" + c.dbTblSuffix
+	}
+	return n
+}
+
 func (c *Controller) getSQLGeneratorName(obj interface{}, onlyRoot bool) string {
 	v := reflect.ValueOf(obj)
 	i := reflect.Indirect(v)
@@ -58,6 +76,796 @@ func (c *Controller) getSQLGeneratorName(obj interface{}, onlyRoot bool) string
 	return n
 }
 
+// getFieldKind returns kind of struct field. If field does not exist, reflect.Invalid is returned
+func (c Controller) getFieldKind(obj interface{}, fieldName string) reflect.Kind {
+	s := reflect.Indirect(reflect.ValueOf(obj)).Type()
+	if s.String() == "reflect.Value" {
+		s = reflect.ValueOf(obj.(reflect.Value).Interface()).Type().Elem().Elem()
+	}
+
+	f, ok := s.FieldByName(fieldName)
+	if !ok {
+		return reflect.Invalid
+	}
+	return f.Type.Kind()
+}
+
+// rewriteQuery returns query with the caller comment, when TagQueriesWithCaller is set, and changed by controller's
+// QueryRewriter, or the same query when none of them is set
+func (c Controller) rewriteQuery(op string, query string) string {
+	if c.tagQueriesWithCaller {
+		query = c.getCallerComment() + query
+	}
+	if c.queryRewriter == nil {
+		return query
+	}
+	return c.queryRewriter(op, query)
+}
+
+// pkgDir is the directory of this package, used to skip its own functions when looking for the caller
+var pkgDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// getCallerComment returns SQL comment with directory, file and line of the first caller outside of this package,
+// eg. '/* app/users.go:42 */ '
+func (c Controller) getCallerComment() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if frame.File != "" && (filepath.Dir(frame.File) != pkgDir || strings.HasSuffix(frame.File, "_test.go")) {
+			loc := fmt.Sprintf("%s/%s:%d", filepath.Base(filepath.Dir(frame.File)), filepath.Base(frame.File), frame.Line)
+			return "/* " + strings.ReplaceAll(loc, "*/", "") + " */ "
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// queryError wraps error of a query with the query and its arguments, so they can be added to ErrController
+type queryError struct {
+	query string
+	args  []interface{}
+	err   error
+}
+
+func (e *queryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+// newQueryError returns nil when err is nil, and err wrapped with the query otherwise
+func newQueryError(err error, query string, args []interface{}) error {
+	if err == nil {
+		return nil
+	}
+	return &queryError{query: query, args: args, err: err}
+}
+
+// dbRow is a result of queryRow that adds the query to Scan error, except sql.ErrNoRows which is returned as it is
+type dbRow struct {
+	row   *sql.Row
+	query string
+	args  []interface{}
+	// err is set when statement could not be prepared
+	err error
+}
+
+func (r *dbRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return newQueryError(r.err, r.query, r.args)
+	}
+	err := r.row.Scan(dest...)
+	if err == sql.ErrNoRows {
+		return err
+	}
+	return newQueryError(err, r.query, r.args)
+}
+
+// newErrDBQuery returns ErrController with DBQuery operation for a failed query, with the query and, when allowed,
+// its arguments
+func (c Controller) newErrDBQuery(err error) *ErrController {
+	errCtl := &ErrController{
+		Op:  "DBQuery",
+		Err: fmt.Errorf("Error executing DB query: %w", err),
+	}
+
+	var qErr *queryError
+	if errors.As(err, &qErr) {
+		errCtl.Query = qErr.query
+		if c.includeArgs {
+			errCtl.Args = c.redactArgs(qErr.args)
+		}
+	}
+	return errCtl
+}
+
+// exec executes a query, that does not return rows, on the database or in the transaction, after passing it to
+// QueryRewriter
+func (c Controller) exec(ctx context.Context, op string, query string, args ...interface{}) (sql.Result, error) {
+	query = c.rewriteQuery(op, query)
+	stmt, err := c.getStmt(ctx, query)
+	if err != nil {
+		return nil, newQueryError(err, query, args)
+	}
+	if stmt != nil {
+		res, err := stmt.ExecContext(ctx, args...)
+		return res, newQueryError(err, query, args)
+	}
+	if c.tx != nil {
+		res, err := c.tx.ExecContext(ctx, query, args...)
+		return res, newQueryError(err, query, args)
+	}
+	res, err := c.dbConn.ExecContext(ctx, query, args...)
+	return res, newQueryError(err, query, args)
+}
+
+// query executes a query, that returns rows, on the database or in the transaction, after passing it to
+// QueryRewriter
+func (c Controller) query(ctx context.Context, op string, query string, args ...interface{}) (*sql.Rows, error) {
+	query = c.rewriteQuery(op, query)
+	stmt, err := c.getStmt(ctx, query)
+	if err != nil {
+		return nil, newQueryError(err, query, args)
+	}
+	if stmt != nil {
+		rows, err := stmt.QueryContext(ctx, args...)
+		return rows, newQueryError(err, query, args)
+	}
+	if c.tx != nil {
+		rows, err := c.tx.QueryContext(ctx, query, args...)
+		return rows, newQueryError(err, query, args)
+	}
+	rows, err := c.dbConn.QueryContext(ctx, query, args...)
+	return rows, newQueryError(err, query, args)
+}
+
+// queryRow executes a query, that returns at most one row, on the database or in the transaction, after passing
+// it to QueryRewriter
+func (c Controller) queryRow(ctx context.Context, op string, query string, args ...interface{}) *dbRow {
+	query = c.rewriteQuery(op, query)
+	stmt, err := c.getStmt(ctx, query)
+	if err != nil {
+		return &dbRow{query: query, args: args, err: err}
+	}
+	if stmt != nil {
+		return &dbRow{row: stmt.QueryRowContext(ctx, args...), query: query, args: args}
+	}
+	if c.tx != nil {
+		return &dbRow{row: c.tx.QueryRowContext(ctx, query, args...), query: query, args: args}
+	}
+	return &dbRow{row: c.dbConn.QueryRowContext(ctx, query, args...), query: query, args: args}
+}
+
+// getStmt returns cached prepared statement for the query, bound to the transaction when there is one, or nil when
+// statement cache is off or full
+func (c Controller) getStmt(ctx context.Context, query string) (*sql.Stmt, error) {
+	if c.stmtCache == nil {
+		return nil, nil
+	}
+	stmt, err := c.stmtCache.get(ctx, c.dbConn, query)
+	if err != nil || stmt == nil {
+		return nil, err
+	}
+	// Statement bound to the transaction is closed when the transaction ends
+	if c.tx != nil {
+		return c.tx.StmtContext(ctx, stmt), nil
+	}
+	return stmt, nil
+}
+
+// beginWithStatementTimeout starts a transaction in which queries are aborted by the database after timeout
+func (c Controller) beginWithStatementTimeout(ctx context.Context, timeout time.Duration) (*sql.Tx, *ErrController) {
+	// SET LOCAL would stay until the end of the transaction and apply to all the following queries
+	if c.tx != nil {
+		return nil, &ErrController{
+			Op:  "DBBegin",
+			Err: fmt.Errorf("statement timeout is not supported in a transaction"),
+		}
+	}
+
+	tx, err := c.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, &ErrController{
+			Op:  "DBBegin",
+			Err: fmt.Errorf("Error starting DB transaction: %w", err),
+		}
+	}
+
+	// SET does not accept placeholders, value is an integer so it is safe to put it into the query
+	_, err = tx.ExecContext(ctx, fmt.Sprintf("SET LOCAL statement_timeout = %d", timeout.Milliseconds()))
+	if err != nil {
+		tx.Rollback()
+		return nil, &ErrController{
+			Op:  "DBQuery",
+			Err: fmt.Errorf("Error setting statement timeout: %w", err),
+		}
+	}
+	return tx, nil
+}
+
+// hydrateJoined sets joined structs (pointer fields with 'join' tag) in an object using values of its 'Joined_Field'
+// fields, and 'JoinedID' field as the primary key of joined struct, which is ID or its field with 'pk' tag
+func (c Controller) hydrateJoined(obj interface{}) {
+	v := reflect.ValueOf(obj).Elem()
+	s := v.Type()
+
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		if f.Type.Kind() != reflect.Ptr || f.Type.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		isJoined := false
+		for _, t := range strings.Split(f.Tag.Get(c.tagName), " ") {
+			if t == "join" {
+				isJoined = true
+			}
+		}
+		if !isJoined {
+			continue
+		}
+
+		joined := reflect.New(f.Type.Elem())
+		idField := v.FieldByName(f.Name + "ID")
+		joinedIDField := joined.Elem().FieldByName(c.getIDFieldName(joined.Interface()))
+		if idField.IsValid() && joinedIDField.IsValid() && idField.Type() == joinedIDField.Type() {
+			joinedIDField.Set(idField)
+		}
+
+		for j := 0; j < s.NumField(); j++ {
+			if !strings.HasPrefix(s.Field(j).Name, f.Name+"_") {
+				continue
+			}
+			joinedField := joined.Elem().FieldByName(strings.TrimPrefix(s.Field(j).Name, f.Name+"_"))
+			if joinedField.IsValid() && joinedField.Type() == s.Field(j).Type {
+				joinedField.Set(v.Field(j))
+			}
+		}
+
+		v.Field(i).Set(joined)
+	}
+}
+
+// removeEmptyFilters returns copy of filters without ones that have an empty string value, or zero value of any
+// type when zero is true. Special keys, such as '_raw', are always kept, and so are lists, because an empty one,
+// also nil, matches nothing
+func (c Controller) removeEmptyFilters(filters map[string]interface{}, empty bool, zero bool) map[string]interface{} {
+	if (!empty && !zero) || len(filters) == 0 {
+		return filters
+	}
+
+	f := make(map[string]interface{}, len(filters))
+	for k, v := range filters {
+		if !strings.HasPrefix(k, "_") && !stsql.IsFilterValueList(v) {
+			if str, ok := v.(string); ok && str == "" {
+				continue
+			}
+			if zero && (v == nil || reflect.ValueOf(v).IsZero()) {
+				continue
+			}
+		}
+		f[k] = v
+	}
+	return f
+}
+
+// getExampleFilters returns filters with values of all the non-zero fields of obj that have a column, and with
+// the 'filters' added on top
+func (c Controller) getExampleFilters(h *stsql.StructSQL, obj interface{}, filters map[string]interface{}) map[string]interface{} {
+	val := reflect.ValueOf(obj).Elem()
+
+	f := make(map[string]interface{}, len(filters))
+	for i := 0; i < val.NumField(); i++ {
+		fieldName := val.Type().Field(i).Name
+		valueField := val.Field(i)
+		if !stsql.IsFieldTypeSupported(valueField.Type()) || h.GetDBColFromFieldName(fieldName) == "" || valueField.IsZero() {
+			continue
+		}
+		f[fieldName] = valueField.Interface()
+	}
+
+	for k, v := range filters {
+		f[k] = v
+	}
+	return f
+}
+
+var reConstraintName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// reUUID matches UUID with or without hyphens, which PostgreSQL accepts in a UUID column
+var reUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{4}-?[0-9a-fA-F]{12}$`)
+
+// saveOnConflictConstraint inserts object or, when it conflicts on options.ConflictConstraint, updates the
+// existing row, and sets object's ID to the ID of the row
+func (c Controller) saveOnConflictConstraint(ctx context.Context, h *stsql.StructSQL, obj interface{}, options SaveOptions) (*SaveResult, *ErrController) {
+	if options.NoInsert || options.ForceInsertWithID || options.OnConflictDoNothing {
+		return nil, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("ConflictConstraint cannot be used with NoInsert, ForceInsertWithID or OnConflictDoNothing"),
+		}
+	}
+
+	if !reConstraintName.MatchString(options.ConflictConstraint) {
+		return nil, &ErrController{
+			Op:  "InvalidName",
+			Err: fmt.Errorf("invalid constraint name %s", options.ConflictConstraint),
+		}
+	}
+
+	if c.idGenerator != nil && !c.hasObjID(obj) {
+		err := c.setGeneratedID(obj)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	withID := c.hasObjID(obj)
+	query := h.GetQueryInsertOnConflictConstraintUpdateReturningInserted(options.ConflictConstraint, withID, options.UpdateColumns, options.ConflictUpdateWhere)
+	if query == "" {
+		return nil, &ErrController{
+			Op:  "InvalidField",
+			Err: fmt.Errorf("invalid update columns %v", options.UpdateColumns),
+		}
+	}
+
+	result := &SaveResult{}
+	err := c.queryRow(ctx, "Save", query, c.GetObjFieldInterfaces(obj, withID)...).Scan(c.getUpsertScanDests(h, obj, result)...)
+	// when update is skipped because of the condition, no row is returned
+	if err == sql.ErrNoRows && options.ConflictUpdateWhere != "" {
+		result.Skipped = true
+		err = nil
+	}
+	if err != nil {
+		return nil, c.newErrDBQuery(err)
+	}
+	return result, nil
+}
+
+// setTimestamps sets the 'updated_at' field of object to the current time, and the 'created_at' one as well when it
+// is zero. Time is truncated to microseconds, which is the precision of the database column
+func (c Controller) setTimestamps(h *stsql.StructSQL, obj interface{}) {
+	createdAt := h.GetCreatedAtFieldName()
+	updatedAt := h.GetUpdatedAtFieldName()
+	if createdAt == "" && updatedAt == "" {
+		return
+	}
+
+	now := reflect.ValueOf(time.Now().Truncate(time.Microsecond))
+	val := reflect.ValueOf(obj).Elem()
+	if createdAt != "" && val.FieldByName(createdAt).IsZero() {
+		val.FieldByName(createdAt).Set(now)
+	}
+	if updatedAt != "" {
+		val.FieldByName(updatedAt).Set(now)
+	}
+}
+
+// getUpsertScanDests returns destinations for the row returned by "upsert" queries, which contains ID, whether row
+// was inserted and value of the 'created_at' column, when struct has one
+func (c Controller) getUpsertScanDests(h *stsql.StructSQL, obj interface{}, result *SaveResult) []interface{} {
+	dests := []interface{}{c.GetObjIDInterface(obj), &result.Inserted}
+	if createdAt := h.GetCreatedAtFieldName(); createdAt != "" {
+		dests = append(dests, reflect.ValueOf(obj).Elem().FieldByName(createdAt).Addr().Interface())
+	}
+	return dests
+}
+
+// updateByIDWithoutCreatedAt updates all the columns of object's row apart from the 'created_at' one
+func (c Controller) updateByIDWithoutCreatedAt(ctx context.Context, h *stsql.StructSQL, obj interface{}) *ErrController {
+	val := reflect.ValueOf(obj).Elem()
+	values := map[string]interface{}{}
+	for i := 0; i < val.NumField(); i++ {
+		fieldName := val.Type().Field(i).Name
+		if fieldName == h.GetIDFieldName() || fieldName == h.GetCreatedAtFieldName() || h.GetDBColFromFieldName(fieldName) == "" || !stsql.IsStructFieldSupported(val.Type().Field(i), c.tagName) {
+			continue
+		}
+		if stsql.IsFieldJSON(val.Type().Field(i), c.tagName) {
+			values[fieldName] = c.getFieldInterface(val.Type().Field(i), val.Field(i))
+			continue
+		}
+		values[fieldName] = val.Field(i).Interface()
+	}
+	filters := map[string]interface{}{h.GetIDFieldName(): val.FieldByName(h.GetIDFieldName()).Interface()}
+
+	_, err := c.exec(ctx, "Save", h.GetQueryUpdate(values, filters, nil, nil), append(c.getFilterArgs(obj, values), c.getFilterArgs(obj, filters)...)...)
+	if err != nil {
+		return c.newErrDBQuery(err)
+	}
+	return nil
+}
+
+// insertOnConflictDoNothing inserts object unless it violates a unique constraint, in which case object's ID is set
+// to zero and the result has Skipped set
+func (c Controller) insertOnConflictDoNothing(ctx context.Context, h *stsql.StructSQL, obj interface{}, withID bool) (*SaveResult, *ErrController) {
+	result := &SaveResult{}
+	err := c.queryRow(ctx, "Save", h.GetQueryInsertOnConflictDoNothing(withID), c.GetObjFieldInterfaces(obj, withID)...).Scan(c.GetObjIDInterface(obj))
+	if err == sql.ErrNoRows {
+		idField := reflect.ValueOf(obj).Elem().FieldByName(h.GetIDFieldName())
+		idField.Set(reflect.Zero(idField.Type()))
+		result.Skipped = true
+		return result, nil
+	}
+	if err != nil {
+		return nil, c.newErrDBQuery(err)
+	}
+
+	result.Inserted = true
+	return result, nil
+}
+
+// insertBatches inserts objects in batches, for SaveMultiple, and syncs ID sequence after them when requested
+func (c Controller) insertBatches(ctx context.Context, h *stsql.StructSQL, objs []interface{}, batches [][2]int, withID bool, options SaveOptions) *ErrController {
+	for _, batch := range batches {
+		args := []interface{}{}
+		for _, obj := range objs[batch[0]:batch[1]] {
+			args = append(args, c.GetObjFieldInterfaces(obj, withID)...)
+		}
+
+		err := c.insertBatch(ctx, h.GetQueryInsertMultiple(batch[1]-batch[0], withID), args, objs[batch[0]:batch[1]])
+		if err != nil {
+			return err
+		}
+
+		if options.OnProgress != nil {
+			options.OnProgress(batch[1], len(objs))
+		}
+	}
+
+	if withID && options.SyncIDSequence {
+		_, err2 := c.exec(ctx, "SaveMultiple", h.GetQuerySyncIDSequence())
+		if err2 != nil {
+			return c.newErrDBQuery(err2)
+		}
+	}
+	return nil
+}
+
+// insertBatch runs a multi-row INSERT query and sets returned IDs in the objects, in the same order
+func (c Controller) insertBatch(ctx context.Context, query string, args []interface{}, objs []interface{}) *ErrController {
+	rows, err := c.query(ctx, "SaveMultiple", query, args...)
+	if err != nil {
+		return c.newErrDBQuery(err)
+	}
+	defer rows.Close()
+
+	i := 0
+	for rows.Next() && i < len(objs) {
+		err = rows.Scan(c.GetObjIDInterface(objs[i]))
+		if err != nil {
+			return &ErrController{
+				Op:  "DBQueryRowsScan",
+				Err: fmt.Errorf("Error scanning DB query row: %w", err),
+			}
+		}
+		i++
+	}
+
+	if err = rows.Err(); err != nil {
+		return c.newErrDBQuery(err)
+	}
+	return nil
+}
+
+// setGeneratedID sets object's ID field to a value from controller's ID generator
+func (c Controller) setGeneratedID(obj interface{}) *ErrController {
+	idField := reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj))
+	id := reflect.ValueOf(c.idGenerator.NewID())
+	if !id.IsValid() || !id.Type().ConvertibleTo(idField.Type()) {
+		return &ErrController{
+			Op:  "GenerateID",
+			Err: fmt.Errorf("generated ID cannot be converted to %s", idField.Type()),
+		}
+	}
+
+	idField.Set(id.Convert(idField.Type()))
+	return nil
+}
+
+// validateUpdateMultiple checks values and filters for UpdateMultiple and returns values converted from strings when
+// it is requested in options
+func (c Controller) validateUpdateMultiple(method string, obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) (map[string]interface{}, *ErrController) {
+	err := c.checkNotView(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options.Filters) == 0 && !options.AllowFullTableUpdate {
+		return nil, &ErrController{
+			Op:  "UnsafeFullTable",
+			Err: fmt.Errorf("refusing to update all rows without filters"),
+		}
+	}
+
+	if len(values) < 1 {
+		return nil, &ErrController{
+			Op:  "MissingValues",
+			Err: fmt.Errorf("missing values for update"),
+		}
+	}
+
+	if options.ConvertValuesFromString {
+		values = c.StringToFieldValues(obj, values)
+	}
+
+	if !options.SkipValidation {
+		b, invalidFields, err1 := c.Validate(obj, values)
+		if err1 != nil {
+			return nil, &ErrController{
+				Op:  "ValidateValues",
+				Err: fmt.Errorf("Error when trying to validate values: %w", err1),
+			}
+		}
+
+		if !b {
+			c.logValidationFailure(method, "ValidateValues", obj, invalidFields)
+			return nil, &ErrController{
+				Op: "ValidateValues",
+				Err: &ErrValidation{
+					Fields: invalidFields,
+				},
+			}
+		}
+	}
+
+	err = c.validateFilters(method, obj, options.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// validateValuesForID checks if values for an object with specific ID have exactly the same fields as others, and
+// if the values are of types convertible to the fields and valid
+func (c Controller) validateValuesForID(obj interface{}, objType reflect.Type, id int64, values map[string]interface{}, fields []string) *ErrController {
+	if len(values) != len(fields) {
+		return &ErrController{
+			Op:  "InvalidValue",
+			Err: fmt.Errorf("values for ID %d have different fields than others", id),
+		}
+	}
+
+	for _, f := range fields {
+		v, ok := values[f]
+		if !ok {
+			return &ErrController{
+				Op:  "InvalidValue",
+				Err: fmt.Errorf("values for ID %d are missing field %s", id, f),
+			}
+		}
+
+		field, ok := objType.FieldByName(f)
+		if !ok {
+			return &ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s does not exist", f),
+			}
+		}
+
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() || !rv.Type().ConvertibleTo(field.Type) || (rv.Kind() == reflect.String) != (field.Type.Kind() == reflect.String) {
+			return &ErrController{
+				Op:  "InvalidValue",
+				Err: fmt.Errorf("invalid value of field %s for ID %d", f, id),
+			}
+		}
+	}
+
+	b, invalidFields, err := c.Validate(obj, values)
+	if err != nil {
+		return &ErrController{
+			Op:  "ValidateValues",
+			Err: fmt.Errorf("Error when trying to validate values: %w", err),
+		}
+	}
+
+	if !b {
+		c.logValidationFailure("UpdateByIDValues", "ValidateValues", obj, invalidFields)
+		return &ErrController{
+			Op: "ValidateValues",
+			Err: &ErrValidation{
+				Fields: invalidFields,
+			},
+		}
+	}
+	return nil
+}
+
+// getOrder returns options.Order, or options.OrderBy turned into the same list of field and direction pairs
+func (c Controller) getOrder(h *stsql.StructSQL, options GetOptions) ([]string, *ErrController) {
+	if len(options.OrderBy) == 0 {
+		// Field at the end without direction is ascending
+		if len(options.Order)%2 == 1 {
+			return append(append([]string{}, options.Order...), "asc"), nil
+		}
+		return options.Order, nil
+	}
+	if len(options.Order) > 0 {
+		return nil, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("Order and OrderBy cannot be both set"),
+		}
+	}
+
+	order := make([]string, 0, len(options.OrderBy)*2)
+	for _, o := range options.OrderBy {
+		if h.GetDBColFromFieldName(o.Field) == "" {
+			return nil, &ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s does not exist", o.Field),
+			}
+		}
+		d := "asc"
+		if o.Desc {
+			d = "desc"
+		}
+		order = append(order, o.Field, d)
+	}
+	return order, nil
+}
+
+// validateDeleteMultiple checks if DeleteMultiple can run with the options, for both DeleteMultiple and
+// CountMatching, where method is the name of the calling one
+func (c Controller) validateDeleteMultiple(method string, obj interface{}, options DeleteMultipleOptions) *ErrController {
+	err := c.checkNotView(obj)
+	if err != nil {
+		return err
+	}
+
+	if len(options.Filters) == 0 && !options.AllowFullTableUpdate {
+		return &ErrController{
+			Op:  "UnsafeFullTable",
+			Err: fmt.Errorf("refusing to delete all rows without filters"),
+		}
+	}
+
+	// TODO: Enable validation once struct-validator support reflect.Value
+	if len(options.Filters) > 0 {
+		b, invalidFields, err1 := c.Validate(obj, options.Filters)
+		if err1 != nil {
+			return &ErrController{
+				Op:  "ValidateFilters",
+				Err: fmt.Errorf("Error when trying to validate filters: %w", err1),
+			}
+		}
+
+		if !b {
+			c.logValidationFailure(method, "ValidateFilters", obj, invalidFields)
+			return &ErrController{
+				Op: "ValidateFilters",
+				Err: &ErrValidation{
+					Fields: invalidFields,
+				},
+			}
+		}
+	}
+	return nil
+}
+
+// checkReferences checks if objects that foreign key fields refer to exist, where refs maps each field to a function
+// returning an instance of the referenced struct. Fields with zero value are skipped
+func (c Controller) checkReferences(ctx context.Context, obj interface{}, refs map[string]func() interface{}) *ErrController {
+	fieldNames := make([]string, 0, len(refs))
+	for fieldName := range refs {
+		fieldNames = append(fieldNames, fieldName)
+	}
+	sort.Strings(fieldNames)
+
+	v := reflect.ValueOf(obj).Elem()
+	invalidFields := map[string]int{}
+	for _, fieldName := range fieldNames {
+		f := v.FieldByName(fieldName)
+		if !f.IsValid() {
+			return &ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s does not exist", fieldName),
+			}
+		}
+		if f.IsZero() {
+			continue
+		}
+
+		h, err := c.getSQLGenerator(refs[fieldName](), nil, "")
+		if err != nil {
+			return err
+		}
+
+		var exists bool
+		err2 := c.queryRow(ctx, "Save", h.GetQueryExistsById(), f.Interface()).Scan(&exists)
+		if err2 != nil {
+			return c.newErrDBQuery(err2)
+		}
+		if !exists {
+			invalidFields[fieldName] = FailReference
+		}
+	}
+
+	if len(invalidFields) > 0 {
+		c.logValidationFailure("Save", "ValidateReferences", obj, invalidFields)
+		return &ErrController{
+			Op: "ValidateReferences",
+			Err: &ErrValidation{
+				Fields: invalidFields,
+			},
+		}
+	}
+	return nil
+}
+
+// logValidationFailure logs failed validation of object, filters or values when logger is configured
+func (c Controller) logValidationFailure(method string, op string, obj interface{}, fields map[string]int) {
+	if c.logger == nil {
+		return
+	}
+
+	c.logger.LogAttrs(context.Background(), slog.LevelWarn, "validation failed",
+		slog.String("model", stsql.GetStructName(obj)),
+		slog.String("method", method),
+		slog.String("op", op),
+		slog.Any("fields", fields),
+	)
+}
+
+// addOrderTiebreaker returns order with the primary key field, usually ID, added at the end, unless it is already
+// ordered by it
+func (c Controller) addOrderTiebreaker(order []string, idField string) []string {
+	for i := 0; i < len(order); i += 2 {
+		if order[i] == idField {
+			return order
+		}
+	}
+
+	o := make([]string, len(order), len(order)+2)
+	copy(o, order)
+	return append(o, idField, "asc")
+}
+
+// isNumericKind returns true when kind is an integer or a float
+func (c Controller) isNumericKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// validateFilters validates filters against object's fields and returns ErrController when they are invalid
+func (c Controller) validateFilters(method string, obj interface{}, filters map[string]interface{}) *ErrController {
+	if len(filters) == 0 {
+		return nil
+	}
+
+	b, invalidFields, err := c.Validate(obj, filters)
+	if err != nil {
+		return &ErrController{
+			Op:  "ValidateFilters",
+			Err: fmt.Errorf("Error when trying to validate filters: %w", err),
+		}
+	}
+
+	if !b {
+		c.logValidationFailure(method, "ValidateFilters", obj, invalidFields)
+		return &ErrController{
+			Op: "ValidateFilters",
+			Err: &ErrValidation{
+				Fields: invalidFields,
+			},
+		}
+	}
+
+	return nil
+}
+
 func (c Controller) mapWithInterfacesToMapBool(m map[string]interface{}) map[string]bool {
 	o := map[string]bool{}
 	for k := range m {
@@ -66,7 +874,92 @@ func (c Controller) mapWithInterfacesToMapBool(m map[string]interface{}) map[str
 	return o
 }
 
-func (c Controller) runOnDelete(obj interface{}, tagName string, ids []int64, lastDepth int) *ErrController {
+// deleteByID deletes row of the object and then its children with 'on_del' tag. Cascade works only with integer
+// primary keys
+func (c Controller) deleteByID(ctx context.Context, h *stsql.StructSQL, obj interface{}, options DeleteOptions) *ErrController {
+	_, err := c.exec(ctx, "Delete", h.GetQueryDeleteById(), c.GetObjIDInterface(obj))
+	if err != nil {
+		return c.newErrDBQuery(err)
+	}
+
+	id := c.GetObjIDValue(obj)
+	if id == 0 {
+		return nil
+	}
+	return c.runOnDelete(ctx, obj, c.tagName, []int64{id}, 0, options.CascadeDeleteBatchSize)
+}
+
+// hasCascadeDelete returns true when object has fields with children that are deleted or updated with it, which are
+// slices of pointers to structs with 'on_del' tag
+func (c Controller) hasCascadeDelete(obj interface{}) bool {
+	t := reflect.TypeOf(obj).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		ft := t.Field(i).Type
+		if ft.Kind() != reflect.Slice || ft.Elem().Kind() != reflect.Ptr || ft.Elem().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		for _, opt := range strings.Split(t.Field(i).Tag.Get(c.tagName), " ") {
+			if strings.HasPrefix(opt, "on_del:") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sortByDependencies returns objects ordered so that each struct comes after the ones it refers to. Objects
+// without dependencies between them, and the ones that refer to each other, keep their order
+func (c Controller) sortByDependencies(xobj []interface{}) []interface{} {
+	sorted := make([]interface{}, 0, len(xobj))
+	visited := map[int]bool{}
+
+	var visit func(i int)
+	visit = func(i int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+
+		deps := c.getDependencies(xobj[i])
+		for j, obj := range xobj {
+			if deps[c.getSQLGeneratorName(obj, false)] {
+				visit(j)
+			}
+		}
+		sorted = append(sorted, xobj[i])
+	}
+
+	for i := range xobj {
+		visit(i)
+	}
+	return sorted
+}
+
+// getDependencies returns names of structs that object refers to, with joined struct fields or fields named after
+// the struct with 'ID' suffix, eg. 'ProductKindID'
+func (c Controller) getDependencies(obj interface{}) map[string]bool {
+	deps := map[string]bool{}
+	t := reflect.TypeOf(obj).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct {
+			for _, opt := range strings.Split(f.Tag.Get(c.tagName), " ") {
+				if opt == "join" {
+					deps[f.Type.Elem().Name()] = true
+				}
+			}
+			continue
+		}
+		if len(f.Name) > 2 && strings.HasSuffix(f.Name, "ID") {
+			deps[strings.TrimSuffix(f.Name, "ID")] = true
+		}
+	}
+	delete(deps, t.Name())
+	return deps
+}
+
+func (c Controller) runOnDelete(ctx context.Context, obj interface{}, tagName string, ids []int64, lastDepth int, batchSize int) *ErrController {
+
 	v := reflect.ValueOf(obj)
 	i := reflect.Indirect(v)
 	s := i.Type()
@@ -114,67 +1007,89 @@ func (c Controller) runOnDelete(obj interface{}, tagName string, ids []int64, la
 			}
 		}
 
-		// Perform delete
-		if tagsMap["on_del"] == "del" {
-			if tagsMap["del_field"] != "" {
-				parentIDField = tagsMap["del_field"]
+		if tagsMap["del_field"] != "" {
+			parentIDField = tagsMap["del_field"]
+		}
+
+		if tagsMap["on_del"] == "upd" && tagsMap["del_upd_field"] == "" {
+			return &ErrController{
+				Op:  "CascadeDelete",
+				Err: errors.New("missing update field in tags"),
 			}
+		}
 
-			// Delete from children table where parent ID = id of deleted object
-			errCtl := c.DeleteMultiple(reflect.New(f.Type.Elem()), DeleteMultipleOptions{
-				Filters: map[string]interface{}{
-					"_raw": []interface{}{
-						fmt.Sprintf(".%s IN (?)", parentIDField),
-						ids,
-					},
-				},
-				CascadeDeleteDepth: lastDepth + 1,
-			})
+		// IDs are passed in batches so that the IN (...) list does not exceed the limit of query parameters
+		for _, batch := range c.getBatches(len(ids), 1, batchSize) {
+			errCtl := c.runOnDeleteForBatch(ctx, f, tagsMap, parentIDField, ids[batch[0]:batch[1]], lastDepth, batchSize)
 			if errCtl != nil {
-				return &ErrController{
-					Op:  "CascadeDelete",
-					Err: errors.New("Error from DeleteMultiple"),
-				}
+				return errCtl
 			}
 		}
+	}
 
-		// Perform update
-		if tagsMap["on_del"] == "upd" {
-			updField := tagsMap["del_upd_field"]
-			updValue := tagsMap["del_upd_val"]
-			if updField == "" {
-				return &ErrController{
-					Op:  "CascadeDelete",
-					Err: errors.New("missing update field in tags"),
-				}
-			}
+	return nil
+}
 
-			if tagsMap["del_field"] != "" {
-				parentIDField = tagsMap["del_field"]
-			}
-			// Update children table where parent ID = id of deleted object
-			errCtl := c.UpdateMultiple(reflect.New(f.Type.Elem()),
-				map[string]interface{}{
-					updField: updValue,
+func (c Controller) runOnDeleteForBatch(ctx context.Context, f reflect.StructField, tagsMap map[string]string, parentIDField string, ids []int64, lastDepth int, batchSize int) *ErrController {
+	// Perform delete
+	if tagsMap["on_del"] == "del" {
+		// Delete from children table where parent ID = id of deleted object
+		errCtl := c.DeleteMultipleContext(ctx, reflect.New(f.Type.Elem()), DeleteMultipleOptions{
+			Filters: map[string]interface{}{
+				"_raw": []interface{}{
+					fmt.Sprintf(".%s IN (?)", parentIDField),
+					ids,
 				},
-				UpdateMultipleOptions{
-					Filters: map[string]interface{}{
-						"_raw": []interface{}{
-							fmt.Sprintf(".%s IN (?)", parentIDField),
-							ids,
-						},
+			},
+			CascadeDeleteDepth:     lastDepth + 1,
+			CascadeDeleteBatchSize: batchSize,
+		})
+		if errCtl != nil {
+			return &ErrController{
+				Op:  "CascadeDelete",
+				Err: errors.New("Error from DeleteMultiple"),
+			}
+		}
+	}
+
+	// Perform update
+	if tagsMap["on_del"] == "upd" {
+		// Update children table where parent ID = id of deleted object
+		errCtl := c.UpdateMultipleContext(ctx, reflect.New(f.Type.Elem()),
+			map[string]interface{}{
+				tagsMap["del_upd_field"]: tagsMap["del_upd_val"],
+			},
+			UpdateMultipleOptions{
+				Filters: map[string]interface{}{
+					"_raw": []interface{}{
+						fmt.Sprintf(".%s IN (?)", parentIDField),
+						ids,
 					},
-					ConvertValuesFromString: true,
 				},
-			)
-			if errCtl != nil {
-				return &ErrController{
-					Op:  "CascadeDelete",
-					Err: errors.New("Error from UpdateMultiple"),
-				}
+				ConvertValuesFromString: true,
+			},
+		)
+		if errCtl != nil {
+			return &ErrController{
+				Op:  "CascadeDelete",
+				Err: errors.New("Error from UpdateMultiple"),
 			}
 		}
 	}
 
 	return nil
 }
+
+// getFiltersWithGroups returns a copy of filters with the groups under the '_groups' key, which is where the SQL
+// generator takes them from. Filters are returned as they are when there are no groups
+func (c Controller) getFiltersWithGroups(filters map[string]interface{}, groups []FilterGroup) map[string]interface{} {
+	if len(groups) == 0 {
+		return filters
+	}
+	f := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		f[k] = v
+	}
+	f["_groups"] = groups
+	return f
+}
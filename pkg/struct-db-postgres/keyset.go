@@ -0,0 +1,134 @@
+package structdbpostgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
+)
+
+// EncodeCursor returns a cursor for keyset pagination that is passed in GetOptions.Cursor to get objects coming
+// after obj, which is usually the last object of the current page. The cursor contains obj's values of all the
+// fields in options.Order or options.OrderBy, and of ID that Get adds to it unless DisableOrderTiebreaker is set,
+// so the same options must be used with the cursor. As ID is added in ascending order, it has to be put in Order
+// for descending one
+func (c Controller) EncodeCursor(obj interface{}, options GetOptions) (string, *ErrController) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return "", err
+	}
+
+	// Order has to be the same as the one Get uses, which can be registered as default
+	options = c.getOptionsWithDefaults(obj, options)
+
+	options.Order, err = c.getOrder(h, options)
+	if err != nil {
+		return "", err
+	}
+
+	fields, _, err := c.getKeysetFields(h, c.getCursorOrder(h, options))
+	if err != nil {
+		return "", err
+	}
+
+	v := reflect.ValueOf(obj).Elem()
+	values := make([]interface{}, 0, len(fields))
+	for _, f := range fields {
+		values = append(values, v.FieldByName(f).Interface())
+	}
+
+	b, err2 := json.Marshal(values)
+	if err2 != nil {
+		return "", &ErrController{
+			Op:  "InvalidCursor",
+			Err: fmt.Errorf("error encoding cursor: %w", err2),
+		}
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// getCursorOrder returns order with the ID tiebreaker added the same way as Get adds it to pages with Limit
+func (c Controller) getCursorOrder(h *stsql.StructSQL, options GetOptions) []string {
+	if options.DisableOrderTiebreaker {
+		return options.Order
+	}
+	return c.addOrderTiebreaker(options.Order, h.GetIDFieldName())
+}
+
+// getKeysetFields returns names of fields in the order, and whether they are ordered descending. All of them must
+// be ordered in the same direction as they are compared with a row value
+func (c Controller) getKeysetFields(h *stsql.StructSQL, order []string) ([]string, bool, *ErrController) {
+	if len(order) == 0 || len(order)%2 != 0 {
+		return nil, false, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("cursor requires order with pairs of field name and direction"),
+		}
+	}
+
+	fields := []string{}
+	desc := order[1] == "desc"
+	for i := 0; i < len(order); i += 2 {
+		if h.GetDBColFromFieldName(order[i]) == "" {
+			return nil, false, &ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s in order does not exist", order[i]),
+			}
+		}
+		if (order[i+1] == "desc") != desc {
+			return nil, false, &ErrController{
+				Op:  "InvalidOptions",
+				Err: fmt.Errorf("cursor requires all the fields in order, including ID, to have the same direction"),
+			}
+		}
+		fields = append(fields, order[i])
+	}
+	return fields, desc, nil
+}
+
+// getKeysetFilters returns a copy of filters with the keyset condition from the cursor, which values are decoded
+// into types of the ordered fields
+func (c Controller) getKeysetFilters(h *stsql.StructSQL, obj interface{}, filters map[string]interface{}, order []string, cursor string) (map[string]interface{}, *ErrController) {
+	fields, desc, err := c.getKeysetFields(h, order)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw []json.RawMessage
+	b, err2 := base64.RawURLEncoding.DecodeString(cursor)
+	if err2 == nil {
+		err2 = json.Unmarshal(b, &raw)
+	}
+	if err2 == nil && len(raw) != len(fields) {
+		err2 = fmt.Errorf("cursor has %d values and order has %d fields", len(raw), len(fields))
+	}
+	if err2 != nil {
+		return nil, &ErrController{
+			Op:  "InvalidCursor",
+			Err: fmt.Errorf("error decoding cursor: %w", err2),
+		}
+	}
+
+	t := reflect.TypeOf(obj).Elem()
+	values := make([]interface{}, 0, len(fields))
+	for i, f := range fields {
+		sf, _ := t.FieldByName(f)
+		val := reflect.New(sf.Type)
+		err2 = json.Unmarshal(raw[i], val.Interface())
+		if err2 != nil {
+			return nil, &ErrController{
+				Op:  "InvalidCursor",
+				Err: fmt.Errorf("error decoding value of %s from cursor: %w", f, err2),
+			}
+		}
+		values = append(values, val.Elem().Interface())
+	}
+
+	fc := make(map[string]interface{}, len(filters)+1)
+	for k, v := range filters {
+		fc[k] = v
+	}
+	fc["_keyset"] = stsql.Keyset{Fields: fields, Values: values, Desc: desc}
+	return fc, nil
+}
@@ -0,0 +1,88 @@
+package structdbpostgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
+// AdvisoryLock waits for PostgreSQL session advisory lock with specific key and returns a function that releases it.
+// Lock is held on a dedicated connection from the pool, which is returned to the pool when the lock is released.
+// Waiting can be cancelled with ctx
+func (c Controller) AdvisoryLock(ctx context.Context, key int64) (func() error, *ErrController) {
+	conn, err := c.getLockConn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	query := c.rewriteQuery("AdvisoryLock", "SELECT pg_advisory_lock($1)")
+	_, err2 := conn.ExecContext(ctx, query, key)
+	if err2 != nil {
+		err2 = newQueryError(err2, query, []interface{}{key})
+		conn.Close()
+		return nil, c.newErrDBQuery(err2)
+	}
+
+	return c.getUnlockFunc(conn, key), nil
+}
+
+// TryAdvisoryLock tries to get PostgreSQL session advisory lock with specific key without waiting. When lock is
+// acquired, it returns true and a function that releases it
+func (c Controller) TryAdvisoryLock(ctx context.Context, key int64) (func() error, bool, *ErrController) {
+	conn, err := c.getLockConn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	query := c.rewriteQuery("TryAdvisoryLock", "SELECT pg_try_advisory_lock($1)")
+	err2 := conn.QueryRowContext(ctx, query, key).Scan(&acquired)
+	if err2 != nil {
+		err2 = newQueryError(err2, query, []interface{}{key})
+		conn.Close()
+		return nil, false, c.newErrDBQuery(err2)
+	}
+
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	return c.getUnlockFunc(conn, key), true, nil
+}
+
+func (c Controller) getLockConn(ctx context.Context) (*sql.Conn, *ErrController) {
+	conn, err := c.dbConn.Conn(ctx)
+	if err != nil {
+		return nil, &ErrController{
+			Op:  "DBConn",
+			Err: fmt.Errorf("Error getting DB connection: %w", err),
+		}
+	}
+	return conn, nil
+}
+
+// getUnlockFunc returns a function that releases advisory lock and returns connection to the pool. It can be
+// called multiple times
+func (c Controller) getUnlockFunc(conn *sql.Conn, key int64) func() error {
+	released := false
+	return func() error {
+		if released {
+			return nil
+		}
+		released = true
+
+		// Lock is released even when context of the lock has been cancelled
+		_, err := conn.ExecContext(context.Background(), c.rewriteQuery("AdvisoryUnlock", "SELECT pg_advisory_unlock($1)"), key)
+		if err != nil {
+			// Closing the session releases locks as well, so the connection is marked as bad to be discarded
+			// instead of going back to the pool with the lock
+			conn.Raw(func(driverConn interface{}) error {
+				return driver.ErrBadConn
+			})
+			return fmt.Errorf("Error releasing advisory lock: %w", err)
+		}
+		return conn.Close()
+	}
+}
@@ -0,0 +1,46 @@
+package structdbpostgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestAdvisoryLock tests if advisory lock is acquired, blocks other sessions and is released
+func TestAdvisoryLock(t *testing.T) {
+	ctx := context.Background()
+
+	unlock, err := testController.AdvisoryLock(ctx, 12345)
+	if err != nil {
+		t.Fatalf("AdvisoryLock failed to acquire lock: %s", err.Op)
+	}
+
+	_, acquired, err := testController.TryAdvisoryLock(ctx, 12345)
+	if err != nil {
+		t.Fatalf("TryAdvisoryLock failed: %s", err.Op)
+	}
+	if acquired {
+		t.Fatalf("TryAdvisoryLock acquired lock that is held by another session")
+	}
+
+	// waiting for the lock stops when context is cancelled
+	ctxTimeout, cancel := context.WithTimeout(ctx, 200*time.Millisecond)
+	defer cancel()
+	_, err = testController.AdvisoryLock(ctxTimeout, 12345)
+	if err == nil {
+		t.Fatalf("AdvisoryLock failed to stop waiting for lock when context was cancelled")
+	}
+
+	if err2 := unlock(); err2 != nil {
+		t.Fatalf("AdvisoryLock failed to release lock: %s", err2.Error())
+	}
+	if err2 := unlock(); err2 != nil {
+		t.Fatalf("AdvisoryLock failed when lock was released twice: %s", err2.Error())
+	}
+
+	unlock2, acquired, err := testController.TryAdvisoryLock(ctx, 12345)
+	if err != nil || !acquired {
+		t.Fatalf("TryAdvisoryLock failed to acquire released lock")
+	}
+	unlock2()
+}
@@ -2,6 +2,7 @@ package structdbpostgres
 
 import (
 	"database/sql"
+	"log/slog"
 
 	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
 )
@@ -10,12 +11,63 @@ import (
 type Controller struct {
 	dbConn        *sql.DB
 	dbTblPrefix   string
+	dbTblSuffix   string
 	sqlGenerators map[string]*stsql.StructSQL
 	tagName       string
+	batchSize     int
+	views         map[string]string
+	maxRows       int
+	idGenerator   IDGenerator
+	queryRewriter func(op string, query string) string
+	logger        *slog.Logger
+	tx            *sql.Tx
+	includeArgs   bool
+	stmtCache     *stmtCache
+
+	tagQueriesWithCaller bool
+
+	validationMessages map[string]ValidationMessages
+	defaultGetOptions  map[string]GetOptions
+}
+
+// IDGenerator generates IDs for new objects when they are saved without an ID, instead of the database sequence.
+// Returned value must be convertible to the type of ID field
+type IDGenerator interface {
+	NewID() interface{}
 }
 
 type ControllerConfig struct {
 	TagName string
+	// BatchSize is the maximum number of items that bulk operations put into a single query, see DefaultBatchSize.
+	// Batches are made smaller when needed to stay within MaxQueryParameters
+	BatchSize int
+	// MaxRows is the maximum number of rows that Get returns. When query returns more, Get fails with 'TooManyRows'.
+	// Default 0 means no limit
+	MaxRows int
+	// IDGenerator, when set, is used to generate IDs of inserted objects. By default, database assigns them
+	IDGenerator IDGenerator
+	// QueryRewriter, when set, is called with name of the controller method and SQL query right before the query is
+	// executed, and the returned query is executed instead, eg. with a comment added. Query arguments cannot be
+	// changed. Returned query is not checked in any way, so it must not contain any input from outside
+	QueryRewriter func(op string, query string) string
+	// Logger, when set, gets a warning each time validation of object, values or filters fails, with the struct name
+	// as 'model', controller method as 'method', operation of returned error as 'op' and failed fields as 'fields'
+	Logger *slog.Logger
+	// IncludeQueryArgs makes errors of failed queries contain query arguments in Args. It is off by default as
+	// arguments may contain personal data or secrets that should not be logged
+	IncludeQueryArgs bool
+	// ValidationMessages are catalogs of validation messages keyed by locale, eg. 'pl' or 'pl-PL', that are used by
+	// ValidationErrorMessages. When there is no catalog for a locale, messages are in English
+	ValidationMessages map[string]ValidationMessages
+	// StatementCacheSize, when set, makes the controller prepare queries and keep up to this number of prepared
+	// statements, so that the database server does not parse the same query again each time. Statements are closed
+	// with ClearStatementCache
+	StatementCacheSize int
+	// TagQueriesWithCaller adds a comment with file and line of the code that called the controller, eg.
+	// '/* app/users.go:42 */', at the beginning of each query, so that slow queries in logs can be found in the code.
+	// It is off by default, as pg_stat_statements keeps comments, and the same query from different places is
+	// counted separately. It also makes the statement cache keep a statement for each place
+	TagQueriesWithCaller bool
 }
 
 // NewController returns new Controller object
@@ -33,6 +85,49 @@ func NewController(dbConn *sql.DB, tblPrefix string, cfg *ControllerConfig) *Con
 		c.tagName = "2db"
 	}
 
+	c.batchSize = DefaultBatchSize
+	if cfg != nil && cfg.BatchSize > 0 {
+		c.batchSize = cfg.BatchSize
+	}
+
+	if cfg != nil && cfg.MaxRows > 0 {
+		c.maxRows = cfg.MaxRows
+	}
+
+	if cfg != nil {
+		c.idGenerator = cfg.IDGenerator
+		c.queryRewriter = cfg.QueryRewriter
+		c.logger = cfg.Logger
+		c.includeArgs = cfg.IncludeQueryArgs
+		c.validationMessages = cfg.ValidationMessages
+		c.tagQueriesWithCaller = cfg.TagQueriesWithCaller
+	}
+
+	if cfg != nil && cfg.StatementCacheSize > 0 {
+		c.stmtCache = newStmtCache(cfg.StatementCacheSize)
+	}
+
 	c.sqlGenerators = make(map[string]*stsql.StructSQL)
+	c.views = make(map[string]string)
+	c.defaultGetOptions = make(map[string]GetOptions)
 	return c
 }
+
+// WithTableSuffix returns a copy of the controller that runs queries on tables with names ending with the suffix,
+// eg. 'persons_v2' for '_v2', such as a shadow table of a schema migration. Column names stay the same. The copy
+// shares SQL generators, statement cache and other settings with the controller
+func (c *Controller) WithTableSuffix(suffix string) *Controller {
+	c2 := *c
+	c2.dbTblSuffix = suffix
+	return &c2
+}
+
+// Close closes prepared statements cached by the controller and removes the SQL generators of its structs. It does
+// not close the database connection, which is owned by the caller. Controller must not be used after Close
+func (c *Controller) Close() error {
+	err := c.ClearStatementCache()
+	for n := range c.sqlGenerators {
+		delete(c.sqlGenerators, n)
+	}
+	return err
+}
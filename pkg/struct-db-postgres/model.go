@@ -1,45 +1,142 @@
 package structdbpostgres
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 
 	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
 )
 
-// GetObjIDInterface returns an interface{} to ID field of an object
+// GetObjIDInterface returns an interface{} to the primary key field of an object, which is ID unless another field
+// has the 'pk' tag
 func (c *Controller) GetObjIDInterface(obj interface{}) interface{} {
-	return reflect.ValueOf(obj).Elem().FieldByName("ID").Addr().Interface()
+	return reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj)).Addr().Interface()
 }
 
-// GetObjIDValue returns value of ID field (int64) of an object
+// GetObjIDValue returns value of the primary key field (int64) of an object. It returns 0 when the primary key
+// is a string
 func (c *Controller) GetObjIDValue(obj interface{}) int64 {
-	return reflect.ValueOf(obj).Elem().FieldByName("ID").Int()
+	idField := reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj))
+	if idField.Kind() == reflect.String {
+		return 0
+	}
+	return idField.Int()
+}
+
+// hasObjID returns true when the primary key field of an object is set, ie. it is not 0 or an empty string
+func (c *Controller) hasObjID(obj interface{}) bool {
+	return !reflect.ValueOf(obj).Elem().FieldByName(c.getIDFieldName(obj)).IsZero()
+}
+
+// getIDFieldName returns name of the primary key field, which is the string or integer field with 'pk' tag, the
+// same as in struct-sql-postgres, or ID when there is none
+func (c *Controller) getIDFieldName(obj interface{}) string {
+	t := reflect.TypeOf(obj).Elem()
+	for i := 0; i < t.NumField(); i++ {
+		k := t.Field(i).Type.Kind()
+		if k != reflect.String && k != reflect.Int64 && k != reflect.Int {
+			continue
+		}
+		for _, opt := range strings.Split(t.Field(i).Tag.Get(c.tagName), " ") {
+			if opt == "pk" {
+				return t.Field(i).Name
+			}
+		}
+	}
+	return "ID"
 }
 
 // GetObjFieldInterfaces return list of interfaces to object's fields
 // Argument includeID tells it to include or omit the ID field
 func (c Controller) GetObjFieldInterfaces(obj interface{}, includeID bool) []interface{} {
 	val := reflect.ValueOf(obj).Elem()
+	idField := c.getIDFieldName(obj)
 
 	var v []interface{}
 	for i := 0; i < val.NumField(); i++ {
 		valueField := val.Field(i)
-		if val.Type().Field(i).Name == "ID" && !includeID {
+		if val.Type().Field(i).Name == idField && !includeID {
 			continue
 		}
 		// struct-sql-postgres is used to generate SQL queries so here the same kinds must be supported
-		if !stsql.IsFieldKindSupported(valueField.Kind()) {
+		if !stsql.IsStructFieldSupported(val.Type().Field(i), c.tagName) {
 			continue
 		}
 
-		v = append(v, valueField.Addr().Interface())
+		v = append(v, c.getFieldInterface(val.Type().Field(i), valueField))
 	}
 	return v
 }
 
+// getObjFieldInterfacesForFields returns interfaces to object's fields that are in fieldNames, in the same order as they are
+// defined in the struct, like GetObjFieldInterfaces does
+func (c Controller) getObjFieldInterfacesForFields(obj interface{}, fieldNames map[string]bool) []interface{} {
+	val := reflect.ValueOf(obj).Elem()
+
+	var v []interface{}
+	for i := 0; i < val.NumField(); i++ {
+		if !fieldNames[val.Type().Field(i).Name] || !stsql.IsStructFieldSupported(val.Type().Field(i), c.tagName) {
+			continue
+		}
+		v = append(v, c.getFieldInterface(val.Type().Field(i), val.Field(i)))
+	}
+	return v
+}
+
+// getFieldInterface returns an interface to the field that is used both as a bind parameter and scan destination.
+// For a JSON field, it is a wrapper that marshals and unmarshals its value
+func (c Controller) getFieldInterface(sf reflect.StructField, valueField reflect.Value) interface{} {
+	if stsql.IsFieldJSON(sf, c.tagName) {
+		return jsonField{ptr: valueField.Addr().Interface()}
+	}
+	if c.isFieldRedacted(sf) {
+		return redactedField{ptr: valueField.Addr().Interface()}
+	}
+	return valueField.Addr().Interface()
+}
+
+// jsonField marshals a map or struct field, that is stored in a JSONB column, when it is passed as a bind
+// parameter, and unmarshals the column value into the field when it is scanned
+type jsonField struct {
+	ptr interface{}
+}
+
+func (j jsonField) Value() (driver.Value, error) {
+	b, err := json.Marshal(j.ptr)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (j jsonField) Scan(src interface{}) error {
+	// Unmarshaling into a map adds keys to the existing ones so the field is zeroed first
+	v := reflect.ValueOf(j.ptr).Elem()
+	v.Set(reflect.Zero(v.Type()))
+
+	switch b := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(b, j.ptr)
+	case string:
+		return json.Unmarshal([]byte(b), j.ptr)
+	default:
+		return fmt.Errorf("cannot scan %T into JSON field", src)
+	}
+}
+
 // GetFiltersInterfaces returns list of interfaces from filters map (used in querying)
 func (c Controller) GetFiltersInterfaces(mf map[string]interface{}) []interface{} {
+	return c.getFiltersInterfaces(mf, nil)
+}
+
+// getFiltersInterfaces does the same as GetFiltersInterfaces and marks values of the redacted fields
+func (c Controller) getFiltersInterfaces(mf map[string]interface{}, redacted map[string]bool) []interface{} {
 	var xi []interface{}
 
 	if len(mf) == 0 {
@@ -48,7 +145,7 @@ func (c Controller) GetFiltersInterfaces(mf map[string]interface{}) []interface{
 
 	sorted := []string{}
 	for k := range mf {
-		if k == "_raw" || k == "_rawConjuction" {
+		if k == "_raw" || k == "_rawConjuction" || k == "_groups" || k == "_keyset" {
 			continue
 		}
 		sorted = append(sorted, k)
@@ -56,7 +153,25 @@ func (c Controller) GetFiltersInterfaces(mf map[string]interface{}) []interface{
 	sort.Strings(sorted)
 
 	for _, v := range sorted {
-		xi = append(xi, mf[v])
+		xi = c.appendFilterValueRedacted(xi, mf[v], redacted[v])
+	}
+
+	// Filters of each group are sorted separately, the same way as they are in the query
+	if groups, ok := mf["_groups"].([]stsql.FilterGroup); ok {
+		for _, group := range groups {
+			groupSorted := []string{}
+			for k := range group.Filters {
+				groupSorted = append(groupSorted, k)
+			}
+			sort.Strings(groupSorted)
+			for _, v := range groupSorted {
+				xi = c.appendFilterValueRedacted(xi, group.Filters[v], redacted[v])
+			}
+		}
+	}
+
+	if keyset, ok := mf["_keyset"].(stsql.Keyset); ok {
+		xi = append(xi, keyset.Values...)
 	}
 
 	// Get pointers to values from raw query
@@ -181,6 +296,30 @@ func (c Controller) GetFiltersInterfaces(mf map[string]interface{}) []interface{
 	return xi
 }
 
+// appendFilterValue appends bind parameters of a filter value to xi
+func (c Controller) appendFilterValue(xi []interface{}, val interface{}) []interface{} {
+	// Raw expressions are put straight into the query, hence they are not bind parameters
+	if _, ok := val.(stsql.Raw); ok {
+		return xi
+	}
+	// Only value of a filter with operator is a bind parameter, and IsNull and IsNotNull have none
+	if f, ok := val.(stsql.Filter); ok {
+		if !f.HasValue() {
+			return xi
+		}
+		return append(xi, f.Value())
+	}
+	// Each item of a list is a separate bind parameter in 'IN (...)'
+	if stsql.IsFilterValueList(val) {
+		rv := reflect.ValueOf(val)
+		for j := 0; j < rv.Len(); j++ {
+			xi = append(xi, rv.Index(j).Interface())
+		}
+		return xi
+	}
+	return append(xi, val)
+}
+
 // ResetFields zeroes object's field values
 func (c Controller) ResetFields(obj interface{}) {
 	val := reflect.ValueOf(obj).Elem()
@@ -206,5 +345,112 @@ func (c Controller) ResetFields(obj interface{}) {
 		if k == reflect.Bool {
 			f.SetBool(false)
 		}
+		if k == reflect.Slice && f.Type().Elem().Kind() == reflect.Uint8 {
+			f.SetBytes(nil)
+		}
+		if k == reflect.Map || k == reflect.Struct {
+			f.Set(reflect.Zero(f.Type()))
+		}
+	}
+}
+
+// ObjectMapOptions are options for ObjectToMap and MapToObject
+type ObjectMapOptions struct {
+	// DBColumns makes map keys table columns instead of field names
+	DBColumns bool
+}
+
+// ObjectToMap returns map with values of object's fields that are stored in the database. Map keys are field
+// names, or table columns when DBColumns is set in options. Nullable (pointer) field has the value it points to,
+// or nil when it is NULL, so it can be told apart from a zero value
+func (c Controller) ObjectToMap(obj interface{}, options ObjectMapOptions) (map[string]interface{}, *ErrController) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	m := map[string]interface{}{}
+	val := reflect.ValueOf(obj).Elem()
+	for i := 0; i < val.NumField(); i++ {
+		fieldName := val.Type().Field(i).Name
+		col := h.GetDBColFromFieldName(fieldName)
+		if col == "" {
+			continue
+		}
+
+		var v interface{}
+		f := val.Field(i)
+		if !stsql.IsFieldNullable(f.Type()) {
+			v = f.Interface()
+		} else if !f.IsNil() {
+			v = f.Elem().Interface()
+		}
+
+		if options.DBColumns {
+			m[col] = v
+		} else {
+			m[fieldName] = v
+		}
+	}
+	return m, nil
+}
+
+// MapToObject sets object's fields with values from a map, where keys are field names, or table columns when
+// DBColumns is set in options. Values are converted to field types, and strings are parsed, the same way as in
+// StringToFieldValues. Nil value sets nullable (pointer) field to NULL. Keys that are not fields stored in the
+// database return an error
+func (c Controller) MapToObject(obj interface{}, values map[string]interface{}, options ObjectMapOptions) *ErrController {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return err
+	}
+
+	val := reflect.ValueOf(obj).Elem()
+	for k, v := range values {
+		fieldName := k
+		if options.DBColumns {
+			fieldName = h.GetFieldNameFromDBCol(k)
+		}
+		if fieldName == "" || h.GetDBColFromFieldName(fieldName) == "" {
+			return &ErrController{
+				Op:  "InvalidField",
+				Err: fmt.Errorf("field %s does not exist", k),
+			}
+		}
+
+		f := val.FieldByName(fieldName)
+		if str, ok := v.(string); ok && f.Kind() != reflect.String {
+			converted, ok := c.StringToFieldValues(obj, map[string]interface{}{fieldName: str})[fieldName]
+			if !ok {
+				return &ErrController{
+					Op:  "InvalidValue",
+					Err: fmt.Errorf("invalid value for field %s", k),
+				}
+			}
+			v = converted
+		}
+
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() {
+			f.Set(reflect.Zero(f.Type()))
+			continue
+		}
+		// Nullable field is set to a pointer to the converted value
+		target := f
+		nullable := stsql.IsFieldNullable(f.Type())
+		if nullable {
+			target = reflect.New(f.Type().Elem()).Elem()
+		}
+		if !rv.Type().ConvertibleTo(target.Type()) || (rv.Kind() == reflect.String) != (target.Kind() == reflect.String) {
+			return &ErrController{
+				Op:  "InvalidValue",
+				Err: fmt.Errorf("invalid value for field %s", k),
+			}
+		}
+		target.Set(rv.Convert(target.Type()))
+		if nullable {
+			f.Set(target.Addr())
+		}
 	}
+	return nil
 }
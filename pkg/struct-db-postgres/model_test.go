@@ -1,6 +1,7 @@
 package structdbpostgres
 
 import (
+	"fmt"
 	"log"
 	"testing"
 )
@@ -34,3 +35,102 @@ func TestObjFieldInterfaces(t *testing.T) {
 func TestResetFields(t *testing.T) {
 	// TODO
 }
+
+// Test struct for ObjectToMap and MapToObject
+type MapTestStruct struct {
+	ID       int64
+	Name     string
+	Age      int
+	Active   bool
+	Children []*MapTestStruct
+	Meta     map[string]string
+}
+
+// TestObjectToMap tests if ObjectToMap returns values of fields stored in the database
+func TestObjectToMap(t *testing.T) {
+	ts := &MapTestStruct{ID: 12, Name: "John", Age: 40, Active: true, Meta: map[string]string{"a": "b"}}
+
+	m, err := testController.ObjectToMap(ts, ObjectMapOptions{})
+	if err != nil {
+		t.Fatalf("ObjectToMap failed: %s", err.Op)
+	}
+	if len(m) != 4 || m["ID"].(int64) != 12 || m["Name"].(string) != "John" || m["Age"].(int) != 40 || !m["Active"].(bool) {
+		t.Fatalf("ObjectToMap returned invalid map: %v", m)
+	}
+	if _, ok := m["Children"]; ok {
+		t.Fatalf("ObjectToMap failed to exclude field that is not stored in the database")
+	}
+
+	m, _ = testController.ObjectToMap(ts, ObjectMapOptions{DBColumns: true})
+	if m["map_test_struct_id"].(int64) != 12 || m["name"].(string) != "John" {
+		t.Fatalf("ObjectToMap returned invalid map with table columns: %v", m)
+	}
+}
+
+// TestMapToObject tests if MapToObject sets object fields with converted values
+func TestMapToObject(t *testing.T) {
+	ts := &MapTestStruct{}
+
+	err := testController.MapToObject(ts, map[string]interface{}{
+		"ID":     float64(12),
+		"Name":   "John",
+		"Age":    "40",
+		"Active": "true",
+	}, ObjectMapOptions{})
+	if err != nil {
+		t.Fatalf("MapToObject failed: %s", err.Op)
+	}
+	if ts.ID != 12 || ts.Name != "John" || ts.Age != 40 || !ts.Active {
+		t.Fatalf("MapToObject failed to set fields: %v", ts)
+	}
+
+	err = testController.MapToObject(ts, map[string]interface{}{"age": 41}, ObjectMapOptions{DBColumns: true})
+	if err != nil || ts.Age != 41 {
+		t.Fatalf("MapToObject failed to set fields using table columns")
+	}
+
+	err = testController.MapToObject(ts, map[string]interface{}{"Children": nil}, ObjectMapOptions{})
+	if err == nil || err.Op != "InvalidField" {
+		t.Fatalf("MapToObject failed to return error for field that is not stored in the database")
+	}
+
+	err = testController.MapToObject(ts, map[string]interface{}{"Age": "abc"}, ObjectMapOptions{})
+	if err == nil || err.Op != "InvalidValue" {
+		t.Fatalf("MapToObject failed to return error for invalid value")
+	}
+
+	err = testController.MapToObject(ts, map[string]interface{}{"Name": 5}, ObjectMapOptions{})
+	if err == nil || err.Op != "InvalidValue" {
+		t.Fatalf("MapToObject failed to return error for value of invalid type")
+	}
+}
+
+// TestObjectToMapWithNullableFields tests if NULL columns are nil in the map, and zero values are kept
+func TestObjectToMapWithNullableFields(t *testing.T) {
+	testController.DropTable(&NullableTestStruct{})
+	testController.CreateTable(&NullableTestStruct{})
+
+	age := int64(0)
+	ns := &NullableTestStruct{Age: &age}
+	testController.Save(ns, SaveOptions{})
+
+	ns2 := &NullableTestStruct{}
+	testController.Load(ns2, fmt.Sprintf("%d", ns.ID), LoadOptions{})
+	m, err := testController.ObjectToMap(ns2, ObjectMapOptions{DBColumns: true})
+	if err != nil {
+		t.Fatalf("ObjectToMap failed: %s", err.Op)
+	}
+	if m["name"] != "" || m["age"] != int64(0) || m["nickname"] != nil || m["born_at"] != nil {
+		t.Fatalf("ObjectToMap failed to return nil for NULL and zero for zero values: %v", m)
+	}
+
+	err = testController.MapToObject(ns2, map[string]interface{}{"Nickname": "Johnny", "Age": nil}, ObjectMapOptions{})
+	if err != nil || ns2.Nickname == nil || *ns2.Nickname != "Johnny" || ns2.Age != nil {
+		t.Fatalf("MapToObject failed to set nullable fields")
+	}
+
+	err = testController.MapToObject(ns2, map[string]interface{}{"Age": "41"}, ObjectMapOptions{})
+	if err != nil || ns2.Age == nil || *ns2.Age != 41 {
+		t.Fatalf("MapToObject failed to set nullable field from string")
+	}
+}
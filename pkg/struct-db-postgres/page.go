@@ -0,0 +1,59 @@
+package structdbpostgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// GetPage runs the same query as Get for one page of objects, with Limit and Offset set from page, which starts
+// at 1, and perPage, and returns them with the total number of objects matching the same filters, eg. to show
+// the number of pages. Options are used the same way as in Get, but Cursor cannot be set
+func (c Controller) GetPage(newObjFunc func() interface{}, page int, perPage int, options GetOptions) ([]interface{}, int64, *ErrController) {
+	return c.GetPageContext(context.Background(), newObjFunc, page, perPage, options)
+}
+
+// GetPageContext does the same as GetPage but it runs queries with the context
+func (c Controller) GetPageContext(ctx context.Context, newObjFunc func() interface{}, page int, perPage int, options GetOptions) ([]interface{}, int64, *ErrController) {
+	if page < 1 || perPage < 1 {
+		return nil, 0, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("page and perPage must be at least 1"),
+		}
+	}
+	if options.Cursor != "" {
+		return nil, 0, &ErrController{
+			Op:  "InvalidOptions",
+			Err: fmt.Errorf("cursor cannot be used with page"),
+		}
+	}
+
+	options.Limit = perPage
+	options.Offset = (page - 1) * perPage
+
+	// Get validates the filters, so the count does not have to
+	xobj, err := c.GetContext(ctx, newObjFunc, options)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	obj := newObjFunc()
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return nil, 0, err
+	}
+
+	options = c.getOptionsWithDefaults(obj, options)
+	filters := c.removeEmptyFilters(options.Filters, options.IgnoreEmptyFilters, options.IgnoreZeroFilters)
+	filters = c.getFiltersWithGroups(filters, options.FilterGroups)
+
+	var total int64
+	err2 := c.queryRow(ctx, "GetPage", h.GetQuerySelectCount(filters, nil), c.getFilterArgs(obj, filters)...).Scan(&total)
+	if err2 != nil {
+		return nil, 0, &ErrController{
+			Op:  "DBQueryRowScan",
+			Err: fmt.Errorf("Error scanning DB query row: %w", err2),
+		}
+	}
+
+	return xobj, total, nil
+}
@@ -0,0 +1,140 @@
+package structdbpostgres
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// RedactedValue replaces values of fields with 'redact' tag in Args of ErrController
+const RedactedValue = "***"
+
+// isFieldRedacted checks if struct field has 'redact' in its tag, eg. '2db:"redact"'. Only string and []byte fields,
+// and pointers to string, can be redacted, as passwords or tokens are
+func (c Controller) isFieldRedacted(sf reflect.StructField) bool {
+	t := sf.Type
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.String {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.String && (t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uint8) {
+		return false
+	}
+	for _, opt := range strings.Split(sf.Tag.Get(c.tagName), " ") {
+		if opt == "redact" {
+			return true
+		}
+	}
+	return false
+}
+
+// getRedactedFields returns names of object's fields with 'redact' tag
+func (c Controller) getRedactedFields(obj interface{}) map[string]bool {
+	t := reflect.Indirect(reflect.ValueOf(obj)).Type()
+	if t.String() == "reflect.Value" {
+		t = reflect.ValueOf(obj.(reflect.Value).Interface()).Type().Elem().Elem()
+	}
+
+	var fields map[string]bool
+	for i := 0; i < t.NumField(); i++ {
+		if !c.isFieldRedacted(t.Field(i)) {
+			continue
+		}
+		if fields == nil {
+			fields = map[string]bool{}
+		}
+		fields[t.Field(i).Name] = true
+	}
+	return fields
+}
+
+// getFilterArgs returns bind parameters of filters, or values, the same as GetFiltersInterfaces does, but the ones of
+// redacted fields are marked so that they do not get into Args of ErrController. Values in '_raw' are not marked
+func (c Controller) getFilterArgs(obj interface{}, mf map[string]interface{}) []interface{} {
+	redacted := c.getRedactedFields(obj)
+	// Filter named ID can be for the primary key field that has a different name
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err == nil && redacted[h.GetFilterFieldName("ID")] {
+		redacted["ID"] = true
+	}
+	return c.getFiltersInterfaces(mf, redacted)
+}
+
+// appendFilterValueRedacted appends bind parameters of a filter value, like appendFilterValue, and marks them when
+// the field is redacted
+func (c Controller) appendFilterValueRedacted(xi []interface{}, v interface{}, redact bool) []interface{} {
+	n := len(xi)
+	xi = c.appendFilterValue(xi, v)
+	if redact {
+		for i := n; i < len(xi); i++ {
+			xi[i] = redactedArg{v: xi[i]}
+		}
+	}
+	return xi
+}
+
+// redactArgs returns a copy of query arguments with the redacted ones replaced with RedactedValue
+func (c Controller) redactArgs(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+	r := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg.(type) {
+		case redactedArg, redactedField:
+			r[i] = RedactedValue
+		default:
+			r[i] = arg
+		}
+	}
+	return r
+}
+
+// redactedArg is a bind parameter of a redacted field that is passed to the database as it is
+type redactedArg struct {
+	v interface{}
+}
+
+func (r redactedArg) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(r.v)
+}
+
+// redactedField is used instead of a pointer to a redacted field, both as a bind parameter and scan destination
+type redactedField struct {
+	ptr interface{}
+}
+
+func (r redactedField) Value() (driver.Value, error) {
+	return driver.DefaultParameterConverter.ConvertValue(r.ptr)
+}
+
+func (r redactedField) Scan(src interface{}) error {
+	v := reflect.ValueOf(r.ptr).Elem()
+	if src == nil {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	// Pointer field is set to a new string
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+
+	switch s := src.(type) {
+	case string:
+		if v.Kind() == reflect.String {
+			v.SetString(s)
+		} else {
+			v.SetBytes([]byte(s))
+		}
+	case []byte:
+		if v.Kind() == reflect.String {
+			v.SetString(string(s))
+		} else {
+			v.SetBytes(append([]byte(nil), s...))
+		}
+	default:
+		return fmt.Errorf("cannot scan %T into redacted field", src)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package structdbpostgres
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// Test struct with a redacted field
+type RedactTestStruct struct {
+	ID    int64
+	Name  string
+	Token string `2db:"uniq redact"`
+}
+
+// TestRedactedFieldsInErrors tests if values of fields with 'redact' tag are not included in errors and logs
+func TestRedactedFieldsInErrors(t *testing.T) {
+	var buf bytes.Buffer
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{
+		IncludeQueryArgs: true,
+		Logger:           slog.New(slog.NewJSONHandler(&buf, nil)),
+	})
+	c.DropTable(&RedactTestStruct{})
+	c.CreateTable(&RedactTestStruct{})
+
+	rs := &RedactTestStruct{Name: "First", Token: "secret-token"}
+	err := c.Save(rs, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert struct with redacted field: %s", err.Op)
+	}
+
+	rs2 := &RedactTestStruct{}
+	c.Load(rs2, fmt.Sprintf("%d", rs.ID), LoadOptions{})
+	if rs2.Token != "secret-token" {
+		t.Fatalf("Load failed to get value of redacted field")
+	}
+
+	// duplicate token violates unique constraint
+	err = c.Save(&RedactTestStruct{Name: "First", Token: "secret-token"}, SaveOptions{})
+	if err == nil || err.Op != "DBQuery" {
+		t.Fatalf("Save failed to return error for duplicate value of redacted field")
+	}
+	args := fmt.Sprintf("%v", err.Args)
+	if strings.Contains(args, "secret-token") || !strings.Contains(args, RedactedValue) {
+		t.Fatalf("Save returned error with value of redacted field: %s", args)
+	}
+
+	xobj, err := c.Get(func() interface{} { return &RedactTestStruct{} }, GetOptions{
+		Filters: map[string]interface{}{"Token": "secret-token"},
+	})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("Get failed to filter by redacted field")
+	}
+
+	c.DropTable(&RedactTestStruct{})
+	_, err = c.Get(func() interface{} { return &RedactTestStruct{} }, GetOptions{
+		Filters: map[string]interface{}{"Token": "secret-token"},
+	})
+	if err == nil || len(err.Args) != 1 || err.Args[0] != RedactedValue {
+		t.Fatalf("Get returned error with value of redacted filter")
+	}
+
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Fatalf("Value of redacted field was logged: %s", buf.String())
+	}
+}
@@ -0,0 +1,65 @@
+package structdbpostgres
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Seed inserts rows, which are objects of the same type as model, eg. for a development database, and returns how
+// many of them were inserted. Row that violates a unique constraint, eg. on a natural key or ID, is skipped, so
+// Seed can be run many times. Rows with ID are inserted with that ID, and then ID sequence is set to the highest ID
+// in the table. Everything is done in a single transaction, so on error nothing is inserted
+func (c Controller) Seed(model interface{}, rows []interface{}) (int64, *ErrController) {
+	t := reflect.TypeOf(model)
+	for i, row := range rows {
+		if reflect.TypeOf(row) != t {
+			return 0, &ErrController{
+				Op:  "InvalidType",
+				Err: fmt.Errorf("row %d is %s and not %s", i, reflect.TypeOf(row), t),
+			}
+		}
+	}
+
+	h, err := c.getSQLGenerator(model, nil, "")
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := c.Begin()
+	if err != nil {
+		return 0, err
+	}
+
+	var cnt int64
+	withID := false
+	for _, row := range rows {
+		hasID := c.hasObjID(row)
+		res, err := tx.c.SaveWithResult(row, SaveOptions{
+			ForceInsertWithID:   hasID,
+			OnConflictDoNothing: true,
+		})
+		if err != nil {
+			tx.Rollback()
+			return 0, err
+		}
+		if res.Inserted {
+			cnt++
+			withID = withID || hasID
+		}
+	}
+
+	if withID {
+		_, err2 := tx.c.exec(context.Background(), "Seed", h.GetQuerySyncIDSequence())
+		if err2 != nil {
+			tx.Rollback()
+			return 0, c.newErrDBQuery(err2)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
@@ -0,0 +1,58 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+func getSeedRows() []interface{} {
+	rows := []interface{}{}
+	for i := 1; i < 4; i++ {
+		ts := getTestStructWithData()
+		ts.ID = 0
+		ts.Key = fmt.Sprintf("seed-key-000000000000000000000%d", i)
+		rows = append(rows, ts)
+	}
+	ts := getTestStructWithData()
+	ts.ID = 100
+	ts.Key = "seed-key-0000000000000000000100"
+	return append(rows, ts)
+}
+
+// TestSeed tests if Seed inserts rows only once, and sets ID sequence after rows with ID
+func TestSeed(t *testing.T) {
+	recreateTestStructTable()
+
+	cnt, err := testController.Seed(&TestStruct{}, getSeedRows())
+	if err != nil {
+		t.Fatalf("Seed failed to insert rows: %s", err.Op)
+	}
+	if cnt != 4 {
+		t.Fatalf("Seed returned invalid number of inserted rows, want %d, got %d", 4, cnt)
+	}
+
+	cnt, err = testController.Seed(&TestStruct{}, getSeedRows())
+	if err != nil {
+		t.Fatalf("Seed failed when run again: %s", err.Op)
+	}
+	if cnt != 0 {
+		t.Fatalf("Seed inserted rows that already exist, got %d", cnt)
+	}
+
+	cnt, _ = testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 4 {
+		t.Fatalf("Seed inserted invalid number of rows, want %d, got %d", 4, cnt)
+	}
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	err = testController.Save(ts, SaveOptions{})
+	if err != nil || ts.ID <= 100 {
+		t.Fatalf("Seed failed to set ID sequence after rows with ID")
+	}
+
+	_, err = testController.Seed(&TestStruct{}, []interface{}{&ProductKind{}})
+	if err == nil || err.Op != "InvalidType" {
+		t.Fatalf("Seed failed to return error for row of another type")
+	}
+}
@@ -0,0 +1,74 @@
+package structdbpostgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// stmtCache keeps prepared statements keyed by query. When it is full, queries are run without being prepared, as
+// queries with different number of items in a list filter, eg. 'IN ($1,$2)', are all different
+type stmtCache struct {
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
+	size  int
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		stmts: make(map[string]*sql.Stmt),
+		size:  size,
+	}
+}
+
+// get returns prepared statement for the query, and prepares it when it is not in the cache yet. It returns nil
+// when the cache is full
+func (s *stmtCache) get(ctx context.Context, dbConn *sql.DB, query string) (*sql.Stmt, error) {
+	s.mu.Lock()
+	stmt, ok := s.stmts[query]
+	full := len(s.stmts) >= s.size
+	s.mu.Unlock()
+	if ok || full {
+		return stmt, nil
+	}
+
+	stmt, err := dbConn.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// Another goroutine might have prepared the same query in the meantime
+	if cached, ok := s.stmts[query]; ok {
+		stmt.Close()
+		return cached, nil
+	}
+	s.stmts[query] = stmt
+	return stmt, nil
+}
+
+// clear closes all the prepared statements and removes them from the cache
+func (s *stmtCache) clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for query, stmt := range s.stmts {
+		if err := stmt.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		delete(s.stmts, query)
+	}
+	return errors.Join(errs...)
+}
+
+// ClearStatementCache closes prepared statements cached when StatementCacheSize is set in ControllerConfig. They are
+// prepared again when queries are run
+func (c Controller) ClearStatementCache() error {
+	if c.stmtCache == nil {
+		return nil
+	}
+	return c.stmtCache.clear()
+}
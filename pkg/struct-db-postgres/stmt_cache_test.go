@@ -0,0 +1,83 @@
+package structdbpostgres
+
+import (
+	"fmt"
+	"testing"
+)
+
+// TestStatementCache tests if controller reuses prepared statements, up to the cache size, also in a transaction,
+// and closes them when the cache is cleared
+func TestStatementCache(t *testing.T) {
+	recreateTestStructTable()
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{StatementCacheSize: 3})
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	err := c.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed with statement cache: %s", err.Op)
+	}
+
+	for i := 0; i < 3; i++ {
+		ts2 := &TestStruct{}
+		err = c.Load(ts2, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+		if err != nil || ts2.ID != ts.ID {
+			t.Fatalf("Load failed with statement cache")
+		}
+	}
+	if len(c.stmtCache.stmts) != 2 {
+		t.Fatalf("Statement cache has invalid number of statements, want %d, got %d", 2, len(c.stmtCache.stmts))
+	}
+
+	for i := 1; i < 6; i++ {
+		_, err = c.Get(func() interface{} { return &TestStruct{} }, GetOptions{Limit: i})
+		if err != nil {
+			t.Fatalf("Get failed with statement cache: %s", err.Op)
+		}
+	}
+	if len(c.stmtCache.stmts) != 3 {
+		t.Fatalf("Statement cache exceeded its size, got %d statements", len(c.stmtCache.stmts))
+	}
+
+	tx, _ := c.Begin()
+	ts.Age = 50
+	err = tx.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed with statement cache in transaction: %s", err.Op)
+	}
+	tx.Rollback()
+
+	err2 := c.ClearStatementCache()
+	if err2 != nil || len(c.stmtCache.stmts) != 0 {
+		t.Fatalf("ClearStatementCache failed to remove statements")
+	}
+
+	ts3 := &TestStruct{}
+	c.Load(ts3, fmt.Sprintf("%d", ts.ID), LoadOptions{})
+	if ts3.Age != 37 {
+		t.Fatalf("Load failed after statement cache was cleared")
+	}
+}
+
+// TestClose tests if Close releases cached statements and generators, and leaves the database connection open
+func TestClose(t *testing.T) {
+	recreateTestStructTable()
+
+	c := NewController(dbConn, "struct2db_", &ControllerConfig{StatementCacheSize: 10})
+	c.Get(func() interface{} { return &TestStruct{} }, GetOptions{})
+	if len(c.stmtCache.stmts) == 0 || len(c.sqlGenerators) == 0 {
+		t.Fatalf("Controller failed to cache statement and generator")
+	}
+
+	err := c.Close()
+	if err != nil {
+		t.Fatalf("Close failed: %s", err)
+	}
+	if len(c.stmtCache.stmts) != 0 || len(c.sqlGenerators) != 0 {
+		t.Fatalf("Close failed to release cached statements and generators")
+	}
+	if dbConn.Ping() != nil {
+		t.Fatalf("Close closed the database connection")
+	}
+}
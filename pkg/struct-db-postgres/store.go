@@ -0,0 +1,17 @@
+package structdbpostgres
+
+// Store contains methods that get and save objects. It is implemented by Controller, and code that depends on
+// Store instead of Controller can use a different implementation in tests, eg. FakeController from the fake package
+type Store interface {
+	Save(obj interface{}, options SaveOptions) *ErrController
+	Load(obj interface{}, id string, options LoadOptions) *ErrController
+	Delete(obj interface{}, options DeleteOptions) *ErrController
+	DeleteMultiple(obj interface{}, options DeleteMultipleOptions) *ErrController
+	UpdateMultiple(obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) *ErrController
+	Get(newObjFunc func() interface{}, options GetOptions) ([]interface{}, *ErrController)
+	GetCount(newObjFunc func() interface{}, options GetCountOptions) (int64, *ErrController)
+}
+
+// Controller must keep implementing Store, with the same method signatures
+var _ Store = Controller{}
+var _ Store = (*Controller)(nil)
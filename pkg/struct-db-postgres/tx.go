@@ -0,0 +1,103 @@
+package structdbpostgres
+
+import (
+	"context"
+	"fmt"
+)
+
+// Tx is a database transaction in which objects are saved, loaded, deleted etc. the same way as with Controller.
+// Changes are visible to others only after Commit, and they are discarded with Rollback
+type Tx struct {
+	c Controller
+}
+
+// Begin starts a database transaction
+func (c Controller) Begin() (*Tx, *ErrController) {
+	return c.BeginContext(context.Background())
+}
+
+// BeginContext starts a database transaction. When ctx is cancelled before Commit, the transaction is rolled back
+func (c Controller) BeginContext(ctx context.Context) (*Tx, *ErrController) {
+	if c.tx != nil {
+		return nil, &ErrController{
+			Op:  "DBBegin",
+			Err: fmt.Errorf("transaction has already been started"),
+		}
+	}
+
+	tx, err := c.dbConn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, &ErrController{
+			Op:  "DBBegin",
+			Err: fmt.Errorf("Error starting DB transaction: %w", err),
+		}
+	}
+
+	t := &Tx{c: c}
+	t.c.tx = tx
+	return t, nil
+}
+
+// Commit commits the transaction
+func (t *Tx) Commit() *ErrController {
+	err := t.c.tx.Commit()
+	if err != nil {
+		return &ErrController{
+			Op:  "DBCommit",
+			Err: fmt.Errorf("Error committing DB transaction: %w", err),
+		}
+	}
+	return nil
+}
+
+// Rollback discards all the changes made in the transaction
+func (t *Tx) Rollback() *ErrController {
+	err := t.c.tx.Rollback()
+	if err != nil {
+		return &ErrController{
+			Op:  "DBRollback",
+			Err: fmt.Errorf("Error rolling back DB transaction: %w", err),
+		}
+	}
+	return nil
+}
+
+// Save does the same as Controller's Save but in the transaction
+func (t *Tx) Save(obj interface{}, options SaveOptions) *ErrController {
+	return t.c.Save(obj, options)
+}
+
+// SaveMultiple does the same as Controller's SaveMultiple but in the transaction
+func (t *Tx) SaveMultiple(objs []interface{}, options SaveOptions) *ErrController {
+	return t.c.SaveMultiple(objs, options)
+}
+
+// Load does the same as Controller's Load but in the transaction
+func (t *Tx) Load(obj interface{}, id string, options LoadOptions) *ErrController {
+	return t.c.Load(obj, id, options)
+}
+
+// Delete does the same as Controller's Delete but in the transaction, including cascade delete
+func (t *Tx) Delete(obj interface{}, options DeleteOptions) *ErrController {
+	return t.c.Delete(obj, options)
+}
+
+// DeleteMultiple does the same as Controller's DeleteMultiple but in the transaction, including cascade delete
+func (t *Tx) DeleteMultiple(obj interface{}, options DeleteMultipleOptions) *ErrController {
+	return t.c.DeleteMultiple(obj, options)
+}
+
+// UpdateMultiple does the same as Controller's UpdateMultiple but in the transaction
+func (t *Tx) UpdateMultiple(obj interface{}, values map[string]interface{}, options UpdateMultipleOptions) *ErrController {
+	return t.c.UpdateMultiple(obj, values, options)
+}
+
+// Get does the same as Controller's Get but in the transaction. StatementTimeout is not supported
+func (t *Tx) Get(newObjFunc func() interface{}, options GetOptions) ([]interface{}, *ErrController) {
+	return t.c.Get(newObjFunc, options)
+}
+
+// GetCount does the same as Controller's GetCount but in the transaction
+func (t *Tx) GetCount(newObjFunc func() interface{}, options GetCountOptions) (int64, *ErrController) {
+	return t.c.GetCount(newObjFunc, options)
+}
@@ -0,0 +1,119 @@
+package structdbpostgres
+
+import (
+	"testing"
+)
+
+// TestTx tests if changes made in a transaction are saved on commit and discarded on rollback
+func TestTx(t *testing.T) {
+	recreateTestStructTable()
+
+	tx, err := testController.Begin()
+	if err != nil {
+		t.Fatalf("Begin failed to start transaction: %s", err.Op)
+	}
+	ts := getTestStructWithData()
+	ts.ID = 0
+	err = tx.Save(ts, SaveOptions{})
+	if err != nil {
+		t.Fatalf("Save failed to insert object in transaction: %s", err.Op)
+	}
+	cnt, err := tx.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if err != nil || cnt != 1 {
+		t.Fatalf("GetCount failed to count object saved in transaction")
+	}
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatalf("Rollback failed: %s", err.Op)
+	}
+
+	cnt, _ = testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 0 {
+		t.Fatalf("Rollback failed to discard object saved in transaction")
+	}
+
+	tx, _ = testController.Begin()
+	ts.ID = 0
+	tx.Save(ts, SaveOptions{})
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Commit failed: %s", err.Op)
+	}
+
+	cnt, _ = testController.GetCount(func() interface{} { return &TestStruct{} }, GetCountOptions{})
+	if cnt != 1 {
+		t.Fatalf("Commit failed to save object")
+	}
+}
+
+// TestTxDeleteCascade tests if cascade delete in a transaction is discarded on rollback
+func TestTxDeleteCascade(t *testing.T) {
+	p := createTestDelParentWithChildren()
+
+	tx, _ := testController.Begin()
+	err := tx.Delete(p, DeleteOptions{
+		Constructors: map[string]func() interface{}{
+			"DelChildNone":   func() interface{} { return &DelChildNone{} },
+			"DelChildDelete": func() interface{} { return &DelChildDelete{} },
+			"DelChildUpdate": func() interface{} { return &DelChildUpdate{} },
+		},
+	})
+	if err != nil {
+		t.Fatalf("Delete failed to delete object in transaction: %s", err.Op)
+	}
+	tx.Rollback()
+
+	var cnt int
+	err2 := dbConn.QueryRow("SELECT COUNT(*) FROM struct2db_del_child_deletes WHERE del_child_delete_id IN (1, 2, 111, 121, 211, 221, 112, 122, 212, 222, 1001, 1003) AND del_parent_id != 0").Scan(&cnt)
+	if err2 != nil {
+		t.Fatalf("Failed to select count: %s", err2.Error())
+	}
+	if cnt != 12 {
+		t.Fatalf("Rollback failed to discard cascade delete")
+	}
+	err2 = dbConn.QueryRow("SELECT COUNT(*) FROM struct2db_del_parents").Scan(&cnt)
+	if err2 != nil {
+		t.Fatalf("Failed to select count: %s", err2.Error())
+	}
+	if cnt != 1 {
+		t.Fatalf("Rollback failed to discard delete")
+	}
+}
+
+// TestTxGetForShare tests if rows got with ForShare in a transaction can be read with ForShare, but not updated, by
+// another transaction
+func TestTxGetForShare(t *testing.T) {
+	recreateTestStructTable()
+
+	ts := getTestStructWithData()
+	ts.ID = 0
+	testController.Save(ts, SaveOptions{})
+
+	newObjFunc := func() interface{} { return &TestStruct{} }
+	tx, _ := testController.Begin()
+	defer tx.Rollback()
+	xobj, err := tx.Get(newObjFunc, GetOptions{ForShare: true})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("Get failed to return objects with ForShare in transaction")
+	}
+
+	tx2, _ := testController.Begin()
+	xobj, err = tx2.Get(newObjFunc, GetOptions{ForShare: true})
+	if err != nil || len(xobj) != 1 {
+		t.Fatalf("Get failed to return objects locked with ForShare by another transaction")
+	}
+	tx2.Rollback()
+
+	sqlTx, _ := dbConn.Begin()
+	defer sqlTx.Rollback()
+	sqlTx.Exec("SET LOCAL lock_timeout = '100ms'")
+	_, err2 := sqlTx.Exec("UPDATE struct2db_test_structs SET first_name = 'Locked'")
+	if err2 == nil {
+		t.Fatalf("Get with ForShare failed to lock rows against update")
+	}
+
+	_, err = testController.Get(newObjFunc, GetOptions{ForShare: true, ForUpdate: true})
+	if err == nil || err.Op != "InvalidOptions" {
+		t.Fatalf("Get failed to return error when both ForUpdate and ForShare are set")
+	}
+}
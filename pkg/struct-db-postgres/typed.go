@@ -0,0 +1,25 @@
+package structdbpostgres
+
+import "fmt"
+
+// GetTyped does the same as Get but it creates objects of type T itself and returns them as []*T, so that there is
+// no need for a constructor and type assertions. RowObjTransformFunc in options must return *T
+func GetTyped[T any](c *Controller, options GetOptions) ([]*T, *ErrController) {
+	xobj, err := c.Get(func() interface{} { return new(T) }, options)
+	if err != nil {
+		return nil, err
+	}
+
+	objs := make([]*T, 0, len(xobj))
+	for i, obj := range xobj {
+		o, ok := obj.(*T)
+		if !ok {
+			return nil, &ErrController{
+				Op:  "InvalidType",
+				Err: fmt.Errorf("object %d is %T and not %T", i, obj, o),
+			}
+		}
+		objs = append(objs, o)
+	}
+	return objs, nil
+}
@@ -1,24 +1,165 @@
 package structdbpostgres
 
 import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	stsql "github.com/mikolajgs/prototyping/pkg/struct-sql-postgres"
 	validator "github.com/mikolajgs/struct-validator"
 )
 
-// Validate checks object's fields. It returns result of validation as a bool and list of fields with invalid value
+// Validate checks object's fields. It returns result of validation as a bool and list of fields with invalid value.
+// Error is returned when object's SQL generator cannot be created, eg. because of an invalid pattern in a tag
 func (c Controller) Validate(obj interface{}, filters map[string]interface{}) (bool, map[string]int, error) {
+	h, err := c.getSQLGenerator(obj, nil, "")
+	if err != nil {
+		return false, nil, err
+	}
+
+	var valid bool
+	var failedFields map[string]int
 	if filters != nil {
-		valid, failedFields := validator.Validate(obj, &validator.ValidationOptions{
+		valid, failedFields = validator.Validate(obj, &validator.ValidationOptions{
 			ValidateWhenSuffix:   true,
 			OverwriteFieldValues: filters,
 			RestrictFields:       c.mapWithInterfacesToMapBool(filters),
 			OverwriteTagName:     c.tagName,
+			OverwriteFieldTags:   c.getValidationFieldTags(obj),
+		})
+	} else {
+		valid, failedFields = validator.Validate(obj, &validator.ValidationOptions{
+			ValidateWhenSuffix: true,
+			OverwriteTagName:   c.tagName,
+			OverwriteFieldTags: c.getValidationFieldTags(obj),
 		})
-		return valid, failedFields, nil
 	}
 
-	valid, failedFields := validator.Validate(obj, &validator.ValidationOptions{
-		ValidateWhenSuffix: true,
-		OverwriteTagName:   c.tagName,
-	})
+	if !c.validateFieldsRegexp(h, obj, filters, failedFields) {
+		valid = false
+	}
 	return valid, failedFields, nil
 }
+
+// ValidateMany checks fields of each of the objects. It does not stop on the first invalid object and returns
+// a list with failed fields for each of them, in the same order as objects were passed. For a valid object,
+// the list contains nil
+func (c Controller) ValidateMany(objs []interface{}) ([]map[string]int, *ErrController) {
+	xfailedFields := make([]map[string]int, len(objs))
+	for i, obj := range objs {
+		valid, failedFields, err := c.Validate(obj, nil)
+		if err != nil {
+			return nil, &ErrController{
+				Op:  "Validate",
+				Err: fmt.Errorf("Error when trying to validate object %d: %w", i, err),
+			}
+		}
+		if !valid {
+			xfailedFields[i] = failedFields
+		}
+	}
+	return xfailedFields, nil
+}
+
+// validationRuleAliases are names of rules that can be used in the tag instead of the validator's ones, eg.
+// 'minlen:2' instead of 'lenmin:2'
+var validationRuleAliases = map[string]string{
+	"minlen": "lenmin",
+	"maxlen": "lenmax",
+	"min":    "valmin",
+	"max":    "valmax",
+}
+
+// getValidationTag returns field's tag with rule aliases replaced with the validator's rules
+func (c Controller) getValidationTag(sf reflect.StructField) string {
+	opts := strings.Split(sf.Tag.Get(c.tagName), " ")
+	for i, opt := range opts {
+		rule, val, found := strings.Cut(opt, ":")
+		if !found {
+			continue
+		}
+		if alias, ok := validationRuleAliases[rule]; ok {
+			opts[i] = alias + ":" + val
+		}
+	}
+	return strings.Join(opts, " ")
+}
+
+// regexpMatchAll replaces patterns in tags passed to the validator, because it compiles them on each call and panics
+// on an invalid one. Values are matched against patterns compiled by the SQL generator instead
+const regexpMatchAll = "(?:)"
+
+// getValidationFieldTags returns tags of object's fields that use rule aliases or patterns, to be passed to the
+// validator as OverwriteFieldTags
+func (c Controller) getValidationFieldTags(obj interface{}) map[string]map[string]string {
+	t := reflect.Indirect(reflect.ValueOf(obj)).Type()
+	if t.String() == "reflect.Value" {
+		t = reflect.ValueOf(obj.(reflect.Value).Interface()).Type().Elem().Elem()
+	}
+
+	var tags map[string]map[string]string
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		opts := strings.Split(c.getValidationTag(sf), " ")
+		for j, opt := range opts {
+			if strings.HasPrefix(opt, "regexp:") {
+				opts[j] = "regexp:" + regexpMatchAll
+			}
+		}
+		tag := strings.Join(opts, " ")
+		hasRegexpTag := sf.Tag.Get(c.tagName+"_regexp") != ""
+		if tag == sf.Tag.Get(c.tagName) && !hasRegexpTag {
+			continue
+		}
+		if tags == nil {
+			tags = map[string]map[string]string{}
+		}
+		tags[sf.Name] = map[string]string{c.tagName: tag}
+		if hasRegexpTag {
+			tags[sf.Name][c.tagName+"_regexp"] = regexpMatchAll
+		}
+	}
+	return tags
+}
+
+// validateFieldsRegexp matches values of string fields against patterns from their tags, and adds the ones that do
+// not match to failedFields. Field that failed on a rule checked before the pattern keeps its failure, as in the
+// validator. With filters, only the listed fields are checked
+func (c Controller) validateFieldsRegexp(h *stsql.StructSQL, obj interface{}, filters map[string]interface{}, failedFields map[string]int) bool {
+	if _, ok := obj.(reflect.Value); ok {
+		return true
+	}
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return true
+	}
+
+	valid := true
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		re := h.GetFieldRegexp(name)
+		if re == nil {
+			continue
+		}
+		if failure := failedFields[name]; failure != 0 && failure != validator.FailEmail {
+			continue
+		}
+
+		var value string
+		if filters != nil {
+			fv, ok := filters[name].(string)
+			if !ok {
+				continue
+			}
+			value = fv
+		} else {
+			value = v.Field(i).String()
+		}
+
+		if !re.MatchString(value) {
+			failedFields[name] = validator.FailRegexp
+			valid = false
+		}
+	}
+	return valid
+}
@@ -0,0 +1,118 @@
+package structdbpostgres
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	validator "github.com/mikolajgs/struct-validator"
+)
+
+// FailReference is a failure of a foreign key field that refers to an object that does not exist, see
+// CheckReferences in SaveOptions. It is far from the validator's Fail* constants so it does not collide with them
+const FailReference = 1 << 16
+
+// ValidationMessages is a catalog of validation messages in one language. It maps a validation failure, eg.
+// validator.FailLenMin, to a message template. In the template, {field} is replaced with the name of the field
+// and {param} with the value of the failed rule, eg. 2 for 'lenmin:2'
+type ValidationMessages map[int]string
+
+// DefaultValidationMessages are English messages. They are used when there is no catalog for the locale or the
+// catalog does not have a message for the failure
+var DefaultValidationMessages = ValidationMessages{
+	validator.FailEmpty:  "{field} is required",
+	validator.FailZero:   "{field} must not be zero",
+	validator.FailLenMin: "{field} must be at least {param} characters long",
+	validator.FailLenMax: "{field} must be at most {param} characters long",
+	validator.FailValMin: "{field} must be at least {param}",
+	validator.FailValMax: "{field} must be at most {param}",
+	validator.FailRegexp: "{field} has invalid format",
+	validator.FailEmail:  "{field} must be a valid email address",
+	FailReference:        "{field} refers to an object that does not exist",
+}
+
+type localeContextKey struct{}
+
+// WithLocale returns a copy of ctx with the locale, eg. 'pl' or 'pl-PL', that ValidationErrorMessages uses to
+// pick a message catalog
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext returns the locale set with WithLocale or an empty string when there is none
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
+
+// ValidationErrorMessages returns a message for each of the failed fields, eg. from ErrValidation, in the language
+// of the locale from ctx. Catalog is looked up in ValidationMessages from ControllerConfig for the locale, and then
+// for its language only, eg. 'pl' for 'pl-PL'. English is used when there is none
+func (c Controller) ValidationErrorMessages(ctx context.Context, obj interface{}, failedFields map[string]int) map[string]string {
+	catalog := c.getValidationMessages(LocaleFromContext(ctx))
+
+	v := reflect.ValueOf(obj)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	msgs := make(map[string]string, len(failedFields))
+	for field, failure := range failedFields {
+		tmpl, ok := catalog[failure]
+		if !ok {
+			tmpl = DefaultValidationMessages[failure]
+		}
+
+		var param string
+		if sf, ok := v.Type().FieldByName(field); ok {
+			param = c.getValidationParam(sf, failure)
+		}
+
+		msgs[field] = strings.NewReplacer("{field}", field, "{param}", param).Replace(tmpl)
+	}
+	return msgs
+}
+
+func (c Controller) getValidationMessages(locale string) ValidationMessages {
+	if locale == "" {
+		return DefaultValidationMessages
+	}
+	if catalog, ok := c.validationMessages[locale]; ok {
+		return catalog
+	}
+	if lang, _, found := strings.Cut(locale, "-"); found {
+		if catalog, ok := c.validationMessages[lang]; ok {
+			return catalog
+		}
+	}
+	return DefaultValidationMessages
+}
+
+// getValidationParam returns value of the rule that failed from the field's tag, eg. '2' for 'lenmin:2'
+func (c Controller) getValidationParam(sf reflect.StructField, failure int) string {
+	var rule string
+	switch failure {
+	case validator.FailLenMin:
+		rule = "lenmin"
+	case validator.FailLenMax:
+		rule = "lenmax"
+	case validator.FailValMin:
+		rule = "valmin"
+	case validator.FailValMax:
+		rule = "valmax"
+	case validator.FailRegexp:
+		if re := sf.Tag.Get(c.tagName + "_regexp"); re != "" {
+			return re
+		}
+		rule = "regexp"
+	default:
+		return ""
+	}
+
+	for _, opt := range strings.Split(c.getValidationTag(sf), " ") {
+		if strings.HasPrefix(opt, rule+":") {
+			return strings.TrimPrefix(opt, rule+":")
+		}
+	}
+	return ""
+}
@@ -1,9 +1,13 @@
 package structdbpostgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
+
+	validator "github.com/mikolajgs/struct-validator"
 )
 
 // Test struct for validation tests
@@ -114,3 +118,149 @@ func TestValidateWithInvalidStruct(t *testing.T) {
 		}
 	}
 }
+
+// TestValidateMany tests if ValidateMany returns failed fields for each of the invalid objects
+func TestValidateMany(t *testing.T) {
+	ts1 := getValidationTestStructWithData()
+	ts2 := getValidationTestStructWithData()
+	ts2.FirstName = "x"
+	ts3 := getValidationTestStructWithData()
+	ts3.Age = 0
+	ts3.PostCode = "inv"
+
+	xfailedFields, err := testController.ValidateMany([]interface{}{ts1, ts2, ts3})
+	if err != nil {
+		t.Fatalf("ValidateMany failed with an err: %s", err.Op)
+	}
+	if len(xfailedFields) != 3 {
+		t.Fatalf("ValidateMany failed to return failed fields for each object, want %d, got %d", 3, len(xfailedFields))
+	}
+	if xfailedFields[0] != nil {
+		t.Fatalf("ValidateMany returned failed fields for a valid object")
+	}
+	if len(xfailedFields[1]) != 1 || xfailedFields[1]["FirstName"] == 0 {
+		t.Fatalf("ValidateMany failed to return field FirstName in failed fields of the second object")
+	}
+	if len(xfailedFields[2]) != 2 || xfailedFields[2]["Age"] == 0 || xfailedFields[2]["PostCode"] == 0 {
+		t.Fatalf("ValidateMany failed to return fields Age and PostCode in failed fields of the third object")
+	}
+}
+
+// TestValidationErrorMessages tests if ValidationErrorMessages returns messages from the catalog for the locale and
+// falls back to English
+func TestValidationErrorMessages(t *testing.T) {
+	ts := getValidationTestStructWithData()
+	ts.FirstName = "x"
+	ts.Age = 200
+	ts.PrimaryEmail = ""
+
+	_, failedFields, _ := testController.Validate(ts, nil)
+
+	ctl := NewController(nil, "", &ControllerConfig{
+		ValidationMessages: map[string]ValidationMessages{
+			"pl": {
+				validator.FailLenMin: "{field} musi mieć co najmniej {param} znaki",
+				validator.FailValMax: "{field} może wynosić najwyżej {param}",
+			},
+		},
+	})
+
+	msgs := ctl.ValidationErrorMessages(context.Background(), ts, failedFields)
+	if msgs["FirstName"] != "FirstName must be at least 2 characters long" || msgs["Age"] != "Age must be at most 120" || msgs["PrimaryEmail"] != "PrimaryEmail is required" {
+		t.Fatalf("ValidationErrorMessages failed to return English messages without locale, got %v", msgs)
+	}
+
+	msgs = ctl.ValidationErrorMessages(WithLocale(context.Background(), "pl-PL"), ts, failedFields)
+	if msgs["FirstName"] != "FirstName musi mieć co najmniej 2 znaki" || msgs["Age"] != "Age może wynosić najwyżej 120" {
+		t.Fatalf("ValidationErrorMessages failed to return messages from the catalog for the locale, got %v", msgs)
+	}
+	if msgs["PrimaryEmail"] != "PrimaryEmail is required" {
+		t.Fatalf("ValidationErrorMessages failed to fall back to English message missing in the catalog, got %v", msgs["PrimaryEmail"])
+	}
+}
+
+// Test struct for validation rule aliases
+type ValidationAliasTestStruct struct {
+	ID   int64
+	Name string `2db:"req minlen:2 maxlen:50"`
+	Age  int    `2db:"min:0 max:150"`
+}
+
+// TestValidateWithRuleAliases tests if Validate enforces 'minlen', 'maxlen', 'min' and 'max' the same as the
+// validator's rules
+func TestValidateWithRuleAliases(t *testing.T) {
+	ts := &ValidationAliasTestStruct{Name: "John", Age: 0}
+	b, failedFields, _ := testController.Validate(ts, nil)
+	if !b || len(failedFields) > 0 {
+		t.Fatalf("Validate failed to validate valid struct with rule aliases, got %v", failedFields)
+	}
+
+	ts.Name = "x"
+	ts.Age = 151
+	b, failedFields, _ = testController.Validate(ts, nil)
+	if b || failedFields["Name"] != validator.FailLenMin || failedFields["Age"] != validator.FailValMax {
+		t.Fatalf("Validate failed to return fields with rule aliases in failed fields, got %v", failedFields)
+	}
+
+	ts.Age = -1
+	_, failedFields, _ = testController.Validate(ts, map[string]interface{}{"Age": -1})
+	if len(failedFields) != 1 || failedFields["Age"] != validator.FailValMin {
+		t.Fatalf("Validate failed to validate listed field with rule alias, got %v", failedFields)
+	}
+
+	msgs := testController.ValidationErrorMessages(context.Background(), ts, failedFields)
+	if msgs["Age"] != "Age must be at least 0" {
+		t.Fatalf("ValidationErrorMessages failed to return param of rule alias, got %v", msgs)
+	}
+}
+
+// Test structs for pattern validation
+type ValidationRegexpTestStruct struct {
+	ID      int64
+	Code    string `2db:"req regexp:^[A-Z]{3}$"`
+	Name    string `2db_regexp:"^[A-Z][a-z]+( [A-Z][a-z]+)*$"`
+	Contact string `2db:"email"`
+}
+
+type ValidationInvalidRegexpTestStruct struct {
+	ID   int64
+	Code string `2db:"regexp:^[A-Z{3}$"`
+}
+
+// TestValidateWithRegexp tests if Validate matches string fields against patterns and returns an error instead of
+// panicking when a pattern is invalid
+func TestValidateWithRegexp(t *testing.T) {
+	ts := &ValidationRegexpTestStruct{Code: "WAW", Name: "Warsaw Chopin", Contact: "info@example.com"}
+	b, failedFields, err := testController.Validate(ts, nil)
+	if err != nil || !b || len(failedFields) > 0 {
+		t.Fatalf("Validate failed to validate struct with values matching patterns, got %v", failedFields)
+	}
+
+	ts.Code = "waw"
+	ts.Name = "warsaw"
+	ts.Contact = "invalidemail"
+	b, failedFields, _ = testController.Validate(ts, nil)
+	if b || failedFields["Code"] != validator.FailRegexp || failedFields["Name"] != validator.FailRegexp || failedFields["Contact"] != validator.FailEmail {
+		t.Fatalf("Validate failed to return fields not matching patterns in failed fields, got %v", failedFields)
+	}
+
+	_, failedFields, _ = testController.Validate(ts, map[string]interface{}{"Code": "WA"})
+	if len(failedFields) != 1 || failedFields["Code"] != validator.FailRegexp {
+		t.Fatalf("Validate failed to match listed field against pattern, got %v", failedFields)
+	}
+
+	err2 := testController.Save(ts, SaveOptions{})
+	var errValidation *ErrValidation
+	if err2 == nil || err2.Op != "Validate" || !errors.As(err2.Err, &errValidation) || len(errValidation.Fields) != 3 {
+		t.Fatalf("Save failed to return ErrValidation for fields not matching patterns")
+	}
+
+	_, _, err = testController.Validate(&ValidationInvalidRegexpTestStruct{Code: "WAW"}, nil)
+	if err == nil {
+		t.Fatalf("Validate failed to return an err for invalid pattern")
+	}
+	err2 = testController.Save(&ValidationInvalidRegexpTestStruct{Code: "WAW"}, SaveOptions{})
+	if err2 == nil || err2.Op != "GetHelper" {
+		t.Fatalf("Save failed to return GetHelper err for invalid pattern")
+	}
+}
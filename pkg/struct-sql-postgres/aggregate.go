@@ -0,0 +1,20 @@
+package structsqlpostgres
+
+// AggregateFunc is an SQL aggregate function, eg. SUM, that is used in GetQuerySelectAggregate.
+type AggregateFunc string
+
+const (
+	Sum AggregateFunc = "SUM"
+	Avg AggregateFunc = "AVG"
+	Min AggregateFunc = "MIN"
+	Max AggregateFunc = "MAX"
+)
+
+// IsValid checks if the function is one of Sum, Avg, Min or Max, as the others are not supported.
+func (fn AggregateFunc) IsValid() bool {
+	switch fn {
+	case Sum, Avg, Min, Max:
+		return true
+	}
+	return false
+}
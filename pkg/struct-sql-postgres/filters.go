@@ -0,0 +1,105 @@
+package structsqlpostgres
+
+import "reflect"
+
+// Raw is a filter (or update) value which is a trusted SQL expression, eg. Raw("now()"). It is put into the query
+// as it is, instead of being passed as a bind parameter, hence it must never contain any user input as it
+// would lead to an SQL injection. Use it only for expressions that are controlled by the server code.
+type Raw string
+
+// FilterGroup is a group of filters that are joined with its Conjunction, RawConjuctionOR or RawConjuctionAND (the
+// default), and put in parentheses, eg. (status=$1 OR age>$2). Groups are passed in filters under the '_groups' key
+// as []FilterGroup, and they are AND-ed with each other and with the other filters.
+type FilterGroup struct {
+	Conjunction int
+	Filters     map[string]interface{}
+}
+
+// Keyset is a condition for keyset pagination. It matches rows that come after the one with Values in the order of
+// Fields, eg. (created_at,id)<($1,$2) when Desc is set. Fields are compared as a row value, so all of them must be
+// ordered in the same direction. Keyset is passed in filters under the '_keyset' key.
+type Keyset struct {
+	Fields []string
+	Values []interface{}
+	Desc   bool
+}
+
+// Filter is a filter value that compares a column with a value using an operator other than equality, eg. Gt(18)
+// gives 'age>$1'. It is created with one of the functions below, and the value is passed as a bind parameter.
+type Filter struct {
+	op    string
+	value interface{}
+}
+
+// Operator returns SQL operator of the filter, eg. '>'.
+func (f Filter) Operator() string {
+	return f.op
+}
+
+// Value returns value that column is compared with.
+func (f Filter) Value() interface{} {
+	return f.value
+}
+
+// Gt returns a filter value matching rows where column is greater than v.
+func Gt(v interface{}) Filter {
+	return Filter{op: ">", value: v}
+}
+
+// Gte returns a filter value matching rows where column is greater than or equal to v.
+func Gte(v interface{}) Filter {
+	return Filter{op: ">=", value: v}
+}
+
+// Lt returns a filter value matching rows where column is less than v.
+func Lt(v interface{}) Filter {
+	return Filter{op: "<", value: v}
+}
+
+// Lte returns a filter value matching rows where column is less than or equal to v.
+func Lte(v interface{}) Filter {
+	return Filter{op: "<=", value: v}
+}
+
+// Ne returns a filter value matching rows where column is not equal to v.
+func Ne(v interface{}) Filter {
+	return Filter{op: "!=", value: v}
+}
+
+// IsNull returns a filter value matching rows where column is NULL, eg. 'deleted_at IS NULL'. It has no bind
+// parameter. Only columns of pointer fields can be NULL, others are NOT NULL.
+func IsNull() Filter {
+	return Filter{op: "IS NULL"}
+}
+
+// IsNotNull returns a filter value matching rows where column is not NULL. See IsNull.
+func IsNotNull() Filter {
+	return Filter{op: "IS NOT NULL"}
+}
+
+// HasValue returns false when filter does not compare column with a value, ie. it is IsNull or IsNotNull, so it has
+// no bind parameter.
+func (f Filter) HasValue() bool {
+	return f.op != "IS NULL" && f.op != "IS NOT NULL"
+}
+
+// IsFilterValueList returns true when filter value is a slice or an array, which matches rows where column is equal
+// to any of its items. []byte is not a list as it is a single value of a BYTEA column.
+func IsFilterValueList(v interface{}) bool {
+	t := reflect.TypeOf(v)
+	if t == nil || (t.Kind() != reflect.Slice && t.Kind() != reflect.Array) {
+		return false
+	}
+	return t.Elem().Kind() != reflect.Uint8
+}
+
+// Like returns a filter value matching rows where column matches pattern v, with '%' and '_' wildcards that must be
+// put in v by the caller, eg. Like("%john%").
+func Like(v string) Filter {
+	return Filter{op: "LIKE", value: v}
+}
+
+// ILike returns a filter value matching rows where column matches pattern v case-insensitively. See Like.
+func ILike(v string) Filter {
+	return Filter{op: "ILIKE", value: v}
+}
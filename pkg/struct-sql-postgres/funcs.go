@@ -1,6 +1,10 @@
 package structsqlpostgres
 
-import "reflect"
+import (
+	"reflect"
+	"strings"
+	"time"
+)
 
 // GetStructName returns struct name of a struct instance
 func GetStructName(u interface{}) string {
@@ -20,9 +24,8 @@ func GetStructFieldNames(u interface{}) []string {
 
 	for j := 0; j < s.NumField(); j++ {
 		f := s.Field(j)
-		k := f.Type.Kind()
 
-		if IsFieldKindSupported(k) {
+		if IsFieldTypeSupported(f.Type) {
 			continue
 		}
 
@@ -45,6 +48,56 @@ func GetStructNamesFromConstructors(objFuncs ...func() interface{}) []string {
 	return names
 }
 
+// IsFieldTypeSupported checks if a field type is supported by this module. Apart from kinds supported by
+// IsFieldKindSupported, it allows byte slices, which are stored as BYTEA, time.Time, stored as TIMESTAMPTZ, and
+// pointers to all of them, which are stored in nullable columns
+func IsFieldTypeSupported(t reflect.Type) bool {
+	if IsFieldNullable(t) {
+		return true
+	}
+	return isFieldValueTypeSupported(t)
+}
+
+// IsFieldNullable checks if a field type is a pointer to a supported type, eg. *string or *time.Time. Such field
+// is stored in a column without NOT NULL, and nil pointer is NULL
+func IsFieldNullable(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && isFieldValueTypeSupported(t.Elem())
+}
+
+func isFieldValueTypeSupported(t reflect.Type) bool {
+	if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+		return true
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return true
+	}
+	return IsFieldKindSupported(t.Kind())
+}
+
+// IsStructFieldSupported checks if a struct field is stored in a column, either because its type is supported or
+// because it is a JSON field (see IsFieldJSON). tagName is the name of the tag with options, eg. '2sql'
+func IsStructFieldSupported(f reflect.StructField, tagName string) bool {
+	return IsFieldTypeSupported(f.Type) || IsFieldJSON(f, tagName)
+}
+
+// IsFieldJSON checks if a struct field is a map or a struct with 'json' option in its tag, eg. '2sql:"json"'. Such
+// field is stored as JSONB, and it has to be marshaled before it is saved
+func IsFieldJSON(f reflect.StructField, tagName string) bool {
+	if !isJSONFieldType(f.Type) {
+		return false
+	}
+	for _, opt := range strings.Split(f.Tag.Get(tagName), " ") {
+		if opt == "json" {
+			return true
+		}
+	}
+	return false
+}
+
+func isJSONFieldType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map || (t.Kind() == reflect.Struct && t != reflect.TypeOf(time.Time{}))
+}
+
 // IsFieldKindSupported checks if a field kind is supported by this module
 func IsFieldKindSupported(k reflect.Kind) bool {
 	switch k {
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -57,7 +58,7 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 		usName = h.getUnderscoredName(forceName)
 	}
 	usPluName := h.getPluralName(usName)
-	h.dbTbl = dbTablePrefix + usPluName
+	h.dbTbl = dbTablePrefix + usPluName + h.dbTblSuffix
 	h.dbColPrefix = usName
 	h.url = usPluName
 
@@ -65,7 +66,7 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 	h.dbCols = make(map[string]string)
 
 	var colsWithTypes, cols, vals, valsWithoutID, colsWithoutID, colVals, colValsAgain string
-	idCol := h.dbColPrefix + "_id"
+	idCol := h.getDBCol(h.idField)
 	if h.hasJoined {
 		idCol = fmt.Sprintf("t1.%s", idCol)
 	}
@@ -77,13 +78,22 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 	valCnt := 0
 	valWithoutIDCnt := 0
 
+	var checks []string
+
 	for j := 0; j < s.NumField(); j++ {
 		f := s.Field(j)
-		k := f.Type.Kind()
 
-		// Only basic golang types are included as columns for the database table.
-		// Check the function below for the details.
-		if !IsFieldKindSupported(k) {
+		// Blank fields, eg. '_ struct{}', can have a CHECK constraint of the table in the '2sql_check' tag
+		if f.Name == "_" {
+			if check := f.Tag.Get(h.tagName + "_check"); check != "" {
+				checks = append(checks, check)
+			}
+			continue
+		}
+
+		// Only basic golang types, and maps and structs with 'json' tag, are included as columns for the database
+		// table. Check the function below for the details.
+		if !IsStructFieldSupported(f, h.tagName) {
 			continue
 		}
 
@@ -105,7 +115,7 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 					" INNER JOIN %s %s ON %s=%s.%s",
 					tbl, alias,
 					h.dbFieldCols[fieldNameArr[0]+"ID"],
-					alias, h.joined[fieldNameArr[0]].dbFieldCols["ID"],
+					alias, h.joined[fieldNameArr[0]].dbFieldCols[h.joined[fieldNameArr[0]].idField],
 				)
 				joinedTables[fieldNameArr[0]] = alias
 			}
@@ -137,12 +147,13 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 			uniq = true
 		}
 		dbColParams := h.getDBColParams(f.Name, f.Type.String(), uniq)
+		h.dbColTypes[f.Name] = h.getDBColType(f.Name, dbColParams)
 
 		colsWithTypes = h.addWithComma(colsWithTypes, dbCol+" "+dbColParams)
 		cols = h.addWithComma(cols, dbCol)
 
-		// Assuming that primary field is named ID
-		if f.Name != "ID" {
+		// Primary key is the ID field, unless another one has the 'pk' tag
+		if f.Name != h.idField {
 			colsWithoutID = h.addWithComma(colsWithoutID, dbCol)
 			colVals = h.addWithComma(colVals, dbCol+"=?")
 			valWithoutIDCnt++
@@ -155,6 +166,10 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 
 	colValsAgain = colVals
 
+	for _, check := range checks {
+		colsWithTypes = h.addWithComma(colsWithTypes, "CHECK ("+h.getQueryCheck(check)+")")
+	}
+
 	if valCnt > 0 {
 		vals = "?"
 		if valCnt > 1 {
@@ -167,36 +182,39 @@ func (h *StructSQL) reflectStructForDBQueries(u interface{}, dbTablePrefix strin
 			valsWithoutID = valsWithoutID + strings.Repeat(",?", valWithoutIDCnt-1)
 		}
 	}
-	if valCnt > 0 {
-		for i := 1; i <= valCnt; i++ {
-			vals = strings.Replace(vals, "?", fmt.Sprintf("$%d", i), 1)
-			valsWithoutID = strings.Replace(valsWithoutID, "?", fmt.Sprintf("$%d", i), 1)
-			colVals = strings.Replace(colVals, "?", fmt.Sprintf("$%d", i), 1)
-		}
-		dollarCnt := strings.Count(vals, "$")
-		for i := dollarCnt + 1; i <= dollarCnt+valCnt; i++ {
-			colValsAgain = strings.Replace(colValsAgain, "?", fmt.Sprintf("$%d", i), 1)
-		}
-	}
+	vals = h.numberPlaceholders(vals, 1)
+	valsWithoutID = h.numberPlaceholders(valsWithoutID, 1)
+	colVals = h.numberPlaceholders(colVals, 1)
+	colValsAgain = h.numberPlaceholders(colValsAgain, valCnt+1)
 
 	// Full SQL queries or their prefixes. Query parts such as columns and values in UPDATE or conditions after WHERE etc. must be generated on the fly and cannot be cached.
 	h.queryDropTable = fmt.Sprintf("DROP TABLE IF EXISTS %s", h.dbTbl)
 	h.queryCreateTable = fmt.Sprintf("CREATE TABLE %s (%s)", h.dbTbl, colsWithTypes)
-	h.queryDeleteById = fmt.Sprintf("DELETE FROM %s WHERE %s = $1", h.dbTbl, idCol)
+	h.queryDeleteById = fmt.Sprintf("DELETE FROM %s WHERE %s = %s", h.dbTbl, idCol, h.placeholder.Render(1))
+	h.queryExistsById = fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE %s = %s)", h.dbTbl, idCol, h.placeholder.Render(1))
 	h.queryInsert = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) RETURNING %s", h.dbTbl, colsWithoutID, valsWithoutID, idCol)
-	h.queryUpdateById = fmt.Sprintf("UPDATE %s SET %s WHERE %s = $%d", h.dbTbl, colVals, idCol, valCnt)
+	h.queryInsertWithID = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) RETURNING %s", h.dbTbl, cols, vals, idCol)
+	h.querySyncIDSequence = fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', '%s'), COALESCE(MAX(%s), 0) + 1, false) FROM %s", h.dbTbl, idCol, idCol, h.dbTbl)
+	h.queryUpdateById = fmt.Sprintf("UPDATE %s SET %s WHERE %s = %s", h.dbTbl, colVals, idCol, h.placeholder.Render(valCnt))
 	h.queryInsertOnConflictUpdate = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s RETURNING %s", h.dbTbl, cols, vals, idCol, colValsAgain, idCol)
+	h.queryInsertOnConflictUpdateReturningInserted = h.queryInsertOnConflictUpdate + ",(xmax = 0) AS inserted"
+	h.queryInsertOnConflictUpdatePrefix = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET", h.dbTbl, cols, vals, idCol)
+	h.queryInsertValues = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s)", h.dbTbl, colsWithoutID, valsWithoutID)
+	h.queryInsertWithIDValues = fmt.Sprintf("INSERT INTO %s(%s) VALUES (%s)", h.dbTbl, cols, vals)
 	h.queryDeletePrefix = fmt.Sprintf("DELETE FROM %s", h.dbTbl)
 	h.queryUpdatePrefix = fmt.Sprintf("UPDATE %s SET", h.dbTbl)
+	h.queryColumns = cols
 
 	if h.hasJoined {
-		h.querySelectById = fmt.Sprintf("SELECT %s FROM %s t1%s WHERE %s = $1", cols, h.dbTbl, innerJoins, idCol)
+		h.querySelectById = fmt.Sprintf("SELECT %s FROM %s t1%s WHERE %s = %s", cols, h.dbTbl, innerJoins, idCol, h.placeholder.Render(1))
 		h.querySelectPrefix = fmt.Sprintf("SELECT %s FROM %s t1%s", cols, h.dbTbl, innerJoins)
 		h.querySelectCountPrefix = fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s t1%s", h.dbTbl, innerJoins)
+		h.queryFrom = fmt.Sprintf("FROM %s t1%s", h.dbTbl, innerJoins)
 	} else {
-		h.querySelectById = fmt.Sprintf("SELECT %s FROM %s%s WHERE %s = $1", cols, h.dbTbl, innerJoins, idCol)
+		h.querySelectById = fmt.Sprintf("SELECT %s FROM %s%s WHERE %s = %s", cols, h.dbTbl, innerJoins, idCol, h.placeholder.Render(1))
 		h.querySelectPrefix = fmt.Sprintf("SELECT %s FROM %s%s", cols, h.dbTbl, innerJoins)
 		h.querySelectCountPrefix = fmt.Sprintf("SELECT COUNT(*) AS cnt FROM %s%s", h.dbTbl, innerJoins)
+		h.queryFrom = fmt.Sprintf("FROM %s%s", h.dbTbl, innerJoins)
 	}
 
 }
@@ -206,9 +224,11 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 	i := reflect.Indirect(v)
 	s := i.Type()
 
+	// Joined struct is passed as reflect.Value so its tags, eg. 'pk', are taken from the type behind it
 	var isReflectValue bool
 	if s.String() == "reflect.Value" {
 		isReflectValue = true
+		s = reflect.ValueOf(u.(reflect.Value).Interface()).Type().Elem().Elem()
 	}
 
 	var ve reflect.Value
@@ -220,6 +240,11 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 	h.fieldsUniq = make(map[string]bool)
 	h.fieldsTags = make(map[string]map[string]string)
 	h.fieldsOverwriteType = make(map[string]string)
+	h.fieldsComment = make(map[string]string)
+	h.fieldsJSON = make(map[string]bool)
+	h.fieldsRegexp = make(map[string]*regexp.Regexp)
+	h.idField = "ID"
+	h.dbColTypes = make(map[string]string)
 
 	reDep := regexp.MustCompile(`^[a-zA-Z0-9]+_[a-zA-Z0-9]+`)
 
@@ -254,6 +279,7 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 						h.joined[fieldNameArr[0]] = NewStructSQL(reflect.New(valueField.Type()), StructSQLOptions{
 							ForceName:           childStructName,
 							DatabaseTablePrefix: dbTablePrefix,
+							TagName:             h.tagName,
 						})
 					}
 				}
@@ -263,11 +289,10 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 
 	for j := 0; j < s.NumField(); j++ {
 		f := s.Field(j)
-		k := f.Type.Kind()
 
-		// Only basic golang types are included as columns for the database table.
-		// Check the function below for the details.
-		if !IsFieldKindSupported(k) {
+		// Only basic golang types, and maps and structs with 'json' tag, are included as columns for the database
+		// table. Check the function below for the details.
+		if !IsStructFieldSupported(f, h.tagName) {
 			continue
 		}
 
@@ -309,7 +334,7 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 		}
 
 		// Go through tag values and parse out the ones we're interested in
-		h.setFieldFromTag(tagValue, f.Name)
+		h.setFieldFromTag(tagValue, f.Name, f.Type)
 		if h.err != nil {
 			return
 		}
@@ -318,6 +343,19 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 			h.fieldsDefaultValue[f.Name] = valTagValue
 		}
 
+		// Comment is in a separate tag as it may contain spaces
+		if comment := f.Tag.Get(h.tagName + "_comment"); comment != "" {
+			h.fieldsComment[f.Name] = comment
+		}
+
+		// Pattern in a separate tag can contain spaces, and it is used instead of the one from 'regexp' rule
+		if pattern := f.Tag.Get(h.tagName + "_regexp"); pattern != "" && f.Type.Kind() == reflect.String {
+			h.setFieldRegexp(f.Name, h.tagName+"_regexp", pattern)
+			if h.err != nil {
+				return
+			}
+		}
+
 		// Store original field tags (non-overwritten one) so they can be easily returned and used as
 		// defaultFieldsTags in another struct
 		h.fieldsTags[f.Name] = make(map[string]string)
@@ -326,22 +364,49 @@ func (h *StructSQL) reflectStructTags(u interface{}, dbTablePrefix string) {
 	}
 }
 
-func (h *StructSQL) setFieldFromTag(tag string, fieldName string) {
+func (h *StructSQL) setFieldFromTag(tag string, fieldName string, fieldType reflect.Type) {
 	opts := strings.SplitN(tag, " ", -1)
 	for _, opt := range opts {
-		h.setFieldFromTagOptWithoutVal(opt, fieldName)
+		h.setFieldFromTagOptWithoutVal(opt, fieldName, fieldType)
 	}
 }
 
-func (h *StructSQL) setFieldFromTagOptWithoutVal(opt string, fieldName string) {
+func (h *StructSQL) setFieldFromTagOptWithoutVal(opt string, fieldName string, fieldType reflect.Type) {
 	if opt == "uniq" {
 		h.fieldsUniq[fieldName] = true
 		return
 	}
+	if opt == "path" {
+		h.pathField = fieldName
+		return
+	}
+	// Primary key can be a string, eg. UUID, or an integer, which is SERIAL
+	if opt == "pk" && (fieldType.Kind() == reflect.String || fieldType.Kind() == reflect.Int64 || fieldType.Kind() == reflect.Int) {
+		h.idField = fieldName
+		return
+	}
+	if opt == "json" && isJSONFieldType(fieldType) {
+		h.fieldsJSON[fieldName] = true
+		return
+	}
+	// Timestamps are set by the controller, so only time.Time fields can have them
+	if opt == "created_at" && fieldType == reflect.TypeOf(time.Time{}) {
+		h.createdAtField = fieldName
+		return
+	}
+	if opt == "updated_at" && fieldType == reflect.TypeOf(time.Time{}) {
+		h.updatedAtField = fieldName
+		return
+	}
+	// Pattern is compiled once, and it can be only used on a string field
+	if strings.HasPrefix(opt, "regexp:") && fieldType.Kind() == reflect.String {
+		h.setFieldRegexp(fieldName, opt, strings.TrimPrefix(opt, "regexp:"))
+		return
+	}
 	if strings.HasPrefix(opt, "db_type:") {
 		dbTypeArr := strings.Split(opt, ":")
 		typeUpperCase := strings.ToUpper(dbTypeArr[1])
-		if typeUpperCase == "TEXT" || typeUpperCase == "BPCHAR" {
+		if typeUpperCase == "TEXT" || typeUpperCase == "BPCHAR" || typeUpperCase == "LTREE" || typeUpperCase == "UUID" {
 			h.fieldsOverwriteType[fieldName] = typeUpperCase
 			return
 		}
@@ -353,6 +418,20 @@ func (h *StructSQL) setFieldFromTagOptWithoutVal(opt string, fieldName string) {
 	}
 }
 
+// setFieldRegexp compiles the pattern from field's tag. Invalid pattern is an error of the whole struct
+func (h *StructSQL) setFieldRegexp(fieldName string, tag string, pattern string) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		h.err = &ErrStructSQL{
+			Op:  "ParseTag",
+			Tag: tag,
+			Err: fmt.Errorf("invalid regexp in tag of field %s: %w", fieldName, err),
+		}
+		return
+	}
+	h.fieldsRegexp[fieldName] = re
+}
+
 func (h *StructSQL) getDBCol(n string) string {
 	dbCol := ""
 	if n == "ID" {
@@ -365,20 +444,48 @@ func (h *StructSQL) getDBCol(n string) string {
 	return dbCol
 }
 
-// Mapping database column type to struct field type
+// getDBColType returns column type without constraints and default value, eg. VARCHAR(255), that can be used in
+// a cast. SERIAL is not a real type so BIGINT is returned for an integer primary key
+func (h *StructSQL) getDBColType(n string, dbColParams string) string {
+	if n == h.idField && strings.HasPrefix(dbColParams, "SERIAL") {
+		return "BIGINT"
+	}
+	if n == h.idField {
+		return strings.TrimSuffix(dbColParams, " PRIMARY KEY")
+	}
+	return strings.SplitN(strings.SplitN(dbColParams, " NOT NULL", 2)[0], " UNIQUE", 2)[0]
+}
+
+// Mapping database column type to struct field type. Pointer field, eg. '*string', has the same column type as
+// the field it points to, but the column is nullable and has no default value
 func (h *StructSQL) getDBColParams(n string, t string, uniq bool) string {
+	nullable := strings.HasPrefix(t, "*") && n != h.idField
+	t = strings.TrimPrefix(t, "*")
+
 	dbColParams := ""
-	if n == "ID" {
+	if n == h.idField && t == "string" && h.fieldsOverwriteType[n] != "" {
+		dbColParams = h.fieldsOverwriteType[n] + " PRIMARY KEY"
+	} else if n == h.idField && t == "string" {
+		dbColParams = "VARCHAR(255) PRIMARY KEY"
+	} else if n == h.idField {
 		dbColParams = "SERIAL PRIMARY KEY"
 	} else if n == "Flags" {
 		dbColParams = "BIGINT NOT NULL DEFAULT 0"
-		// String types can be overwritten by a tag
+	} else if h.fieldsJSON[n] {
+		dbColParams = "JSONB NOT NULL DEFAULT '{}'"
+		// String types can be overwritten by a tag. Empty string is not a valid UUID so it has no default value
+	} else if h.fieldsOverwriteType[n] == "UUID" {
+		dbColParams = "UUID NOT NULL"
 	} else if h.fieldsOverwriteType[n] != "" {
 		dbColParams = h.fieldsOverwriteType[n] + " NOT NULL DEFAULT ''"
 	} else {
 		switch t {
 		case "string":
 			dbColParams = "VARCHAR(255) NOT NULL DEFAULT ''"
+		case "[]uint8":
+			dbColParams = "BYTEA NOT NULL DEFAULT ''"
+		case "time.Time":
+			dbColParams = "TIMESTAMPTZ NOT NULL DEFAULT now()"
 		case "bool":
 			dbColParams = "BOOLEAN NOT NULL DEFAULT false"
 		case "int64":
@@ -406,6 +513,9 @@ func (h *StructSQL) getDBColParams(n string, t string, uniq bool) string {
 			dbColParams = "VARCHAR(255) NOT NULL DEFAULT ''"
 		}
 	}
+	if nullable {
+		dbColParams = strings.SplitN(dbColParams, " NOT NULL", 2)[0]
+	}
 	if uniq {
 		dbColParams += " UNIQUE"
 	}
@@ -420,6 +530,180 @@ func (h *StructSQL) addWithComma(s string, v string) string {
 	return s
 }
 
+// getFieldCondition returns a 'column=value' part of the query for a field value, where value is a placeholder
+// numbered with 'i' or an expression, depending on value type. For Filter, its operator is used instead of '='.
+// For a list of values, 'column IN (...)' is returned. It returns the number for the next placeholder
+func (h *StructSQL) getFieldCondition(col string, value interface{}, i int) (string, int) {
+	switch v := value.(type) {
+	case Raw:
+		return col + "=" + string(v), i
+	case Filter:
+		if !v.HasValue() {
+			return col + " " + v.op, i
+		}
+		// Operators that are words, such as LIKE, must be separated with spaces
+		if v.op == "LIKE" || v.op == "ILIKE" {
+			return col + " " + v.op + " " + h.placeholder.Render(i), i + 1
+		}
+		return col + v.op + h.placeholder.Render(i), i + 1
+	}
+
+	// Slice, except []byte which is a single BYTEA value, gives 'column IN (...)' with a placeholder for each item
+	if IsFilterValueList(value) {
+		n := reflect.ValueOf(value).Len()
+		if n == 0 {
+			return "1=0", i
+		}
+		vals := ""
+		for j := 0; j < n; j++ {
+			vals = h.addWithComma(vals, h.placeholder.Render(i))
+			i++
+		}
+		return col + " IN (" + vals + ")", i
+	}
+	return col + "=" + h.placeholder.Render(i), i + 1
+}
+
+// getFilterCol returns table column of a filter. Filter named ID is the primary key when the struct has no ID
+// field, so generic code can filter by it without knowing the name of the field
+func (h *StructSQL) getFilterCol(k string) string {
+	return h.dbFieldCols[h.GetFilterFieldName(k)]
+}
+
+// getFilterGroupCondition returns conditions for filters of the group joined with the group's conjunction, and
+// number of the next placeholder. Filters are sorted by their names, the same way as other filters
+func (h *StructSQL) getFilterGroupCondition(group FilterGroup, filterFieldsToInclude map[string]bool, i int) (string, int) {
+	conjunction := " AND "
+	if group.Conjunction == RawConjuctionOR {
+		conjunction = " OR "
+	}
+
+	filterNames := []string{}
+	for k := range group.Filters {
+		filterNames = append(filterNames, k)
+	}
+	sort.Strings(filterNames)
+
+	conds := ""
+	for _, k := range filterNames {
+		if h.getFilterCol(k) == "" {
+			continue
+		}
+		if len(filterFieldsToInclude) > 0 && !filterFieldsToInclude[k] {
+			continue
+		}
+		var cond string
+		cond, i = h.getFieldCondition(h.getFilterCol(k), group.Filters[k], i)
+		if conds != "" {
+			conds += conjunction
+		}
+		conds += cond
+	}
+	return conds, i
+}
+
+// getKeysetCondition returns row value comparison of keyset fields with their values, eg. (age,id)>($1,$2), and
+// number of the next placeholder. It returns empty string when any of the fields does not exist
+func (h *StructSQL) getKeysetCondition(keyset Keyset, i int) (string, int) {
+	if len(keyset.Fields) == 0 || len(keyset.Fields) != len(keyset.Values) {
+		return "", i
+	}
+
+	for _, k := range keyset.Fields {
+		if h.dbFieldCols[k] == "" {
+			return "", i
+		}
+	}
+
+	cols := ""
+	vals := ""
+	for _, k := range keyset.Fields {
+		cols = h.addWithComma(cols, h.dbFieldCols[k])
+		vals = h.addWithComma(vals, h.placeholder.Render(i))
+		i++
+	}
+
+	op := ">"
+	if keyset.Desc {
+		op = "<"
+	}
+	return "(" + cols + ")" + op + "(" + vals + ")", i
+}
+
+// getQueryConflictSet returns 'col=EXCLUDED.col' for each of the fields, or for all the fields except ID and the
+// 'created_at' one when there are none, for ON CONFLICT DO UPDATE SET. It returns empty string when any of the
+// fields does not exist
+func (h *StructSQL) getQueryConflictSet(fieldNames []string) string {
+	if len(fieldNames) == 0 {
+		for _, fieldName := range h.fields {
+			if fieldName != h.idField && fieldName != h.createdAtField {
+				fieldNames = append(fieldNames, fieldName)
+			}
+		}
+	}
+
+	qSet := ""
+	for _, fieldName := range fieldNames {
+		col := h.dbFieldCols[fieldName]
+		if col == "" || fieldName == h.idField {
+			return ""
+		}
+		qSet = h.addWithComma(qSet, col+"=EXCLUDED."+col)
+	}
+	return qSet
+}
+
+// getQueryReturningInserted returns RETURNING clause of "upsert" queries with ID, a boolean column which is true when
+// row was inserted, and the 'created_at' column, when there is one, so that the existing value can be scanned
+func (h *StructSQL) getQueryReturningInserted() string {
+	s := fmt.Sprintf(" RETURNING %s,(xmax = 0) AS inserted", h.dbFieldCols[h.idField])
+	if h.createdAtField != "" {
+		s += "," + h.dbFieldCols[h.createdAtField]
+	}
+	return s
+}
+
+// getQueryCheck returns expression of a CHECK constraint with '.Field' replaced with its column
+func (h *StructSQL) getQueryCheck(check string) string {
+	reField := regexp.MustCompile(`\.[a-zA-Z0-9_]+`)
+	return reField.ReplaceAllStringFunc(check, func(f string) string {
+		// If field does not exist, it won't be processed
+		if h.dbFieldCols[f[1:]] == "" {
+			return f
+		}
+		return h.dbFieldCols[f[1:]]
+	})
+}
+
+// getQueryConflictWhere returns condition for ON CONFLICT DO UPDATE with '.Field' replaced with column of the
+// existing row and 'EXCLUDED.Field' replaced with column of the inserted row
+func (h *StructSQL) getQueryConflictWhere(where string) string {
+	reField := regexp.MustCompile(`(EXCLUDED)?\.[a-zA-Z0-9_]+`)
+	return reField.ReplaceAllStringFunc(where, func(f string) string {
+		fieldName := f[strings.Index(f, ".")+1:]
+
+		// If field does not exist, it won't be processed
+		if h.dbFieldCols[fieldName] == "" {
+			return f
+		}
+
+		if strings.HasPrefix(f, "EXCLUDED") {
+			return "EXCLUDED." + h.dbFieldCols[fieldName]
+		}
+		return h.dbTbl + "." + h.dbFieldCols[fieldName]
+	})
+}
+
+// numberPlaceholders replaces each question mark in a string with a placeholder, numbered from 'first'
+func (h *StructSQL) numberPlaceholders(s string, first int) string {
+	parts := strings.Split(s, "?")
+	o := parts[0]
+	for j := 1; j < len(parts); j++ {
+		o += h.placeholder.Render(first+j-1) + parts[j]
+	}
+	return o
+}
+
 func (h *StructSQL) addWithAnd(s string, v string) string {
 	if s != "" {
 		s += " AND "
@@ -468,7 +752,11 @@ func (h *StructSQL) getQueryOrder(order []string, orderFieldsToInclude map[strin
 	if len(order) > 0 {
 		for i := 0; i < len(order); i = i + 2 {
 			k := order[i]
-			v := order[i+1]
+			// Field at the end without direction is ascending
+			v := ""
+			if i+1 < len(order) {
+				v = order[i+1]
+			}
 
 			if len(orderFieldsToInclude) > 0 && !orderFieldsToInclude[k] && !orderFieldsToInclude[h.dbCols[k]] {
 				continue
@@ -513,6 +801,7 @@ func (h *StructSQL) getQuerySet(values map[string]interface{}, valueFieldsToIncl
 		return "", 0
 	}
 
+	// Sort values by their field names, the same way their bind parameters are sorted
 	sorted := []string{}
 	for k := range values {
 		if h.dbFieldCols[k] == "" {
@@ -521,16 +810,14 @@ func (h *StructSQL) getQuerySet(values map[string]interface{}, valueFieldsToIncl
 		if len(valueFieldsToInclude) > 0 && !valueFieldsToInclude[k] {
 			continue
 		}
-		sorted = append(sorted, h.dbFieldCols[k])
+		sorted = append(sorted, k)
 	}
+	sort.Strings(sorted)
 
-	if len(sorted) > 0 {
-		sort.Strings(sorted)
-
-		for _, col := range sorted {
-			qSet = h.addWithComma(qSet, fmt.Sprintf(col+"=$%d", i))
-			i++
-		}
+	for _, k := range sorted {
+		var cond string
+		cond, i = h.getFieldCondition(h.dbFieldCols[k], values[k], i)
+		qSet = h.addWithComma(qSet, cond)
 	}
 
 	return qSet, i - 1
@@ -552,9 +839,8 @@ func (h *StructSQL) getQueryFilters(filters map[string]interface{}, filterFields
 	}
 	sort.Strings(filterNames)
 
-	sorted := []string{}
 	for _, k := range filterNames {
-		if h.dbFieldCols[k] == "" {
+		if h.getFilterCol(k) == "" {
 			continue
 		}
 		if len(filterFieldsToInclude) > 0 && !filterFieldsToInclude[k] {
@@ -564,13 +850,26 @@ func (h *StructSQL) getQueryFilters(filters map[string]interface{}, filterFields
 		if k == "_raw" {
 			continue
 		}
-		sorted = append(sorted, h.dbFieldCols[k])
+		var cond string
+		cond, i = h.getFieldCondition(h.getFilterCol(k), filters[k], i)
+		qWhere = h.addWithAnd(qWhere, cond)
 	}
 
-	if len(sorted) > 0 {
-		for _, col := range sorted {
-			qWhere = h.addWithAnd(qWhere, fmt.Sprintf(col+"=$%d", i))
-			i++
+	if groups, ok := filters["_groups"].([]FilterGroup); ok {
+		for _, group := range groups {
+			var cond string
+			cond, i = h.getFilterGroupCondition(group, filterFieldsToInclude, i)
+			if cond != "" {
+				qWhere = h.addWithAnd(qWhere, "("+cond+")")
+			}
+		}
+	}
+
+	if keyset, ok := filters["_keyset"].(Keyset); ok {
+		var cond string
+		cond, i = h.getKeysetCondition(keyset, i)
+		if cond != "" {
+			qWhere = h.addWithAnd(qWhere, cond)
 		}
 	}
 
@@ -613,25 +912,29 @@ func (h *StructSQL) getQueryFilters(filters map[string]interface{}, filterFields
 			}
 		}
 
-		for j := 1; j < len(filters["_raw"].([]interface{})); j++ {
-			rt := reflect.TypeOf(filters["_raw"].([]interface{})[j])
+		// Question marks are replaced one by one with placeholders, eg. $2. When value is a slice, then its
+		// question mark is replaced with as many placeholders as there are items, eg. $3,$4,$5
+		rawValues := filters["_raw"].([]interface{})
+		rawParts := strings.Split(rawQuery, "?")
+		rawQuery = rawParts[0]
+		for j := 1; j < len(rawParts); j++ {
+			if j >= len(rawValues) {
+				rawQuery += "?" + rawParts[j]
+				continue
+			}
+
+			rt := reflect.TypeOf(rawValues[j])
 			if rt.Kind() == reflect.Slice || rt.Kind() == reflect.Array {
 				queryVal := ""
-				for k := 0; k < reflect.ValueOf(filters["_raw"].([]interface{})[j]).Len(); k++ {
-					if k == 0 {
-						queryVal += fmt.Sprintf("$%d", i)
-						i++
-						continue
-					}
-					queryVal += fmt.Sprintf(",$%d", i)
+				for k := 0; k < reflect.ValueOf(rawValues[j]).Len(); k++ {
+					queryVal = h.addWithComma(queryVal, h.placeholder.Render(i))
 					i++
 				}
-				rawQuery = strings.Replace(rawQuery, "?", queryVal, 1)
+				rawQuery += queryVal + rawParts[j]
 				continue
 			}
 
-			// Value is a single value so just replace ? with $x, eg $2
-			rawQuery = strings.Replace(rawQuery, "?", fmt.Sprintf("$%d", i), 1)
+			rawQuery += h.placeholder.Render(i) + rawParts[j]
 			i++
 		}
 
@@ -1,22 +1,38 @@
 package structsqlpostgres
 
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
 // StructSQL reflects the object to generate and cache PostgreSQL queries (CREATE TABLE, INSERT, UPDATE etc.).
 // Database table and column names are lowercase with underscore and they are generated from field names.
 // StructSQL is created within Controller and there is no need to instantiate it
 type StructSQL struct {
-	queryDropTable              string
-	queryCreateTable            string
-	queryInsert                 string
-	queryUpdateById             string
-	queryInsertOnConflictUpdate string
-	querySelectById             string
-	queryDeleteById             string
-	querySelectPrefix           string
-	querySelectCountPrefix      string
-	queryDeletePrefix           string
-	queryUpdatePrefix           string
+	queryDropTable                               string
+	queryCreateTable                             string
+	queryInsert                                  string
+	queryInsertWithID                            string
+	querySyncIDSequence                          string
+	queryUpdateById                              string
+	queryInsertOnConflictUpdate                  string
+	queryInsertOnConflictUpdateReturningInserted string
+	queryInsertOnConflictUpdatePrefix            string
+	queryInsertValues                            string
+	queryInsertWithIDValues                      string
+	querySelectById                              string
+	queryDeleteById                              string
+	queryExistsById                              string
+	querySelectPrefix                            string
+	querySelectCountPrefix                       string
+	queryDeletePrefix                            string
+	queryUpdatePrefix                            string
+	queryFrom                                    string
+	queryColumns                                 string
 
 	dbTbl       string
+	dbTblSuffix string
 	dbColPrefix string
 	dbFieldCols map[string]string
 	dbCols      map[string]string
@@ -27,6 +43,14 @@ type StructSQL struct {
 	fieldsUniq          map[string]bool
 	fieldsTags          map[string]map[string]string
 	fieldsOverwriteType map[string]string
+	fieldsComment       map[string]string
+	fieldsJSON          map[string]bool
+	fieldsRegexp        map[string]*regexp.Regexp
+	idField             string
+	pathField           string
+	createdAtField      string
+	updatedAtField      string
+	dbColTypes          map[string]string
 
 	flags int
 
@@ -39,6 +63,8 @@ type StructSQL struct {
 	err *ErrStructSQL
 
 	tagName string
+
+	placeholder Placeholder
 }
 
 const RawConjuctionOR = 1
@@ -46,6 +72,8 @@ const RawConjuctionAND = 2
 
 type StructSQLOptions struct {
 	DatabaseTablePrefix string
+	// DatabaseTableSuffix is added at the end of the table name, eg. '_v2' for 'persons_v2'. Column names do not change
+	DatabaseTableSuffix string
 	ForceName           string
 	TagName             string
 	Joined              map[string]*StructSQL
@@ -53,6 +81,8 @@ type StructSQLOptions struct {
 	Base *StructSQL
 	// When struct has a name like 'xx_yy' and it has joined structs, use 'xx' as a name for table and column names
 	UseRootNameWhenJoinedPresent bool
+	// Placeholder renders bind parameters in queries. PostgreSQL's $1, $2 etc. are used when it is not set
+	Placeholder Placeholder
 }
 
 // NewStructSQL takes object and database table name prefix as arguments and returns StructSQL instance.
@@ -64,6 +94,11 @@ func NewStructSQL(obj interface{}, options StructSQLOptions) *StructSQL {
 		h.tagName = options.TagName
 	}
 
+	h.placeholder = PlaceholderDollar{}
+	if options.Placeholder != nil {
+		h.placeholder = options.Placeholder
+	}
+
 	// Get field tags from Base StructSQL to use them instead of the ones parsed out from obj
 	h.setBaseTags(options.Base)
 
@@ -77,6 +112,7 @@ func NewStructSQL(obj interface{}, options StructSQLOptions) *StructSQL {
 	}
 	h.setJoinedTags()
 
+	h.dbTblSuffix = options.DatabaseTableSuffix
 	h.reflectStruct(obj, options.DatabaseTablePrefix, options.ForceName, options.UseRootNameWhenJoinedPresent)
 	return h
 }
@@ -110,6 +146,43 @@ func (h StructSQL) GetQueryCreateTable() string {
 	return h.queryCreateTable
 }
 
+// GetQueriesCommentColumns returns a COMMENT ON COLUMN query for each of the fields that have a comment in the
+// '2sql_comment' tag, eg. COMMENT ON COLUMN users.email IS 'Primary email', in the same order as fields are defined
+// in the struct. Single quotes in the comment are doubled. Nothing is returned when struct has joined structs.
+func (h StructSQL) GetQueriesCommentColumns() []string {
+	if h.hasJoined {
+		return nil
+	}
+
+	var queries []string
+	for _, fieldName := range h.fields {
+		comment, ok := h.fieldsComment[fieldName]
+		if !ok {
+			continue
+		}
+		queries = append(queries, fmt.Sprintf("COMMENT ON COLUMN %s.%s IS '%s'", h.dbTbl, h.dbFieldCols[fieldName], strings.ReplaceAll(comment, "'", "''")))
+	}
+	return queries
+}
+
+// GetQueryCreateView returns a CREATE VIEW query with view named the same as the table would be, defined by 'query'.
+func (h *StructSQL) GetQueryCreateView(query string) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return fmt.Sprintf("CREATE VIEW %s AS %s", h.dbTbl, query)
+}
+
+// GetQueryDropView returns a DROP VIEW query.
+func (h *StructSQL) GetQueryDropView() string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return fmt.Sprintf("DROP VIEW IF EXISTS %s", h.dbTbl)
+}
+
 // GetQueryInsert returns an INSERT query.
 // Columns in the INSERT query are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
 func (h *StructSQL) GetQueryInsert() string {
@@ -120,6 +193,83 @@ func (h *StructSQL) GetQueryInsert() string {
 	return h.queryInsert
 }
 
+// GetQueryInsertDefaultValues returns an INSERT query that creates a row with default values of all columns and
+// returns all of them.
+// Columns are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
+func (h *StructSQL) GetQueryInsertDefaultValues() string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES RETURNING %s", h.dbTbl, h.queryColumns)
+}
+
+// GetQueryInsertWithID returns an INSERT query that includes ID column, so that row gets the ID set in the object
+// instead of the one from sequence.
+// Columns in the INSERT query are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
+func (h *StructSQL) GetQueryInsertWithID() string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return h.queryInsertWithID
+}
+
+// GetQuerySyncIDSequence returns a query that sets ID column sequence to the highest ID in the table, so that next
+// inserted rows do not collide with the ones inserted with an explicit ID. On an empty table next ID is 1.
+func (h *StructSQL) GetQuerySyncIDSequence() string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return h.querySyncIDSequence
+}
+
+// GetQueryInsertOnConflictDoNothing returns an INSERT query that skips inserting the row when it violates a unique
+// constraint and returns ID only when row was inserted. ID column is inserted as well only when 'withID' is true.
+// Empty string is returned when struct has joined structs.
+func (h *StructSQL) GetQueryInsertOnConflictDoNothing(withID bool) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	s := h.queryInsertValues
+	if withID {
+		s = h.queryInsertWithIDValues
+	}
+	return fmt.Sprintf("%s ON CONFLICT DO NOTHING RETURNING %s", s, h.dbFieldCols[h.idField])
+}
+
+// GetQueryInsertMultiple returns an INSERT query that inserts 'rows' rows at once and returns their IDs in the same
+// order, eg. INSERT INTO t(a,b) VALUES ($1,$2),($3,$4) RETURNING id. ID column is inserted as well only when 'withID'
+// is true. Columns are ordered the same way as they are defined in the struct. Empty string is returned when struct
+// has joined structs.
+func (h *StructSQL) GetQueryInsertMultiple(rows int, withID bool) string {
+	if h.hasJoined || rows < 1 {
+		return ""
+	}
+
+	cols := ""
+	vals := ""
+	for _, fieldName := range h.fields {
+		if fieldName == h.idField && !withID {
+			continue
+		}
+		cols = h.addWithComma(cols, h.dbFieldCols[fieldName])
+		vals = h.addWithComma(vals, "?")
+	}
+	if cols == "" {
+		return ""
+	}
+
+	qValues := ""
+	for i := 0; i < rows; i++ {
+		qValues = h.addWithComma(qValues, "("+vals+")")
+	}
+
+	return fmt.Sprintf("INSERT INTO %s(%s) VALUES %s RETURNING %s", h.dbTbl, cols, h.numberPlaceholders(qValues, 1), h.dbFieldCols[h.idField])
+}
+
 // GetQueryUpdateById returns an UPDATE query with WHERE condition on ID field.
 // Columns in the UPDATE query are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
 func (h *StructSQL) GetQueryUpdateById() string {
@@ -140,12 +290,119 @@ func (h *StructSQL) GetQueryInsertOnConflictUpdate() string {
 	return h.queryInsertOnConflictUpdate
 }
 
+// GetQueryInsertOnConflictUpdateReturningInserted returns an "upsert" query, same as GetQueryInsertOnConflictUpdate, that
+// additionally returns a boolean column which is true when row was inserted and false when it was updated.
+func (h *StructSQL) GetQueryInsertOnConflictUpdateReturningInserted() string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return h.queryInsertOnConflictUpdateReturningInserted
+}
+
+// GetQueryInsertOnConflictUpdateColumnsReturningInserted returns an "upsert" query, same as
+// GetQueryInsertOnConflictUpdateReturningInserted, that on conflict updates only columns of specified fields, with
+// values from the insert. It returns empty string when any of the fields does not exist.
+// Columns in the query are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
+func (h *StructSQL) GetQueryInsertOnConflictUpdateColumnsReturningInserted(fieldNames []string) string {
+	if len(fieldNames) == 0 {
+		return ""
+	}
+
+	return h.GetQueryInsertOnConflictUpdateWhereReturningInserted(fieldNames, "")
+}
+
+// GetQueryInsertOnConflictUpdateWhereReturningInserted returns an "upsert" query, same as
+// GetQueryInsertOnConflictUpdateColumnsReturningInserted, with all fields (apart from the 'created_at' one, which
+// is also returned after the boolean column) updated when 'fieldNames' is empty, and
+// with the update done only when 'where' condition is true. In the condition, '.Field' is a column of the existing
+// row and 'EXCLUDED.Field' is a column of the inserted one, eg. 'EXCLUDED.UpdatedAt > .UpdatedAt'. When update is
+// not done, query does not return any row. It returns empty string when any of the fields does not exist.
+// Only trusted input can be used as 'where' as it is put into the query as it is.
+func (h *StructSQL) GetQueryInsertOnConflictUpdateWhereReturningInserted(fieldNames []string, where string) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	qSet := h.getQueryConflictSet(fieldNames)
+	if qSet == "" {
+		return ""
+	}
+
+	s := h.queryInsertOnConflictUpdatePrefix + " " + qSet
+	if where != "" {
+		s += " WHERE " + h.getQueryConflictWhere(where)
+	}
+	return s + h.getQueryReturningInserted()
+}
+
+// GetQueryInsertOnConflictConstraintUpdateReturningInserted returns an "upsert" query, same as
+// GetQueryInsertOnConflictUpdateWhereReturningInserted, with a constraint, eg. a unique one, as the conflict target
+// instead of ID, eg. 'ON CONFLICT ON CONSTRAINT uq_email'. When 'withID' is false, ID is not inserted and the
+// database assigns it. It returns empty string when any of the fields does not exist.
+// Only trusted input can be used as 'constraint' and 'where' as they are put into the query as they are.
+func (h *StructSQL) GetQueryInsertOnConflictConstraintUpdateReturningInserted(constraint string, withID bool, fieldNames []string, where string) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	qSet := h.getQueryConflictSet(fieldNames)
+	if qSet == "" {
+		return ""
+	}
+
+	s := h.queryInsertValues
+	if withID {
+		s = h.queryInsertWithIDValues
+	}
+	s += fmt.Sprintf(" ON CONFLICT ON CONSTRAINT %s DO UPDATE SET %s", constraint, qSet)
+	if where != "" {
+		s += " WHERE " + h.getQueryConflictWhere(where)
+	}
+	return s + h.getQueryReturningInserted()
+}
+
+// GetQueryToggleById returns an UPDATE query that negates value of bool field in a row with specific ID, and returns
+// the new value. It returns empty string when field does not exist.
+func (h *StructSQL) GetQueryToggleById(fieldName string) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	col := h.dbFieldCols[fieldName]
+	if col == "" || fieldName == h.idField {
+		return ""
+	}
+
+	return fmt.Sprintf("UPDATE %s SET %s=NOT %s WHERE %s = %s RETURNING %s", h.dbTbl, col, col, h.dbFieldCols[h.idField], h.placeholder.Render(1), col)
+}
+
+// GetQueryIncrementById returns an UPDATE query that adds a value to numeric field in a row with specific ID, and
+// returns the new value. It returns empty string when field does not exist.
+func (h *StructSQL) GetQueryIncrementById(fieldName string) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	col := h.dbFieldCols[fieldName]
+	if col == "" || fieldName == h.idField {
+		return ""
+	}
+
+	return fmt.Sprintf("UPDATE %s SET %s=%s+%s WHERE %s = %s RETURNING %s", h.dbTbl, col, col, h.placeholder.Render(1), h.dbFieldCols[h.idField], h.placeholder.Render(2), col)
+}
+
 // GetQuerySelectById returns a SELECT query with WHERE condition on ID field.
 // Columns in the SELECT query are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
 func (h *StructSQL) GetQuerySelectById() string {
 	return h.querySelectById
 }
 
+// GetQueryExistsById returns a SELECT query that returns true when there is a row with the ID.
+func (h *StructSQL) GetQueryExistsById() string {
+	return h.queryExistsById
+}
+
 // GetQueryDeleteById returns a DELETE query with WHERE condition on ID field.
 func (h *StructSQL) GetQueryDeleteById() string {
 	if h.hasJoined {
@@ -176,6 +433,90 @@ func (h *StructSQL) GetQuerySelect(order []string, limit int, offset int, filter
 	return s
 }
 
+// GetQueryLock returns a locking clause, ' FOR UPDATE' or ' FOR SHARE', that is added at the end of a SELECT query
+// to lock the selected rows until the end of transaction. When struct has joined ones, only rows of its own table are
+// locked. It returns empty string when neither forUpdate nor forShare is set, and FOR UPDATE wins when both are.
+func (h *StructSQL) GetQueryLock(forUpdate bool, forShare bool) string {
+	s := ""
+	if forUpdate {
+		s = " FOR UPDATE"
+	} else if forShare {
+		s = " FOR SHARE"
+	} else {
+		return ""
+	}
+	if h.hasJoined {
+		s += " OF t1"
+	}
+	return s
+}
+
+// GetQuerySelectColumn returns a SELECT query that gets only one column, with WHERE condition built from 'filters'
+// (field-value pairs), ORDER BY, LIMIT and OFFSET same as in GetQuerySelect. It returns empty string when field does not exist.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (h *StructSQL) GetQuerySelectColumn(fieldName string, order []string, limit int, offset int, filters map[string]interface{}, orderFieldsToInclude map[string]bool, filterFieldsToInclude map[string]bool) string {
+	col := h.dbFieldCols[fieldName]
+	if col == "" {
+		return ""
+	}
+
+	s := fmt.Sprintf("SELECT %s %s", col, h.queryFrom)
+
+	qOrder := h.getQueryOrder(order, orderFieldsToInclude)
+	qLimitOffset := h.getQueryLimitOffset(limit, offset)
+	qWhere := h.getQueryFilters(filters, filterFieldsToInclude, 1)
+
+	if qWhere != "" {
+		s += " WHERE " + qWhere
+	}
+	if qOrder != "" {
+		s += " ORDER BY " + qOrder
+	}
+	if qLimitOffset != "" {
+		s += " " + qLimitOffset
+	}
+	return s
+}
+
+// GetQuerySelectFields returns a SELECT query that gets only columns of the fields and of the ID field, with WHERE
+// condition built from 'filters' (field-value pairs), ORDER BY, LIMIT and OFFSET same as in GetQuerySelect. It returns
+// empty string when any of the fields does not exist.
+// Columns are ordered the same way as they are defined in the struct, regardless of the order of the fields.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (h *StructSQL) GetQuerySelectFields(fieldNames []string, order []string, limit int, offset int, filters map[string]interface{}, orderFieldsToInclude map[string]bool, filterFieldsToInclude map[string]bool) string {
+	fieldsToSelect := map[string]bool{h.idField: true}
+	for _, fieldName := range fieldNames {
+		if h.dbFieldCols[fieldName] == "" {
+			return ""
+		}
+		fieldsToSelect[fieldName] = true
+	}
+
+	cols := ""
+	for _, fieldName := range h.fields {
+		if fieldsToSelect[fieldName] {
+			cols = h.addWithComma(cols, h.dbFieldCols[fieldName])
+		}
+	}
+
+	s := fmt.Sprintf("SELECT %s %s", cols, h.queryFrom)
+
+	qOrder := h.getQueryOrder(order, orderFieldsToInclude)
+	qLimitOffset := h.getQueryLimitOffset(limit, offset)
+	qWhere := h.getQueryFilters(filters, filterFieldsToInclude, 1)
+
+	if qWhere != "" {
+		s += " WHERE " + qWhere
+	}
+	if qOrder != "" {
+		s += " ORDER BY " + qOrder
+	}
+	if qLimitOffset != "" {
+		s += " " + qLimitOffset
+	}
+	return s
+}
+
 // GetQuerySelectCount returns a SELECT COUNT(*) query to count rows with WHERE condition built from 'filters' (field-value pairs).
 // Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
 func (h *StructSQL) GetQuerySelectCount(filters map[string]interface{}, filterFieldsToInclude map[string]bool) string {
@@ -187,6 +528,81 @@ func (h *StructSQL) GetQuerySelectCount(filters map[string]interface{}, filterFi
 	return s
 }
 
+// GetQuerySelectExists returns a SELECT EXISTS query that returns true when there is any row matching WHERE condition
+// built from 'filters' (field-value pairs). Database stops at the first row, so it is cheaper than counting rows.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (h *StructSQL) GetQuerySelectExists(filters map[string]interface{}, filterFieldsToInclude map[string]bool) string {
+	s := "SELECT EXISTS(SELECT 1 " + h.queryFrom
+	qWhere := h.getQueryFilters(filters, filterFieldsToInclude, 1)
+	if qWhere != "" {
+		s += " WHERE " + qWhere
+	}
+	return s + ")"
+}
+
+// GetQuerySelectApproximateCount returns a SELECT query that gets estimated number of rows in the table from
+// PostgreSQL statistics, which is much faster than counting rows. Estimate is -1 when table has never been analyzed.
+func (h *StructSQL) GetQuerySelectApproximateCount() string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return fmt.Sprintf("SELECT reltuples::bigint AS cnt FROM pg_class WHERE oid = '%s'::regclass", h.dbTbl)
+}
+
+// GetQuerySelectCountTrueFalse returns a SELECT query that counts rows where bool field is true and rows where it
+// is false, with WHERE condition built from 'filters' (field-value pairs). It returns empty string when field does not exist.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (h *StructSQL) GetQuerySelectCountTrueFalse(fieldName string, filters map[string]interface{}, filterFieldsToInclude map[string]bool) string {
+	col := h.dbFieldCols[fieldName]
+	if col == "" {
+		return ""
+	}
+
+	s := fmt.Sprintf("SELECT COUNT(*) FILTER (WHERE %s) AS cnt_true,COUNT(*) FILTER (WHERE NOT %s) AS cnt_false %s", col, col, h.queryFrom)
+	qWhere := h.getQueryFilters(filters, filterFieldsToInclude, 1)
+	if qWhere != "" {
+		s += " WHERE " + qWhere
+	}
+	return s
+}
+
+// GetQuerySelectAggregate returns a SELECT query that gets value of an aggregate function, eg. SUM, on a field, with
+// WHERE condition built from 'filters' (field-value pairs). Value is NULL when there are no rows. It returns empty string
+// when field does not exist or the function is not supported.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (h *StructSQL) GetQuerySelectAggregate(fn AggregateFunc, fieldName string, filters map[string]interface{}, filterFieldsToInclude map[string]bool) string {
+	col := h.dbFieldCols[fieldName]
+	if col == "" || !fn.IsValid() {
+		return ""
+	}
+
+	s := fmt.Sprintf("SELECT %s(%s) AS agg %s", fn, col, h.queryFrom)
+	qWhere := h.getQueryFilters(filters, filterFieldsToInclude, 1)
+	if qWhere != "" {
+		s += " WHERE " + qWhere
+	}
+	return s
+}
+
+// GetQuerySelectFacet returns a SELECT query that counts rows for each distinct value of a field, ordered by count
+// from the highest, with WHERE condition built from 'filters' (field-value pairs). It returns empty string when field does not exist.
+// Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
+func (h *StructSQL) GetQuerySelectFacet(fieldName string, filters map[string]interface{}, filterFieldsToInclude map[string]bool) string {
+	col := h.dbFieldCols[fieldName]
+	if col == "" {
+		return ""
+	}
+
+	s := fmt.Sprintf("SELECT %s,COUNT(*) AS cnt %s", col, h.queryFrom)
+	qWhere := h.getQueryFilters(filters, filterFieldsToInclude, 1)
+	if qWhere != "" {
+		s += " WHERE " + qWhere
+	}
+	s += fmt.Sprintf(" GROUP BY %s ORDER BY cnt DESC,%s ASC", col, col)
+	return s
+}
+
 // GetQueryDelete return a DELETE query with WHERE condition built from 'filters' (field-value pairs).
 // Struct fields in 'filters' argument are sorted alphabetically. Hence, when used with database connection, their values (or pointers to it) must be sorted as well.
 func (h *StructSQL) GetQueryDelete(filters map[string]interface{}, filterFieldsToInclude map[string]bool) string {
@@ -214,7 +630,7 @@ func (h *StructSQL) GetQueryDeleteReturningID(filters map[string]interface{}, fi
 	if qWhere != "" {
 		s += " WHERE " + qWhere
 	}
-	s += " RETURNING " + h.dbFieldCols["ID"]
+	s += " RETURNING " + h.dbFieldCols[h.idField]
 	return s
 }
 
@@ -238,6 +654,110 @@ func (h *StructSQL) GetQueryUpdate(values map[string]interface{}, filters map[st
 	return s
 }
 
+// GetQueryUpdateFromValues returns an UPDATE query that sets 'fields' in 'rows' rows at once, with values taken from
+// a VALUES list and matched with table rows by ID, eg. UPDATE t SET a=v.a FROM (VALUES ($1::BIGINT,$2::BIGINT)) AS v(id,a) WHERE t.id = v.id.
+// Each row of values starts with ID, followed by values of fields in the same order as in 'fields'. Empty string is returned when
+// there is an invalid field or struct has joined structs.
+func (h *StructSQL) GetQueryUpdateFromValues(fields []string, rows int) string {
+	if h.hasJoined || len(fields) == 0 || rows < 1 {
+		return ""
+	}
+
+	idCol := h.dbFieldCols[h.idField]
+	qSet := ""
+	vCols := idCol
+	vVals := "?::" + h.dbColTypes[h.idField]
+	for _, f := range fields {
+		col := h.dbFieldCols[f]
+		if col == "" || f == h.idField {
+			return ""
+		}
+		qSet = h.addWithComma(qSet, fmt.Sprintf("%s=v.%s", col, col))
+		vCols = h.addWithComma(vCols, col)
+		vVals = h.addWithComma(vVals, "?::"+h.dbColTypes[f])
+	}
+
+	qValues := ""
+	for i := 0; i < rows; i++ {
+		qValues = h.addWithComma(qValues, "("+vVals+")")
+	}
+
+	return fmt.Sprintf("UPDATE %s SET %s FROM (VALUES %s) AS v(%s) WHERE %s.%s = v.%s", h.dbTbl, qSet, h.numberPlaceholders(qValues, 1), vCols, h.dbTbl, idCol, idCol)
+}
+
+// GetQueryUpdateReturning returns an UPDATE query, same as GetQueryUpdate, that returns all columns of updated rows.
+// Columns are ordered the same way as they are defined in the struct, eg. SELECT field1_column, field2_column, ... etc.
+func (h *StructSQL) GetQueryUpdateReturning(values map[string]interface{}, filters map[string]interface{}, valueFieldsToInclude map[string]bool, filterFieldsToInclude map[string]bool) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return h.GetQueryUpdate(values, filters, valueFieldsToInclude, filterFieldsToInclude) + " RETURNING " + h.queryColumns
+}
+
+// GetQueryUpdateReturningID returns an UPDATE query, same as GetQueryUpdate, that returns only IDs of updated rows.
+func (h *StructSQL) GetQueryUpdateReturningID(values map[string]interface{}, filters map[string]interface{}, valueFieldsToInclude map[string]bool, filterFieldsToInclude map[string]bool) string {
+	if h.hasJoined {
+		return ""
+	}
+
+	return h.GetQueryUpdate(values, filters, valueFieldsToInclude, filterFieldsToInclude) + " RETURNING " + h.dbFieldCols[h.idField]
+}
+
+// GetDBColFromFieldName returns table column from a field name. It returns empty string when field is not a column.
+func (h *StructSQL) GetDBColFromFieldName(n string) string {
+	return h.dbFieldCols[n]
+}
+
+// GetDBColTypeFromFieldName returns type of the table column of a field, eg. VARCHAR(255) or UUID, without constraints
+// and default value. It returns empty string when field is not a column.
+func (h *StructSQL) GetDBColTypeFromFieldName(n string) string {
+	return h.dbColTypes[n]
+}
+
+// GetFieldRegexp returns compiled pattern from the 'regexp' rule in the tag of a string field, or from a separate
+// tag, eg. '2sql_regexp', in which pattern can have spaces. It returns nil when field has no pattern.
+func (h *StructSQL) GetFieldRegexp(n string) *regexp.Regexp {
+	return h.fieldsRegexp[n]
+}
+
+// GetIDFieldName returns name of the primary key field, which is ID unless another field has the 'pk' tag.
+func (h *StructSQL) GetIDFieldName() string {
+	return h.idField
+}
+
+// GetFilterFieldName returns name of the field that a filter is for. Filter named ID is for the primary key field
+// when the struct has no field named ID, and other filters have the name of their field.
+func (h *StructSQL) GetFilterFieldName(n string) string {
+	if n == "ID" && h.dbFieldCols["ID"] == "" {
+		return h.idField
+	}
+	return n
+}
+
+// GetPathFieldName returns name of the field tagged with 'path', which contains a materialized path of the
+// object in a tree, eg. 'electronics.phones'. It returns empty string when there is no such field.
+func (h *StructSQL) GetPathFieldName() string {
+	return h.pathField
+}
+
+// GetCreatedAtFieldName returns name of the time.Time field tagged with 'created_at', which is set when object is
+// inserted. It returns empty string when there is no such field.
+func (h *StructSQL) GetCreatedAtFieldName() string {
+	return h.createdAtField
+}
+
+// GetUpdatedAtFieldName returns name of the time.Time field tagged with 'updated_at', which is set each time object
+// is saved. It returns empty string when there is no such field.
+func (h *StructSQL) GetUpdatedAtFieldName() string {
+	return h.updatedAtField
+}
+
+// IsPathLtree returns true when the path field is a PostgreSQL 'ltree' column, and false when it is a string one.
+func (h *StructSQL) IsPathLtree() bool {
+	return h.pathField != "" && h.fieldsOverwriteType[h.pathField] == "LTREE"
+}
+
 // GetFieldNameFromDBCol returns field name from a table column.
 func (h *StructSQL) GetFieldNameFromDBCol(n string) string {
 	return h.dbCols[n]
@@ -2,6 +2,7 @@ package structsqlpostgres
 
 import (
 	"testing"
+	"time"
 )
 
 // Test struct for all the tests
@@ -85,6 +86,332 @@ func TestSQLQueries(t *testing.T) {
 	}
 }
 
+func TestSQLByteSliceQueries(t *testing.T) {
+	type File struct {
+		ID      int64
+		Name    string
+		Content []byte
+	}
+	h := NewStructSQL(&File{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE files (file_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '',content BYTEA NOT NULL DEFAULT '')"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsert()
+	want = "INSERT INTO files(name,content) VALUES ($1,$2) RETURNING file_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLPathField(t *testing.T) {
+	type Category struct {
+		ID   int64
+		Name string
+		Path string `2sql:"path db_type:ltree"`
+	}
+	h := NewStructSQL(&Category{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE categories (category_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '',path LTREE NOT NULL DEFAULT '')"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	if h.GetPathFieldName() != "Path" || !h.IsPathLtree() {
+		t.Fatalf("Failed to set path field from tag")
+	}
+
+	type Folder struct {
+		ID   int64
+		Path string `2sql:"path"`
+	}
+	h = NewStructSQL(&Folder{}, StructSQLOptions{})
+	if h.GetPathFieldName() != "Path" || h.IsPathLtree() {
+		t.Fatalf("Failed to set string path field from tag")
+	}
+}
+
+func TestSQLCustomPrimaryKey(t *testing.T) {
+	type Person struct {
+		PersonID int64 `2sql:"pk"`
+		Name     string
+	}
+	h := NewStructSQL(&Person{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE persons (person_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '')"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsert()
+	want = "INSERT INTO persons(name) VALUES ($1) RETURNING person_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelect(nil, 0, 0, map[string]interface{}{
+		"ID":      5,
+		"_groups": []FilterGroup{{Conjunction: RawConjuctionOR, Filters: map[string]interface{}{"ID": 6, "Name": "x"}}},
+	}, nil, nil)
+	want = "SELECT person_id,name FROM persons WHERE person_id=$1 AND (person_id=$2 OR name=$3)"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	type Token struct {
+		Uuid  string `2sql:"pk"`
+		Name  string
+		Count int
+	}
+	h = NewStructSQL(&Token{}, StructSQLOptions{})
+
+	if h.GetIDFieldName() != "Uuid" {
+		t.Fatalf("Failed to set primary key field from tag")
+	}
+
+	got = h.GetQueryCreateTable()
+	want = "CREATE TABLE tokens (uuid VARCHAR(255) PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '',count BIGINT NOT NULL DEFAULT 0)"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectById()
+	want = "SELECT uuid,name,count FROM tokens WHERE uuid = $1"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertOnConflictUpdate()
+	want = "INSERT INTO tokens(uuid,name,count) VALUES ($1,$2,$3) ON CONFLICT (uuid) DO UPDATE SET name=$4,count=$5 RETURNING uuid"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryDeleteById()
+	want = "DELETE FROM tokens WHERE uuid = $1"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryExistsById()
+	want = "SELECT EXISTS(SELECT 1 FROM tokens WHERE uuid = $1)"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	type Session struct {
+		ID     string `2sql:"pk db_type:uuid"`
+		UserID string `2sql:"db_type:uuid"`
+	}
+	h = NewStructSQL(&Session{}, StructSQLOptions{})
+
+	got = h.GetQueryCreateTable()
+	want = "CREATE TABLE sessions (session_id UUID PRIMARY KEY,user_id UUID NOT NULL)"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	if h.GetDBColTypeFromFieldName("ID") != "UUID" || h.GetDBColTypeFromFieldName("UserID") != "UUID" {
+		t.Fatalf("Failed to return UUID column types")
+	}
+}
+
+func TestSQLTableSuffix(t *testing.T) {
+	type Person struct {
+		ID   int64
+		Name string
+	}
+	h := NewStructSQL(&Person{}, StructSQLOptions{DatabaseTablePrefix: "app_", DatabaseTableSuffix: "_v2"})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE app_persons_v2 (person_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '')"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectById()
+	want = "SELECT person_id,name FROM app_persons_v2 WHERE person_id = $1"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLJSONFields(t *testing.T) {
+	type Address struct {
+		City string
+	}
+	type Customer struct {
+		ID         int64
+		Name       string
+		Attributes map[string]interface{} `2sql:"json"`
+		Address    Address                `2sql:"json"`
+		Ignored    map[string]string
+	}
+	h := NewStructSQL(&Customer{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE customers (customer_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '',attributes JSONB NOT NULL DEFAULT '{}',address JSONB NOT NULL DEFAULT '{}')"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsert()
+	want = "INSERT INTO customers(name,attributes,address) VALUES ($1,$2,$3) RETURNING customer_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLNullableFields(t *testing.T) {
+	type Person struct {
+		ID       int64
+		Name     string
+		Nickname *string `2sql:"uniq"`
+		Age      *int64
+		BornAt   *time.Time
+	}
+	h := NewStructSQL(&Person{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE persons (person_id SERIAL PRIMARY KEY,name VARCHAR(255) NOT NULL DEFAULT '',nickname VARCHAR(255) UNIQUE,age BIGINT,born_at TIMESTAMPTZ)"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectById()
+	want = "SELECT person_id,name,nickname,age,born_at FROM persons WHERE person_id = $1"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLFieldRegexp(t *testing.T) {
+	type Airport struct {
+		ID   int64
+		Code string `2sql:"regexp:^[A-Z]{3}$"`
+		Name string `2sql:"regexp:^[a-z]+$" 2sql_regexp:"^[A-Z][a-z]+( [A-Z][a-z]+)*$"`
+		Size int    `2sql:"regexp:^[0-9]$"`
+	}
+	h := NewStructSQL(&Airport{}, StructSQLOptions{})
+	if h.Err() != nil {
+		t.Fatalf("NewStructSQL failed with an err: %s", h.Err())
+	}
+	if h.GetFieldRegexp("Code") == nil || !h.GetFieldRegexp("Code").MatchString("WAW") || h.GetFieldRegexp("Code").MatchString("waw") {
+		t.Fatalf("Want pattern of Code field, got %v", h.GetFieldRegexp("Code"))
+	}
+	if h.GetFieldRegexp("Name") == nil || !h.GetFieldRegexp("Name").MatchString("Warsaw Chopin") {
+		t.Fatalf("Want pattern of Name field from regexp tag, got %v", h.GetFieldRegexp("Name"))
+	}
+	if h.GetFieldRegexp("Size") != nil || h.GetFieldRegexp("ID") != nil {
+		t.Fatalf("Want no pattern of non-string fields")
+	}
+
+	type InvalidAirport struct {
+		ID   int64
+		Code string `2sql:"regexp:^[A-Z{3}$"`
+	}
+	h = NewStructSQL(&InvalidAirport{}, StructSQLOptions{})
+	if h.Err() == nil || h.Err().Op != "ParseTag" || h.Err().Tag != "regexp:^[A-Z{3}$" {
+		t.Fatalf("Want ParseTag error for invalid pattern, got %v", h.Err())
+	}
+}
+
+func TestSQLTimestampFields(t *testing.T) {
+	type Post struct {
+		ID        int64
+		Title     string
+		CreatedAt time.Time `2sql:"created_at"`
+		UpdatedAt time.Time `2sql:"updated_at"`
+		Edited    string    `2sql:"updated_at"`
+	}
+	h := NewStructSQL(&Post{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE posts (post_id SERIAL PRIMARY KEY,title VARCHAR(255) NOT NULL DEFAULT '',created_at TIMESTAMPTZ NOT NULL DEFAULT now(),updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),edited VARCHAR(255) NOT NULL DEFAULT '')"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	if h.GetCreatedAtFieldName() != "CreatedAt" || h.GetUpdatedAtFieldName() != "UpdatedAt" {
+		t.Fatalf("Failed to set timestamp fields from tags")
+	}
+
+	got = h.GetQueryInsertOnConflictUpdateWhereReturningInserted(nil, "")
+	want = "INSERT INTO posts(post_id,title,created_at,updated_at,edited) VALUES ($1,$2,$3,$4,$5)"
+	want += " ON CONFLICT (post_id) DO UPDATE SET title=EXCLUDED.title,updated_at=EXCLUDED.updated_at,edited=EXCLUDED.edited"
+	want += " RETURNING post_id,(xmax = 0) AS inserted,created_at"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLCommentColumnsQueries(t *testing.T) {
+	type User struct {
+		ID    int64  `2sql_comment:"Identifier"`
+		Email string `2sql:"req" 2sql_comment:"User's primary email"`
+		Name  string
+	}
+	h := NewStructSQL(&User{}, StructSQLOptions{})
+
+	got := h.GetQueriesCommentColumns()
+	want := []string{
+		"COMMENT ON COLUMN users.user_id IS 'Identifier'",
+		"COMMENT ON COLUMN users.email IS 'User''s primary email'",
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	h = NewStructSQL(testStructObj, StructSQLOptions{})
+	if len(h.GetQueriesCommentColumns()) != 0 {
+		t.Fatalf("Want no comment queries, got %v", h.GetQueriesCommentColumns())
+	}
+}
+
+func TestSQLCheckConstraints(t *testing.T) {
+	type Product struct {
+		ID       int64
+		Price    int
+		MinPrice int
+		_        struct{} `2sql_check:".Price >= 0"`
+		_        struct{} `2sql_check:".MinPrice <= .Price"`
+	}
+	h := NewStructSQL(&Product{}, StructSQLOptions{})
+
+	got := h.GetQueryCreateTable()
+	want := "CREATE TABLE products (product_id SERIAL PRIMARY KEY,price BIGINT NOT NULL DEFAULT 0,min_price BIGINT NOT NULL DEFAULT 0,CHECK (price >= 0),CHECK (min_price <= price))"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsert()
+	want = "INSERT INTO products(price,min_price) VALUES ($1,$2) RETURNING product_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLViewQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQueryCreateView("SELECT * FROM users")
+	want := "CREATE VIEW test_structs AS SELECT * FROM users"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryDropView()
+	want = "DROP VIEW IF EXISTS test_structs"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
 func TestSQLInsertQueries(t *testing.T) {
 	h := NewStructSQL(testStructObj, StructSQLOptions{})
 
@@ -93,6 +420,24 @@ func TestSQLInsertQueries(t *testing.T) {
 	if got != want {
 		t.Fatalf("Want %v, got %v", want, got)
 	}
+
+	got = h.GetQueryInsertDefaultValues()
+	want = "INSERT INTO test_structs DEFAULT VALUES RETURNING test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertWithID()
+	want = "INSERT INTO test_structs(test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13) RETURNING test_struct_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySyncIDSequence()
+	want = "SELECT setval(pg_get_serial_sequence('test_structs', 'test_struct_id'), COALESCE(MAX(test_struct_id), 0) + 1, false) FROM test_structs"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
 }
 
 func TestSQLUpdateByIdQueries(t *testing.T) {
@@ -105,6 +450,52 @@ func TestSQLUpdateByIdQueries(t *testing.T) {
 	}
 }
 
+func TestSQLInsertOnConflictDoNothingQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQueryInsertOnConflictDoNothing(false)
+	want := "INSERT INTO test_structs(test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key)"
+	want += " VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)"
+	want += " ON CONFLICT DO NOTHING RETURNING test_struct_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertOnConflictDoNothing(true)
+	want = "INSERT INTO test_structs(test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key)"
+	want += " VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)"
+	want += " ON CONFLICT DO NOTHING RETURNING test_struct_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLInsertMultipleQueries(t *testing.T) {
+	type Item struct {
+		ID   int64
+		Name string
+		Qty  int
+	}
+	h := NewStructSQL(&Item{}, StructSQLOptions{})
+
+	got := h.GetQueryInsertMultiple(3, false)
+	want := "INSERT INTO items(name,qty) VALUES ($1,$2),($3,$4),($5,$6) RETURNING item_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertMultiple(2, true)
+	want = "INSERT INTO items(item_id,name,qty) VALUES ($1,$2,$3),($4,$5,$6) RETURNING item_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertMultiple(0, false)
+	if got != "" {
+		t.Fatalf("Want empty string, got %v", got)
+	}
+}
+
 func TestSQLInsertOnConflictUpdateQueries(t *testing.T) {
 	h := NewStructSQL(testStructObj, StructSQLOptions{})
 
@@ -116,6 +507,102 @@ func TestSQLInsertOnConflictUpdateQueries(t *testing.T) {
 	if got != want {
 		t.Fatalf("Want %v, got %v", want, got)
 	}
+
+	got = h.GetQueryInsertOnConflictUpdateReturningInserted()
+	want += ",(xmax = 0) AS inserted"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertOnConflictUpdateColumnsReturningInserted([]string{"FirstName", "Age"})
+	want = "INSERT INTO test_structs(test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key)"
+	want += " VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)"
+	want += " ON CONFLICT (test_struct_id) DO UPDATE SET first_name=EXCLUDED.first_name,age=EXCLUDED.age"
+	want += " RETURNING test_struct_id,(xmax = 0) AS inserted"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertOnConflictUpdateColumnsReturningInserted([]string{"FirstName", "Missing"})
+	if got != "" {
+		t.Fatalf("Want empty string, got %v", got)
+	}
+
+	got = h.GetQueryInsertOnConflictUpdateWhereReturningInserted(nil, "EXCLUDED.Age > .Age")
+	want = "INSERT INTO test_structs(test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key)"
+	want += " VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)"
+	want += " ON CONFLICT (test_struct_id) DO UPDATE SET test_struct_flags=EXCLUDED.test_struct_flags,primary_email=EXCLUDED.primary_email,email_secondary=EXCLUDED.email_secondary,first_name=EXCLUDED.first_name,last_name=EXCLUDED.last_name,age=EXCLUDED.age,price=EXCLUDED.price,post_code=EXCLUDED.post_code,post_code2=EXCLUDED.post_code2,password=EXCLUDED.password,created_by_user_id=EXCLUDED.created_by_user_id,key=EXCLUDED.key"
+	want += " WHERE EXCLUDED.age > test_structs.age"
+	want += " RETURNING test_struct_id,(xmax = 0) AS inserted"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
+
+func TestSQLInsertOnConflictConstraintUpdateQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQueryInsertOnConflictConstraintUpdateReturningInserted("uq_key", false, []string{"FirstName", "Age"}, "")
+	want := "INSERT INTO test_structs(test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key)"
+	want += " VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)"
+	want += " ON CONFLICT ON CONSTRAINT uq_key DO UPDATE SET first_name=EXCLUDED.first_name,age=EXCLUDED.age"
+	want += " RETURNING test_struct_id,(xmax = 0) AS inserted"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertOnConflictConstraintUpdateReturningInserted("uq_key", true, []string{"Age"}, "EXCLUDED.Age > .Age")
+	want = "INSERT INTO test_structs(test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key)"
+	want += " VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)"
+	want += " ON CONFLICT ON CONSTRAINT uq_key DO UPDATE SET age=EXCLUDED.age"
+	want += " WHERE EXCLUDED.age > test_structs.age"
+	want += " RETURNING test_struct_id,(xmax = 0) AS inserted"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsertOnConflictConstraintUpdateReturningInserted("uq_key", false, []string{"Missing"}, "")
+	if got != "" {
+		t.Fatalf("Want empty string, got %v", got)
+	}
+}
+
+func TestSQLToggleByIdQueries(t *testing.T) {
+	type Account struct {
+		ID     int64
+		Active bool
+	}
+	h := NewStructSQL(&Account{}, StructSQLOptions{})
+
+	got := h.GetQueryToggleById("Active")
+	want := "UPDATE accounts SET active=NOT active WHERE account_id = $1 RETURNING active"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryToggleById("Missing")
+	if got != "" {
+		t.Fatalf("Want empty string, got %v", got)
+	}
+}
+
+func TestSQLIncrementByIdQueries(t *testing.T) {
+	type Product struct {
+		ID    int64
+		Stock int
+	}
+	h := NewStructSQL(&Product{}, StructSQLOptions{})
+
+	got := h.GetQueryIncrementById("Stock")
+	want := "UPDATE products SET stock=stock+$1 WHERE product_id = $2 RETURNING stock"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryIncrementById("Missing")
+	if got != "" {
+		t.Fatalf("Want empty string, got %v", got)
+	}
 }
 
 func TestSQLDeleteQueries(t *testing.T) {
@@ -190,6 +677,145 @@ func TestSQLSelectQueries(t *testing.T) {
 	}
 }
 
+func TestSQLSelectLockQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelect([]string{"Age", "desc"}, 10, 0, map[string]interface{}{"Price": 4444}, nil, nil) + h.GetQueryLock(false, true)
+	want := "SELECT test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key FROM test_structs WHERE price=$1 ORDER BY age DESC LIMIT 10 FOR SHARE"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	if h.GetQueryLock(true, false) != " FOR UPDATE" || h.GetQueryLock(false, false) != "" {
+		t.Fatalf("GetQueryLock returned invalid clause")
+	}
+
+	h = NewStructSQL(&Product_WithDetails{}, StructSQLOptions{ForceName: "Product"})
+	got = h.GetQueryLock(false, true)
+	want = " FOR SHARE OF t1"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLSelectQueriesWithRawValue(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelect(nil, 0, 0, map[string]interface{}{"Age": Raw("now()"), "PostCode2": "11-111", "Price": 4444}, nil, nil)
+	want := "SELECT test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key FROM test_structs"
+	want += " WHERE age=now() AND post_code2=$1 AND price=$2"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryUpdate(
+		map[string]interface{}{"Age": Raw("age+1"), "Price": 1234},
+		map[string]interface{}{"PrimaryEmail": "primary@example.com"},
+		nil,
+		nil,
+	)
+	want = "UPDATE test_structs SET age=age+1,price=$1 WHERE primary_email=$2"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLQueriesWithFilterOperators(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelect(nil, 0, 0, map[string]interface{}{"Age": Gt(18), "Price": Lte(4444), "PostCode": Ne("00-000"), "FirstName": "John"}, nil, nil)
+	want := "SELECT test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key FROM test_structs"
+	want += " WHERE age>$1 AND first_name=$2 AND post_code!=$3 AND price<=$4"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryDelete(map[string]interface{}{"Age": Lt(18), "Price": Gte(100)}, nil)
+	want = "DELETE FROM test_structs WHERE age<$1 AND price>=$2"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectCount(map[string]interface{}{"FirstName": Like("Jo%"), "LastName": ILike("%smith%")}, nil)
+	want = "SELECT COUNT(*) AS cnt FROM test_structs WHERE first_name LIKE $1 AND last_name ILIKE $2"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryUpdate(map[string]interface{}{"Price": 0}, map[string]interface{}{"Age": Gt(65)}, nil, nil)
+	want = "UPDATE test_structs SET price=$1 WHERE age>$2"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryDelete(map[string]interface{}{"Age": Gt(18), "FirstName": IsNull(), "LastName": IsNotNull(), "Price": 100}, nil)
+	want = "DELETE FROM test_structs WHERE age>$1 AND first_name IS NULL AND last_name IS NOT NULL AND price=$2"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLQueriesWithListFilters(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelect(nil, 0, 0, map[string]interface{}{"ID": []int64{1, 2, 3}, "Price": 4444}, nil, nil)
+	want := "SELECT test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key FROM test_structs"
+	want += " WHERE test_struct_id IN ($1,$2,$3) AND price=$4"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectCount(map[string]interface{}{"Age": []int{}, "Price": 4444}, nil)
+	want = "SELECT COUNT(*) AS cnt FROM test_structs WHERE 1=0 AND price=$1"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryUpdate(map[string]interface{}{"Price": 0}, map[string]interface{}{"FirstName": []string{"John", "Jane"}}, nil, nil)
+	want = "UPDATE test_structs SET price=$1 WHERE first_name IN ($2,$3)"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLQueriesWithFilterGroups(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelectCount(map[string]interface{}{
+		"Price": Gt(1),
+		"_groups": []FilterGroup{
+			{Conjunction: RawConjuctionOR, Filters: map[string]interface{}{"Age": 1, "FirstName": []string{"John", "Jane"}}},
+			{Filters: map[string]interface{}{"LastName": "Doe", "PostCode": 12}},
+		},
+	}, nil)
+	want := "SELECT COUNT(*) AS cnt FROM test_structs WHERE price>$1 AND (age=$2 OR first_name IN ($3,$4)) AND (last_name=$5 AND post_code=$6)"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLQueriesWithKeyset(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelect([]string{"Age", "desc", "ID", "desc"}, 10, 0, map[string]interface{}{
+		"Price":   444,
+		"_keyset": Keyset{Fields: []string{"Age", "ID"}, Values: []interface{}{30, 5}, Desc: true},
+	}, nil, nil)
+	want := "SELECT test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key FROM test_structs"
+	want += " WHERE price=$1 AND (age,test_struct_id)<($2,$3) ORDER BY age DESC,test_struct_id DESC LIMIT 10"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectCount(map[string]interface{}{
+		"_keyset": Keyset{Fields: []string{"ID"}, Values: []interface{}{5}},
+	}, nil)
+	want = "SELECT COUNT(*) AS cnt FROM test_structs WHERE (test_struct_id)>($1)"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
 func TestSQLSelectCountQueries(t *testing.T) {
 	h := NewStructSQL(testStructObj, StructSQLOptions{})
 
@@ -200,6 +826,139 @@ func TestSQLSelectCountQueries(t *testing.T) {
 	}
 }
 
+func TestSQLSelectExistsQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelectExists(map[string]interface{}{"Price": 4444, "PostCode2": "11-111"}, map[string]bool{"Price": true})
+	want := "SELECT EXISTS(SELECT 1 FROM test_structs WHERE price=$1)"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectExists(nil, nil)
+	want = "SELECT EXISTS(SELECT 1 FROM test_structs)"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLSelectApproximateCountQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelectApproximateCount()
+	want := "SELECT reltuples::bigint AS cnt FROM pg_class WHERE oid = 'test_structs'::regclass"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLSelectCountTrueFalseQueries(t *testing.T) {
+	type Account struct {
+		ID     int64
+		Active bool
+		Price  int
+	}
+	h := NewStructSQL(&Account{}, StructSQLOptions{})
+
+	got := h.GetQuerySelectCountTrueFalse("Active", map[string]interface{}{"Price": 4444}, nil)
+	want := "SELECT COUNT(*) FILTER (WHERE active) AS cnt_true,COUNT(*) FILTER (WHERE NOT active) AS cnt_false FROM accounts WHERE price=$1"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectCountTrueFalse("Missing", nil, nil)
+	if got != "" {
+		t.Fatalf("want empty string, got %v", got)
+	}
+}
+
+func TestSQLSelectAggregateQueries(t *testing.T) {
+	type Order struct {
+		ID     int64
+		Status string
+		Price  int
+	}
+	h := NewStructSQL(&Order{}, StructSQLOptions{})
+
+	got := h.GetQuerySelectAggregate(Sum, "Price", map[string]interface{}{"Status": "paid"}, nil)
+	want := "SELECT SUM(price) AS agg FROM orders WHERE status=$1"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectAggregate(Avg, "Price", nil, nil)
+	want = "SELECT AVG(price) AS agg FROM orders"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectAggregate(Max, "Missing", nil, nil)
+	if got != "" {
+		t.Fatalf("want empty string, got %v", got)
+	}
+
+	got = h.GetQuerySelectAggregate(AggregateFunc("COUNT(*); --"), "Price", nil, nil)
+	if got != "" {
+		t.Fatalf("want empty string, got %v", got)
+	}
+}
+
+func TestSQLSelectColumnQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelectColumn("ID", []string{"Age", "desc"}, 10, 20, map[string]interface{}{"Price": 4444}, nil, nil)
+	want := "SELECT test_struct_id FROM test_structs WHERE price=$1 ORDER BY age DESC LIMIT 10 OFFSET 20"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectColumn("ID", []string{"Age", "desc", "Price"}, 0, 0, nil, nil, nil)
+	want = "SELECT test_struct_id FROM test_structs ORDER BY age DESC,price ASC"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectColumn("Missing", nil, 0, 0, nil, nil, nil)
+	if got != "" {
+		t.Fatalf("want empty string, got %v", got)
+	}
+}
+
+func TestSQLSelectFieldsQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQuerySelectFields([]string{"Price", "FirstName"}, []string{"Age", "desc"}, 10, 20, map[string]interface{}{"Price": 4444}, nil, nil)
+	want := "SELECT test_struct_id,first_name,price FROM test_structs WHERE price=$1 ORDER BY age DESC LIMIT 10 OFFSET 20"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectFields([]string{"FirstName", "Missing"}, nil, 0, 0, nil, nil, nil)
+	if got != "" {
+		t.Fatalf("want empty string, got %v", got)
+	}
+}
+
+func TestSQLSelectFacetQueries(t *testing.T) {
+	type Product struct {
+		ID       int64
+		Category string
+		Price    int
+	}
+	h := NewStructSQL(&Product{}, StructSQLOptions{})
+
+	got := h.GetQuerySelectFacet("Category", map[string]interface{}{"Price": 4444}, nil)
+	want := "SELECT category,COUNT(*) AS cnt FROM products WHERE price=$1 GROUP BY category ORDER BY cnt DESC,category ASC"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQuerySelectFacet("Missing", nil, nil)
+	if got != "" {
+		t.Fatalf("want empty string, got %v", got)
+	}
+}
+
 func TestSQLDeleteWithFiltersQueries(t *testing.T) {
 	h := NewStructSQL(testStructObj, StructSQLOptions{})
 
@@ -276,6 +1035,79 @@ func TestSQLUpdateQueries(t *testing.T) {
 	if got != want {
 		t.Fatalf("want %v, got %v", want, got)
 	}
+
+	got = h.GetQueryUpdateReturning(
+		map[string]interface{}{"Price": 1234},
+		map[string]interface{}{"PrimaryEmail": "primary@example.com"},
+		nil,
+		nil,
+	)
+	want = "UPDATE test_structs SET price=$1 WHERE primary_email=$2 RETURNING test_struct_id,test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryUpdateReturningID(
+		map[string]interface{}{"Price": 1234},
+		map[string]interface{}{"PrimaryEmail": "primary@example.com"},
+		nil,
+		nil,
+	)
+	want = "UPDATE test_structs SET price=$1 WHERE primary_email=$2 RETURNING test_struct_id"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+}
+
+func TestSQLUpdateFromValuesQueries(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{})
+
+	got := h.GetQueryUpdateFromValues([]string{"Age", "Key"}, 2)
+	want := "UPDATE test_structs SET age=v.age,key=v.key FROM (VALUES ($1::BIGINT,$2::BIGINT,$3::VARCHAR(2000)),($4::BIGINT,$5::BIGINT,$6::VARCHAR(2000))) AS v(test_struct_id,age,key) WHERE test_structs.test_struct_id = v.test_struct_id"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryUpdateFromValues([]string{"Age", "Missing"}, 2)
+	if got != "" {
+		t.Fatalf("want empty query for invalid field, got %v", got)
+	}
+}
+
+func TestSQLQueriesWithQuestionPlaceholder(t *testing.T) {
+	h := NewStructSQL(testStructObj, StructSQLOptions{
+		Placeholder: PlaceholderQuestion{},
+	})
+
+	got := h.GetQueryDeleteById()
+	want := "DELETE FROM test_structs WHERE test_struct_id = ?"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryInsert()
+	want = "INSERT INTO test_structs(test_struct_flags,primary_email,email_secondary,first_name,last_name,age,price,post_code,post_code2,password,created_by_user_id,key) VALUES (?,?,?,?,?,?,?,?,?,?,?,?) RETURNING test_struct_id"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+
+	got = h.GetQueryUpdate(
+		map[string]interface{}{"Price": 1234, "PostCode2": "12-345"},
+		map[string]interface{}{
+			"PrimaryEmail": "primary@example.com",
+			"_raw": []interface{}{
+				".Price=? OR .Age IN (?)",
+				0,
+				[]int{0, 0, 0},
+			},
+		},
+		nil,
+		nil,
+	)
+	want = "UPDATE test_structs SET post_code2=?,price=? WHERE (primary_email=?) AND (price=? OR age IN (?,?,?))"
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
 }
 
 func TestPluralName(t *testing.T) {
@@ -377,3 +1209,27 @@ func TestSQLSelectQueriesWithJoin(t *testing.T) {
 	}
 
 }
+
+func TestSQLSelectQueriesWithJoinOnPrimaryKey(t *testing.T) {
+	type Warehouse struct {
+		WarehouseCode string `2sql:"pk"`
+		City          string
+	}
+	type Stock_WithWarehouse struct {
+		ID             int64
+		Quantity       int
+		WarehouseID    string
+		Warehouse      *Warehouse `2sql:"join"`
+		Warehouse_City string
+	}
+	h := NewStructSQL(&Stock_WithWarehouse{}, StructSQLOptions{
+		ForceName: "Stock",
+	})
+
+	got := h.GetQuerySelectById()
+	want := "SELECT t1.stock_id,t1.quantity,t1.warehouse_id,t2.city FROM stocks t1"
+	want += " INNER JOIN warehouses t2 ON t1.warehouse_id=t2.warehouse_code WHERE t1.stock_id = $1"
+	if got != want {
+		t.Fatalf("Want %v, got %v", want, got)
+	}
+}
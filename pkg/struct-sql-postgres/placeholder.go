@@ -0,0 +1,22 @@
+package structsqlpostgres
+
+import "fmt"
+
+// Placeholder renders a bind parameter for the n-th value in a query (starting from 1)
+type Placeholder interface {
+	Render(n int) string
+}
+
+// PlaceholderDollar renders PostgreSQL bind parameters, eg. $1, $2. It is used by default
+type PlaceholderDollar struct{}
+
+func (p PlaceholderDollar) Render(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// PlaceholderQuestion renders bind parameters as question marks, which are used by some other drivers
+type PlaceholderQuestion struct{}
+
+func (p PlaceholderQuestion) Render(n int) string {
+	return "?"
+}